@@ -0,0 +1,40 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"regexp"
+)
+
+// IsEmpty returns true if no key is allowed, neither as exact match nor by the regular expression.
+func (a AllowedListSpec) IsEmpty() bool {
+	return len(a.Allowed) == 0 && len(a.AllowedRegex) == 0
+}
+
+// IsAllowed returns true if the key is listed in the allowed ones, or matches the allowed regular expression.
+func (a AllowedListSpec) IsAllowed(key string) bool {
+	for _, allowed := range a.Allowed {
+		if allowed == key {
+			return true
+		}
+	}
+	if len(a.AllowedRegex) == 0 {
+		return false
+	}
+	matched, _ := regexp.MatchString(a.AllowedRegex, key)
+	return matched
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CapsuleConfigurationName is the name of the singleton CapsuleConfiguration read by Capsule, any other is ignored.
+const CapsuleConfigurationName = "default"
+
+// CapsuleConfigurationSpec defines the Capsule settings overriding the ones passed by the command line options, the
+// fields left empty falling back to the related option.
+type CapsuleConfigurationSpec struct {
+	// Names of the groups for the Capsule users, overriding the --capsule-user-group option
+	// +kubebuilder:validation:Optional
+	UserGroups []string `json:"userGroups,omitempty"`
+	// Enforces the Tenant owners to prefix the Namespaces with the Tenant name, overriding the --force-tenant-prefix option
+	// +kubebuilder:validation:Optional
+	ForceTenantPrefix *bool `json:"forceTenantPrefix,omitempty"`
+	// Disallows the creation of the Namespaces whose name matches this regexp, overriding the --protected-namespace-regex option
+	// +kubebuilder:validation:Optional
+	ProtectedNamespaceRegexpString string `json:"protectedNamespaceRegex,omitempty"`
+	// The validity of the generated Capsule CA, overriding the --ca-validity option
+	// +kubebuilder:validation:Optional
+	CaValidity *metav1.Duration `json:"caValidity,omitempty"`
+	// The validity of the generated webhook TLS certificate, overriding the --tls-validity option
+	// +kubebuilder:validation:Optional
+	TlsValidity *metav1.Duration `json:"tlsValidity,omitempty"`
+	// The percentage of the remaining certificate lifetime triggering the renewal, overriding the --renew-before-percentage option
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	RenewBeforePercentage *uint `json:"renewBeforePercentage,omitempty"`
+	// Names of the break-glass groups, whose requests are allowed by the validating webhooks even when denied by the
+	// Tenant policies or the Capsule protections, and who can assign the Namespaces to any Tenant, overriding the
+	// --break-glass-group option
+	// +kubebuilder:validation:Optional
+	BreakGlassGroups []string `json:"breakGlassGroups,omitempty"`
+	// The failure policy of the Capsule webhooks by their name, as quota.namespace.capsule.clastix.io: the webhooks
+	// not listed get back the policy they have been installed with
+	// +kubebuilder:validation:Optional
+	WebhookFailurePolicies map[string]FailurePolicy `json:"webhookFailurePolicies,omitempty"`
+}
+
+// FailurePolicy defines how the API server handles the requests when a Capsule webhook cannot be called.
+// +kubebuilder:validation:Enum=Fail;Ignore
+type FailurePolicy string
+
+const (
+	FailurePolicyFail   FailurePolicy = "Fail"
+	FailurePolicyIgnore FailurePolicy = "Ignore"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// CapsuleConfiguration is the Schema for the Capsule configuration API
+type CapsuleConfiguration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CapsuleConfigurationSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CapsuleConfigurationList contains a list of CapsuleConfiguration
+type CapsuleConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CapsuleConfiguration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CapsuleConfiguration{}, &CapsuleConfigurationList{})
+}
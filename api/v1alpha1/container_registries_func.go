@@ -0,0 +1,37 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"regexp"
+)
+
+// IsImageAllowed returns true if the image, normalized with its explicit registry, can be used by the Tenant: the
+// registry hostname is checked against the allowed list, the whole image against the pattern.
+func (c ContainerRegistriesSpec) IsImageAllowed(registry, image string) bool {
+	if len(c.Allowed) == 0 && len(c.AllowedRegex) == 0 {
+		return true
+	}
+	if c.Allowed.IsStringInList(registry) {
+		return true
+	}
+	if len(c.AllowedRegex) > 0 {
+		matched, _ := regexp.MatchString(c.AllowedRegex, image)
+		return matched
+	}
+	return false
+}
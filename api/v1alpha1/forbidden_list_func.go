@@ -0,0 +1,40 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"regexp"
+)
+
+// ExactMatch returns true if the key is listed in the denied ones.
+func (f ForbiddenListSpec) ExactMatch(key string) bool {
+	for _, denied := range f.Denied {
+		if denied == key {
+			return true
+		}
+	}
+	return false
+}
+
+// RegexMatch returns true if the key matches the denied regular expression, if any.
+func (f ForbiddenListSpec) RegexMatch(key string) bool {
+	if len(f.DeniedRegex) == 0 {
+		return false
+	}
+	matched, _ := regexp.MatchString(f.DeniedRegex, key)
+	return matched
+}
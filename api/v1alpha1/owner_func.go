@@ -0,0 +1,34 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+)
+
+const serviceAccountPrefix = "system:serviceaccount:"
+
+// GetServiceAccountNamespacedName returns the Namespace and the name of a ServiceAccount owner, whose name must be in
+// the system:serviceaccount:<namespace>:<name> form.
+func (o OwnerSpec) GetServiceAccountNamespacedName() (namespace string, name string, err error) {
+	parts := strings.Split(strings.TrimPrefix(o.Name, serviceAccountPrefix), ":")
+	if !strings.HasPrefix(o.Name, serviceAccountPrefix) || len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", fmt.Errorf("ServiceAccount owner name %s must be in the %s<namespace>:<name> form", o.Name, serviceAccountPrefix)
+	}
+	return parts[0], parts[1], nil
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -25,8 +27,24 @@ const (
 	AvailableIngressClassesRegexpAnnotation = "capsule.clastix.io/ingress-classes-regexp"
 	AvailableStorageClassesAnnotation       = "capsule.clastix.io/storage-classes"
 	AvailableStorageClassesRegexpAnnotation = "capsule.clastix.io/storage-classes-regexp"
+	AppliedLabelsAnnotation                 = "capsule.clastix.io/applied-labels"
+	AppliedAnnotationsAnnotation            = "capsule.clastix.io/applied-annotations"
+	AppliedResourcesAnnotation              = "capsule.clastix.io/applied-resources"
+	DeletionProtectionAnnotation            = "capsule.clastix.io/deletion-protection"
+	OwnerKindChangeAnnotation               = "capsule.clastix.io/owner-kind-change"
+	APIVersionAnnotation                    = "capsule.clastix.io/api-version"
+	TenantAssignmentAnnotation              = "capsule.clastix.io/tenant"
+	QuotaOverrideAnnotationPrefix           = "quota.capsule.clastix.io/"
+	QuotaOverrideAnnotationSuffix           = "-override"
 )
 
+// UsedQuotaFor returns the ResourceQuota annotation tracking the Tenant usage of the resource: the slash of the
+// fully-qualified ones, as requests.nvidia.com/gpu, is replaced since not allowed in the annotation name.
 func UsedQuotaFor(resource corev1.ResourceName) string {
-	return "quota.capsule.clastix.io/used-" + resource.String()
+	return "quota.capsule.clastix.io/used-" + strings.ReplaceAll(resource.String(), "/", "_")
+}
+
+// QuotaOverrideFor returns the Namespace annotation overriding the hard quota of the resource, within the Tenant one.
+func QuotaOverrideFor(resource corev1.ResourceName) string {
+	return QuotaOverrideAnnotationPrefix + strings.ReplaceAll(resource.String(), "/", "_") + QuotaOverrideAnnotationSuffix
 }
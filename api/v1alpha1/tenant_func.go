@@ -18,12 +18,86 @@ package v1alpha1
 
 import (
 	"sort"
+	"time"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// namespaceReservationTimeout is the time a reservation is held for a Namespace that has not been created, as denied
+// by a further admission webhook.
+const namespaceReservationTimeout = 30 * time.Second
+
+// IsFull returns true when the collected Namespaces and the pending reservations reached the Namespace quota.
 func (t *Tenant) IsFull() bool {
-	return t.Status.Namespaces.Len() >= int(t.Spec.NamespaceQuota)
+	count := t.Status.Namespaces.Len()
+	for _, r := range t.Status.Reservations {
+		if !t.Status.Namespaces.IsStringInList(r.Name) {
+			count++
+		}
+	}
+	return count >= int(t.Spec.NamespaceQuota)
+}
+
+// ReserveNamespace holds a slot of the Namespace quota for the Namespace being created, returning false if the
+// Tenant is full: the caller must persist the status with optimistic concurrency to make the reservation atomic.
+func (t *Tenant) ReserveNamespace(name string) bool {
+	t.PruneReservations()
+	if t.Status.Namespaces.IsStringInList(name) {
+		return true
+	}
+	if t.IsFull() {
+		return false
+	}
+	t.Status.Reservations = append(t.Status.Reservations, NamespaceReservation{
+		Name:      name,
+		Timestamp: metav1.Now(),
+	})
+	return true
+}
+
+// PruneReservations drops the reservations of the collected Namespaces, along with the expired ones.
+func (t *Tenant) PruneReservations() {
+	var l []NamespaceReservation
+	for _, r := range t.Status.Reservations {
+		if t.Status.Namespaces.IsStringInList(r.Name) || time.Since(r.Timestamp.Time) > namespaceReservationTimeout {
+			continue
+		}
+		l = append(l, r)
+	}
+	t.Status.Reservations = l
+}
+
+// GetOwners returns the primary Tenant owner followed by the additional ones, skipping the duplicates.
+func (t *Tenant) GetOwners() (owners []OwnerSpec) {
+	seen := make(map[OwnerSpec]struct{})
+	for _, o := range append([]OwnerSpec{t.Spec.Owner}, t.Spec.Owners...) {
+		if _, ok := seen[o]; ok || len(o.Name) == 0 {
+			continue
+		}
+		seen[o] = struct{}{}
+		owners = append(owners, o)
+	}
+	return
+}
+
+// IsOwner returns true if the user is one of the Tenant owners, either by name, as User or ServiceAccount, or by
+// group membership.
+func (t *Tenant) IsOwner(userInfo authenticationv1.UserInfo) bool {
+	for _, o := range t.GetOwners() {
+		if (o.Kind == "User" || o.Kind == "ServiceAccount") && userInfo.Username == o.Name {
+			return true
+		}
+		if o.Kind == "Group" {
+			for _, group := range userInfo.Groups {
+				if group == o.Name {
+					return true
+				}
+			}
+		}
+	}
+	return false
 }
 
 func (t *Tenant) AssignNamespaces(namespaces []corev1.Namespace) {
@@ -38,3 +112,103 @@ func (t *Tenant) AssignNamespaces(namespaces []corev1.Namespace) {
 	t.Status.Namespaces = l
 	t.Status.Size = uint(len(l))
 }
+
+// AssignState sets the Tenant state according to the spec, as the cordoned one.
+func (t *Tenant) AssignState() {
+	t.Status.State = TenantStateActive
+	if t.Spec.Cordoned {
+		t.Status.State = TenantStateCordoned
+	}
+}
+
+// SetCondition adds or replaces the Tenant condition with the same type, retaining the last transition time unless
+// the status changed.
+func (t *Tenant) SetCondition(condition TenantCondition) {
+	for i, c := range t.Status.Conditions {
+		if c.Type != condition.Type {
+			continue
+		}
+		if c.Status == condition.Status {
+			condition.LastTransitionTime = c.LastTransitionTime
+		}
+		t.Status.Conditions[i] = condition
+		return
+	}
+	t.Status.Conditions = append(t.Status.Conditions, condition)
+}
+
+// IsNodePortsEnabled returns true unless the NodePort Services are explicitly disabled for the Tenant.
+func (t *Tenant) IsNodePortsEnabled() bool {
+	return t.Spec.EnableNodePorts == nil || *t.Spec.EnableNodePorts
+}
+
+// IsLoadBalancersEnabled returns true unless the LoadBalancer Services are explicitly disabled for the Tenant.
+func (t *Tenant) IsLoadBalancersEnabled() bool {
+	return t.Spec.EnableLoadBalancers == nil || *t.Spec.EnableLoadBalancers
+}
+
+// IsServiceTypeAllowed returns true if the Service type is listed in the Tenant allowed ones, or none is listed.
+func (t *Tenant) IsServiceTypeAllowed(serviceType corev1.ServiceType) bool {
+	if t.Spec.ServiceOptions == nil || len(t.Spec.ServiceOptions.AllowedTypes) == 0 {
+		return true
+	}
+	for _, allowed := range t.Spec.ServiceOptions.AllowedTypes {
+		if string(allowed) == string(serviceType) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsImagePullPolicyAllowed returns true if the image pull policy is listed in the Tenant allowed ones, or none is listed.
+func (t *Tenant) IsImagePullPolicyAllowed(policy corev1.PullPolicy) bool {
+	if len(t.Spec.ImagePullPolicies) == 0 {
+		return true
+	}
+	for _, allowed := range t.Spec.ImagePullPolicies {
+		if string(allowed) == string(policy) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNamespaceMetadataEditable returns true if the Tenant owners can change any Namespace label or annotation, thus
+// granted the Namespace update.
+func (t *Tenant) IsNamespaceMetadataEditable() bool {
+	options := t.Spec.NamespaceOptions
+	return options != nil && (!options.AllowedLabels.IsEmpty() || !options.AllowedAnnotations.IsEmpty())
+}
+
+// IsRoleBindingRestricted returns true if the Tenant users can bind the allowed ClusterRoles only, even when none is
+// listed.
+func (t *Tenant) IsRoleBindingRestricted() bool {
+	return t.Spec.UserResources != nil && t.Spec.UserResources.RestrictRoleBindings
+}
+
+// GetUserResourcePolicy returns how the ResourceQuotas or LimitRanges created by the Tenant users are handled, allowed
+// unless set.
+func (t *Tenant) GetUserResourcePolicy(kind string) (policy UserResourcePolicy) {
+	if t.Spec.UserResources != nil {
+		switch kind {
+		case "ResourceQuota":
+			policy = t.Spec.UserResources.ResourceQuotas
+		case "LimitRange":
+			policy = t.Spec.UserResources.LimitRanges
+		}
+	}
+	if len(policy) == 0 {
+		policy = UserResourcePolicyAllow
+	}
+	return
+}
+
+// GetContainerRegistries returns the registries the Tenant Pods can pull images from, falling back to the deprecated
+// registryClasses of the Tenants not updated since their renaming.
+func (t *Tenant) GetContainerRegistries() ContainerRegistriesSpec {
+	spec := t.Spec.ContainerRegistries
+	if rc := t.Spec.RegistryClasses; rc != nil && len(spec.Allowed) == 0 && len(spec.AllowedRegex) == 0 {
+		spec.Allowed, spec.AllowedRegex = rc.Allowed, rc.AllowedRegex
+	}
+	return spec
+}
@@ -21,9 +21,19 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+const (
+	ManagedByLabel          = "capsule.clastix.io/managed-by"
+	AdditionalResourceLabel = "capsule.clastix.io/additional-resource"
+	ProtectedResourceLabel  = "capsule.clastix.io/protected-resource"
+	ImagePullSecretLabel    = "capsule.clastix.io/image-pull-secret"
+	ExcludedNamespaceLabel  = "capsule.clastix.io/exclude"
+	UserResourceLabel       = "capsule.clastix.io/user-resource"
+)
+
 func GetTypeLabel(t runtime.Object) (label string, err error) {
 	switch v := t.(type) {
 	case *Tenant:
@@ -34,6 +44,8 @@ func GetTypeLabel(t runtime.Object) (label string, err error) {
 		return "capsule.clastix.io/network-policy", nil
 	case *corev1.ResourceQuota:
 		return "capsule.clastix.io/resource-quota", nil
+	case *rbacv1.RoleBinding:
+		return "capsule.clastix.io/role-binding", nil
 	default:
 		err = fmt.Errorf("type %T is not mapped as Capsule label recognized", v)
 	}
@@ -19,7 +19,10 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +kubebuilder:validation:Minimum=1
@@ -37,6 +40,15 @@ type StorageClassesSpec struct {
 	Allowed StorageClassList `json:"allowed"`
 	// +nullable
 	AllowedRegex string `json:"allowedRegex"`
+	// StorageClass assigned to the PersistentVolumeClaims not specifying one, it must be allowed
+	// +kubebuilder:validation:Optional
+	Default string `json:"default,omitempty"`
+	// Replace the empty StorageClass with the default one, otherwise kept to bind the pre-provisioned PersistentVolumes
+	// +kubebuilder:validation:Optional
+	DefaultOnEmpty bool `json:"defaultOnEmpty,omitempty"`
+	// Deny the PersistentVolumeClaims not specifying a StorageClass, rather than relying on the cluster default one
+	// +kubebuilder:validation:Optional
+	RequireExplicitClass bool `json:"requireExplicitClass,omitempty"`
 }
 
 type IngressClassesSpec struct {
@@ -44,8 +56,46 @@ type IngressClassesSpec struct {
 	Allowed IngressClassList `json:"allowed"`
 	// +nullable
 	AllowedRegex string `json:"allowedRegex"`
+	// IngressClass assigned to the Ingresses not specifying one, it must be allowed
+	// +kubebuilder:validation:Optional
+	Default string `json:"default,omitempty"`
+}
+
+type IngressHostnamesSpec struct {
+	// +nullable
+	Allowed IngressHostnamesList `json:"allowed"`
+	// +nullable
+	AllowedRegex string `json:"allowedRegex"`
+}
+
+type ClusterRolesSpec struct {
+	// +nullable
+	Allowed ClusterRoleList `json:"allowed"`
+	// +nullable
+	AllowedRegex string `json:"allowedRegex"`
+}
+
+type PriorityClassesSpec struct {
+	// +nullable
+	Allowed PriorityClassList `json:"allowed"`
+	// +nullable
+	AllowedRegex string `json:"allowedRegex"`
+	// Deny the cluster default PriorityClass, otherwise implicitly allowed, unless explicitly listed
+	// +kubebuilder:validation:Optional
+	DenyDefault bool `json:"denyDefault,omitempty"`
+}
+
+type ContainerRegistriesSpec struct {
+	// +nullable
+	Allowed RegistryList `json:"allowed"`
+	// +nullable
+	AllowedRegex string `json:"allowedRegex"`
+	// Registry prefixed to the images without an explicit one, unless they're already allowed
+	// +kubebuilder:validation:Optional
+	DefaultRegistry string `json:"defaultRegistry,omitempty"`
 }
 
+// RegistryClassesSpec is the former ContainerRegistriesSpec, kept for the Tenants created before its renaming.
 type RegistryClassesSpec struct {
 	// +nullable
 	Allowed RegistryList `json:"allowed"`
@@ -53,44 +103,334 @@ type RegistryClassesSpec struct {
 	AllowedRegex string `json:"allowedRegex"`
 }
 
+type HostPortRange struct {
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Min int32 `json:"min"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Max int32 `json:"max"`
+}
+
+type PodSecuritySpec struct {
+	// +kubebuilder:validation:Optional
+	AllowHostNetwork bool `json:"allowHostNetwork,omitempty"`
+	// +kubebuilder:validation:Optional
+	AllowHostPID bool `json:"allowHostPID,omitempty"`
+	// +kubebuilder:validation:Optional
+	AllowHostIPC bool `json:"allowHostIPC,omitempty"`
+	// +kubebuilder:validation:Optional
+	AllowHostPorts bool `json:"allowHostPorts,omitempty"`
+	// Range the allowed host ports must belong to, any one if missing
+	// +kubebuilder:validation:Optional
+	HostPortRange *HostPortRange `json:"hostPortRange,omitempty"`
+	// +kubebuilder:validation:Optional
+	AllowPrivilegedContainers bool `json:"allowPrivilegedContainers,omitempty"`
+	// +kubebuilder:validation:Optional
+	AllowPrivilegeEscalation bool `json:"allowPrivilegeEscalation,omitempty"`
+	// Allow the hostPath volumes, defaulted to false for the new Tenants and to true for the ones created before
+	// +kubebuilder:validation:Optional
+	AllowHostPath *bool `json:"allowHostPath,omitempty"`
+	// CSI drivers the inline ephemeral volumes can use, all allowed if none is listed
+	// +kubebuilder:validation:Optional
+	AllowedCSIDrivers []string `json:"allowedCSIDrivers,omitempty"`
+}
+
+// UserResourcesSpec restricts the ResourceQuotas, LimitRanges, and RoleBindings the Tenant users create in the Tenant
+// Namespaces, besides the ones managed by Capsule: each restriction is enforced on its own.
+type UserResourcesSpec struct {
+	// Deny the RoleBindings referring a ClusterRole not allowed by clusterRoles, even when none is listed
+	// +kubebuilder:validation:Optional
+	RestrictRoleBindings bool `json:"restrictRoleBindings,omitempty"`
+	// How the ResourceQuotas created by the Tenant users are handled, allowed if missing
+	// +kubebuilder:validation:Optional
+	ResourceQuotas UserResourcePolicy `json:"resourceQuotas,omitempty"`
+	// How the LimitRanges created by the Tenant users are handled, allowed if missing
+	// +kubebuilder:validation:Optional
+	LimitRanges UserResourcePolicy `json:"limitRanges,omitempty"`
+}
+
+// UserResourcePolicy is how the objects created by the Tenant users are handled: Allow, Deny, or Label, allowing them
+// labelled with the capsule.clastix.io/user-resource label.
+// +kubebuilder:validation:Enum=Allow;Deny;Label
+type UserResourcePolicy string
+
+const (
+	UserResourcePolicyAllow UserResourcePolicy = "Allow"
+	UserResourcePolicyDeny  UserResourcePolicy = "Deny"
+	UserResourcePolicyLabel UserResourcePolicy = "Label"
+)
+
+type ExternalServiceIPsSpec struct {
+	// CIDRs the Service external IPs must belong to
+	Allowed []string `json:"allowed"`
+}
+
+type ServiceOptions struct {
+	// Service types the Tenant can create, all allowed if none is listed
+	// +kubebuilder:validation:Optional
+	AllowedTypes []ServiceType `json:"allowedTypes,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer;ExternalName
+type ServiceType string
+
+// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+type ImagePullPolicySpec string
+
+type PodDisruptionBudgetSpec struct {
+	// Minimum number or percentage of the workload Pods that must be available after an eviction
+	// +kubebuilder:validation:Optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// Maximum number or percentage of the workload Pods that can be unavailable after an eviction
+	// +kubebuilder:validation:Optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+type ForbiddenListSpec struct {
+	// Keys denied as exact match
+	// +kubebuilder:validation:Optional
+	Denied []string `json:"denied,omitempty"`
+	// Keys denied if matching the regular expression
+	// +kubebuilder:validation:Optional
+	DeniedRegex string `json:"deniedRegex,omitempty"`
+}
+
+type AllowedListSpec struct {
+	// Keys allowed as exact match
+	// +kubebuilder:validation:Optional
+	Allowed []string `json:"allowed,omitempty"`
+	// Keys allowed if matching the regular expression
+	// +kubebuilder:validation:Optional
+	AllowedRegex string `json:"allowedRegex,omitempty"`
+}
+
+type NamespaceOptions struct {
+	// Labels the Tenant users cannot set on the Namespaces
+	// +kubebuilder:validation:Optional
+	ForbiddenLabels ForbiddenListSpec `json:"forbiddenLabels,omitempty"`
+	// Annotations the Tenant users cannot set on the Namespaces
+	// +kubebuilder:validation:Optional
+	ForbiddenAnnotations ForbiddenListSpec `json:"forbiddenAnnotations,omitempty"`
+	// Labels the Tenant owners can change on the Namespaces, granting them the Namespace update
+	// +kubebuilder:validation:Optional
+	AllowedLabels AllowedListSpec `json:"allowedLabels,omitempty"`
+	// Annotations the Tenant owners can change on the Namespaces, granting them the Namespace update
+	// +kubebuilder:validation:Optional
+	AllowedAnnotations AllowedListSpec `json:"allowedAnnotations,omitempty"`
+}
+
+type AdditionalRoleBindings struct {
+	ClusterRoleName string           `json:"clusterRoleName"`
+	Subjects        []rbacv1.Subject `json:"subjects"`
+}
+
+type ImagePullSecretSpec struct {
+	// Namespace of the source Secret
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+	// Name of the source Secret, kept by the replicas
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+type AdditionalResourceSpec struct {
+	// Key of the object in the Tenant, the changes to the object kind or name replace it
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Deny the changes and the deletion of the object to the Tenant users
+	// +kubebuilder:validation:Optional
+	Protected bool `json:"protected,omitempty"`
+	// Manifest of the namespaced object, created in each Tenant Namespace regardless of its namespace
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:EmbeddedResource
+	Object runtime.RawExtension `json:"object"`
+}
+
 // TenantSpec defines the desired state of Tenant
 type TenantSpec struct {
 	Owner OwnerSpec `json:"owner"`
+	// Additional owners of the Tenant, sharing the same permissions of the primary one
+	// +kubebuilder:validation:Optional
+	Owners []OwnerSpec `json:"owners,omitempty"`
+	// Namespaces created without the Tenant name prefix get it prepended, regardless of the cluster-wide setting
+	// +kubebuilder:validation:Optional
+	ForceTenantPrefix bool `json:"forceTenantPrefix,omitempty"`
 	// +kubebuilder:validation:Optional
 	NamespacesMetadata AdditionalMetadata `json:"namespacesMetadata"`
+	// Namespace metadata the Tenant users cannot set, since other controllers react to it, or can change afterwards
+	// +kubebuilder:validation:Optional
+	NamespaceOptions *NamespaceOptions `json:"namespaceOptions,omitempty"`
+	// Upon the Tenant deletion, Orphan releases the Tenant Namespaces removing the Capsule metadata and objects, while
+	// Delete deletes them before the Tenant goes away
+	// +kubebuilder:validation:Optional
+	NamespaceDeletionPolicy NamespaceDeletionPolicy `json:"namespaceDeletionPolicy,omitempty"`
+	// Protect the Tenant Namespaces from deletion, annotating them upon creation
+	// +kubebuilder:validation:Optional
+	NamespaceDeletionProtection bool `json:"namespaceDeletionProtection,omitempty"`
+	// +kubebuilder:validation:Optional
+	ServicesMetadata AdditionalMetadata `json:"servicesMetadata"`
+	// Labels and annotations of the objects Capsule creates in the Tenant Namespaces, as the ResourceQuotas,
+	// LimitRanges, NetworkPolicies, and RoleBindings
+	// +kubebuilder:validation:Optional
+	AdditionalMetadata AdditionalMetadata `json:"additionalMetadata"`
+	StorageClasses     StorageClassesSpec `json:"storageClasses"`
+	IngressClasses     IngressClassesSpec `json:"ingressClasses"`
+	// Registries the Tenant Pods can pull images from, the regex is matched against the normalized image
+	// +kubebuilder:validation:Optional
+	ContainerRegistries ContainerRegistriesSpec `json:"containerRegistries"`
+	// Deprecated: use containerRegistries, the former registries are migrated there upon the Tenant update
+	// +kubebuilder:validation:Optional
+	RegistryClasses *RegistryClassesSpec `json:"registryClasses,omitempty"`
+	// Image pull policies the Tenant Pods containers can use, all allowed if none is listed
+	// +kubebuilder:validation:Optional
+	ImagePullPolicies []ImagePullPolicySpec `json:"imagePullPolicies,omitempty"`
+	// Rewrite the image pull policy of the Tenant Pods containers to the only allowed one, rather than denying them
+	// +kubebuilder:validation:Optional
+	RewriteImagePullPolicy bool `json:"rewriteImagePullPolicy,omitempty"`
+	// Secrets replicated from their Namespace into each Tenant Namespace, as the private registries credentials
+	// +kubebuilder:validation:Optional
+	ImagePullSecrets []ImagePullSecretSpec `json:"imagePullSecrets,omitempty"`
+	// Append the image pull secrets to the default ServiceAccount of each Tenant Namespace
+	// +kubebuilder:validation:Optional
+	PatchDefaultServiceAccount bool `json:"patchDefaultServiceAccount,omitempty"`
+	// PriorityClasses the Tenant Pods can use, besides the cluster default one
+	// +kubebuilder:validation:Optional
+	PriorityClasses PriorityClassesSpec `json:"priorityClasses"`
+	// Hostnames the Tenant Ingresses can claim, wildcard ones must be explicitly listed
 	// +kubebuilder:validation:Optional
-	ServicesMetadata AdditionalMetadata  `json:"servicesMetadata"`
-	StorageClasses   StorageClassesSpec  `json:"storageClasses"`
-	IngressClasses   IngressClassesSpec  `json:"ingressClasses"`
-	RegistryClasses  RegistryClassesSpec `json:"registryClasses"`
+	IngressHostnames IngressHostnamesSpec `json:"ingressHostnames"`
 	// +kubebuilder:validation:Optional
-	NodeSelector    map[string]string                `json:"nodeSelector"`
-	NamespaceQuota  NamespaceQuota                   `json:"namespaceQuota"`
-	NetworkPolicies []networkingv1.NetworkPolicySpec `json:"networkPolicies,omitempty"`
-	LimitRanges     []corev1.LimitRangeSpec          `json:"limitRanges"`
+	NodeSelector map[string]string `json:"nodeSelector"`
+	// Tolerations appended to the Tenant Pods, unless already set
+	// +kubebuilder:validation:Optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Deny the Tenant Pods tolerations not listed in the Tenant ones
+	// +kubebuilder:validation:Optional
+	EnforceTolerations bool                             `json:"enforceTolerations,omitempty"`
+	NamespaceQuota     NamespaceQuota                   `json:"namespaceQuota"`
+	NetworkPolicies    []networkingv1.NetworkPolicySpec `json:"networkPolicies,omitempty"`
+	LimitRanges        []corev1.LimitRangeSpec          `json:"limitRanges"`
 	// +kubebuilder:validation:Optional
 	ResourceQuota []corev1.ResourceQuotaSpec `json:"resourceQuotas"`
+	// Host namespaces, host ports, host paths, and privileges the Tenant Pods can use, all denied by default
+	// +kubebuilder:validation:Optional
+	PodSecurity PodSecuritySpec `json:"podSecurity"`
+	// Service external IPs allowed to the Tenant, all denied if missing
+	// +kubebuilder:validation:Optional
+	ExternalServiceIPs *ExternalServiceIPsSpec `json:"externalServiceIPs,omitempty"`
+	// Service types the Tenant can create, enforced along with enableNodePorts and enableLoadBalancers
+	// +kubebuilder:validation:Optional
+	ServiceOptions *ServiceOptions `json:"serviceOptions,omitempty"`
+	// Allow the NodePort Services, enabled if missing
+	// +kubebuilder:validation:Optional
+	EnableNodePorts *bool `json:"enableNodePorts,omitempty"`
+	// Allow the LoadBalancer Services, enabled if missing
+	// +kubebuilder:validation:Optional
+	EnableLoadBalancers *bool `json:"enableLoadBalancers,omitempty"`
+	// Annotations allowing a LoadBalancer Service when these are disabled, such as the internal load balancer ones
+	// +kubebuilder:validation:Optional
+	LoadBalancerAnnotations map[string]string `json:"loadBalancerAnnotations,omitempty"`
+	// Freeze the Tenant, denying the changes to its resources and the Namespace creation to the Tenant users
+	// +kubebuilder:validation:Optional
+	Cordoned bool `json:"cordoned,omitempty"`
+	// RoleBindings created in each Tenant Namespace, besides the owners ones
+	// +kubebuilder:validation:Optional
+	AdditionalRoleBindings []AdditionalRoleBindings `json:"additionalRoleBindings,omitempty"`
+	// Objects created in each Tenant Namespace, as pull Secrets, ConfigMaps, or ServiceAccounts, pruned once removed
+	// +kubebuilder:validation:Optional
+	AdditionalResources []AdditionalResourceSpec `json:"additionalResources,omitempty"`
+	// ClusterRoles the Tenant owners can bind in the Tenant Namespaces, all allowed if none is listed
+	// +kubebuilder:validation:Optional
+	ClusterRoles ClusterRolesSpec `json:"clusterRoles"`
+	// Maximum count of the objects of each resource across all the Tenant Namespaces, one of services, secrets,
+	// configmaps, persistentvolumeclaims, pods, or serviceaccounts
+	// +kubebuilder:validation:Optional
+	ObjectQuota map[corev1.ResourceName]uint `json:"objectQuota,omitempty"`
+	// PodDisruptionBudget created for each Deployment and StatefulSet in the Tenant Namespaces, unless already covered
+	// +kubebuilder:validation:Optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+	// ResourceQuotas, LimitRanges, and RoleBindings the Tenant users can create in the Tenant Namespaces, unrestricted
+	// if missing
+	// +kubebuilder:validation:Optional
+	UserResources *UserResourcesSpec `json:"userResources,omitempty"`
 }
 
 // OwnerSpec defines tenant owner name and kind
 type OwnerSpec struct {
+	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name"`
 	Kind Kind   `json:"kind"`
 }
 
-// +kubebuilder:validation:Enum=User;Group
+// +kubebuilder:validation:Enum=User;Group;ServiceAccount
 type Kind string
 
 func (k Kind) String() string {
 	return string(k)
 }
 
+// +kubebuilder:validation:Enum=Orphan;Delete
+type NamespaceDeletionPolicy string
+
+const (
+	NamespaceDeletionPolicyOrphan NamespaceDeletionPolicy = "Orphan"
+	NamespaceDeletionPolicyDelete NamespaceDeletionPolicy = "Delete"
+)
+
+// +kubebuilder:validation:Enum=Active;Cordoned
+type TenantState string
+
+const (
+	TenantStateActive   TenantState = "Active"
+	TenantStateCordoned TenantState = "Cordoned"
+)
+
+type TenantConditionType string
+
+const (
+	// The Tenant has been reconciled with no errors
+	TenantConditionReady TenantConditionType = "Ready"
+	// The ResourceQuotas have been synced in all the Tenant Namespaces
+	TenantConditionQuotaSynced TenantConditionType = "QuotaSynced"
+	// The RoleBindings have been synced in all the Tenant Namespaces
+	TenantConditionRBACSynced TenantConditionType = "RBACSynced"
+	// The Tenant Namespaces are more than the Namespace quota, lowered below the usage
+	TenantConditionNamespaceQuotaViolated TenantConditionType = "NamespaceQuotaViolated"
+)
+
+// TenantCondition reports the result of a Tenant reconciliation step, the message points to the failing object
+type TenantCondition struct {
+	Type   TenantConditionType    `json:"type"`
+	Status corev1.ConditionStatus `json:"status"`
+	// +kubebuilder:validation:Optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// +kubebuilder:validation:Optional
+	Reason string `json:"reason,omitempty"`
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// NamespaceReservation is a Namespace slot held by an admitted creation, until the Namespace is collected
+type NamespaceReservation struct {
+	Name      string      `json:"name"`
+	Timestamp metav1.Time `json:"timestamp"`
+}
+
 // TenantStatus defines the observed state of Tenant
 type TenantStatus struct {
 	Size       uint          `json:"size"`
 	Namespaces NamespaceList `json:"namespaces,omitempty"`
 	Users      []string      `json:"users,omitempty"`
 	Groups     []string      `json:"groups,omitempty"`
+	// The Tenant state, Active or Cordoned
+	State TenantState `json:"state,omitempty"`
+	// The Namespace quota has been reached, so the Namespace creation is denied
+	NamespaceQuotaExhausted bool `json:"namespaceQuotaExhausted,omitempty"`
+	// The Namespaces admitted but not yet collected, counting against the Namespace quota
+	Reservations []NamespaceReservation `json:"reservations,omitempty"`
+	// The results of the last reconciliation, as the ResourceQuotas and RoleBindings sync
+	Conditions []TenantCondition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -100,6 +440,7 @@ type TenantStatus struct {
 // +kubebuilder:printcolumn:name="Namespace count",type="integer",JSONPath=".status.size",description="The total amount of Namespaces in use"
 // +kubebuilder:printcolumn:name="Owner name",type="string",JSONPath=".spec.owner.name",description="The assigned Tenant owner"
 // +kubebuilder:printcolumn:name="Owner kind",type="string",JSONPath=".spec.owner.kind",description="The assigned Tenant owner kind"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="The Tenant state"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age"
 
 // Tenant is the Schema for the tenants API
@@ -0,0 +1,274 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"github.com/hashicorp/go-multierror"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+)
+
+// AdditionalResourcesReconciler seeds the Tenant additional resources into each Tenant Namespace, as the pull Secrets
+// or the ServiceAccounts. The applied objects are tracked by the Namespace annotation, keyed by the item name, to prune
+// the ones removed from the Tenant: the not protected ones are applied only upon the Namespace creation and the item
+// change, so the Tenant users can change or delete them afterwards.
+type AdditionalResourcesReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// appliedResource is the object applied for an additional resource item, the checksum tracks the item changes.
+type appliedResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Checksum   string `json:"checksum"`
+}
+
+func (a appliedResource) groupKind() schema.GroupKind {
+	return schema.FromAPIVersionAndKind(a.APIVersion, a.Kind).GroupKind()
+}
+
+func (r *AdditionalResourcesReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("additionalresources").
+		For(&capsulev1alpha1.Tenant{}).
+		Complete(r)
+}
+
+func (r AdditionalResourcesReconciler) Reconcile(request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("Request.Name", request.Name)
+
+	tnt := &capsulev1alpha1.Tenant{}
+	if err = r.Get(context.TODO(), request.NamespacedName, tnt); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Request object not found, could have been deleted after reconcile request")
+			return reconcile.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return reconcile.Result{}, err
+	}
+
+	for _, ns := range tnt.Status.Namespaces {
+		if e := r.syncNamespace(tnt, ns); e != nil {
+			err = multierror.Append(e, err)
+		}
+	}
+	if err != nil {
+		log.Error(err, "Cannot sync additional resources")
+		return reconcile.Result{}, err
+	}
+
+	log.Info("Additional resources reconciling completed")
+	return ctrl.Result{}, nil
+}
+
+// syncNamespace applies the additional resources to the Namespace, deleting the ones removed from the Tenant or
+// replaced by a different kind or name, then tracks the applied ones.
+func (r AdditionalResourcesReconciler) syncNamespace(tnt *capsulev1alpha1.Tenant, namespace string) error {
+	ns := &corev1.Namespace{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: namespace}, ns); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	applied, err := appliedResources(ns)
+	if err != nil {
+		return err
+	}
+
+	desired := make(map[string]appliedResource, len(tnt.Spec.AdditionalResources))
+	for _, item := range tnt.Spec.AdditionalResources {
+		obj := &unstructured.Unstructured{}
+		if err = obj.UnmarshalJSON(item.Object.Raw); err != nil {
+			return fmt.Errorf("cannot decode the additional resource %s: %w", item.Name, err)
+		}
+		ar := appliedResource{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Name:       obj.GetName(),
+			Checksum:   additionalResourceChecksum(item),
+		}
+		desired[item.Name] = ar
+		// the protected objects are restored at each reconciliation, the other ones only if the item changed
+		if previous, ok := applied[item.Name]; ok && previous == ar && !item.Protected {
+			continue
+		}
+		if err = r.syncResource(tnt, namespace, item, obj); err != nil {
+			return err
+		}
+	}
+
+	for name, previous := range applied {
+		if ar, ok := desired[name]; ok && ar.groupKind() == previous.groupKind() && ar.Name == previous.Name {
+			continue
+		}
+		if err = deleteAppliedResource(r.Client, tnt.GetName(), namespace, previous); err != nil {
+			return err
+		}
+	}
+
+	return r.trackAppliedResources(namespace, desired)
+}
+
+func (r AdditionalResourcesReconciler) syncResource(tnt *capsulev1alpha1.Tenant, namespace string, item capsulev1alpha1.AdditionalResourceSpec, obj *unstructured.Unstructured) error {
+	tl, err := capsulev1alpha1.GetTypeLabel(&capsulev1alpha1.Tenant{})
+	if err != nil {
+		return err
+	}
+
+	target := &unstructured.Unstructured{}
+	target.SetGroupVersionKind(obj.GroupVersionKind())
+	target.SetNamespace(namespace)
+	target.SetName(obj.GetName())
+
+	res, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, target, func() (err error) {
+		// the object content is replaced, besides the metadata and the status
+		for k, v := range obj.Object {
+			if k == "metadata" || k == "status" {
+				continue
+			}
+			target.Object[k] = runtime.DeepCopyJSONValue(v)
+		}
+		l := target.GetLabels()
+		if l == nil {
+			l = make(map[string]string)
+		}
+		for k, v := range obj.GetLabels() {
+			l[k] = v
+		}
+		delete(l, capsulev1alpha1.ProtectedResourceLabel)
+		target.SetLabels(l)
+		a := target.GetAnnotations()
+		if a == nil {
+			a = make(map[string]string)
+		}
+		for k, v := range obj.GetAnnotations() {
+			a[k] = v
+		}
+		target.SetAnnotations(a)
+
+		capsuleLabels := map[string]string{
+			tl:                                      tnt.GetName(),
+			capsulev1alpha1.AdditionalResourceLabel: item.Name,
+		}
+		if item.Protected {
+			capsuleLabels[capsulev1alpha1.ProtectedResourceLabel] = "true"
+		}
+		managedObjectMetadata(target, tnt.Spec.AdditionalMetadata, capsuleLabels)
+		return controllerutil.SetControllerReference(tnt, target, r.Scheme)
+	})
+	r.Log.Info("Additional resource sync result: "+string(res), "name", target.GetName(), "namespace", namespace, "kind", target.GetKind())
+	if err != nil {
+		return fmt.Errorf("cannot sync the additional resource %s in the Namespace %s: %w", item.Name, namespace, err)
+	}
+	return nil
+}
+
+// trackAppliedResources stores the applied resources in the Namespace annotation, dropping it when there's none.
+func (r AdditionalResourcesReconciler) trackAppliedResources(namespace string, applied map[string]appliedResource) error {
+	value, err := json.Marshal(applied)
+	if err != nil {
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		ns := &corev1.Namespace{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: namespace}, ns); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		a := ns.GetAnnotations()
+		if a == nil {
+			a = make(map[string]string)
+		}
+		current := copyAnnotations(a)
+		if len(applied) > 0 {
+			a[capsulev1alpha1.AppliedResourcesAnnotation] = string(value)
+		} else {
+			delete(a, capsulev1alpha1.AppliedResourcesAnnotation)
+		}
+		if reflect.DeepEqual(current, a) {
+			return nil
+		}
+		ns.SetAnnotations(a)
+		return r.Update(context.TODO(), ns)
+	})
+}
+
+// appliedResources returns the additional resources applied to the Namespace, keyed by the item name.
+func appliedResources(ns *corev1.Namespace) (map[string]appliedResource, error) {
+	applied := make(map[string]appliedResource)
+	value, ok := ns.GetAnnotations()[capsulev1alpha1.AppliedResourcesAnnotation]
+	if !ok {
+		return applied, nil
+	}
+	if err := json.Unmarshal([]byte(value), &applied); err != nil {
+		return nil, fmt.Errorf("cannot decode the applied resources of the Namespace %s: %w", ns.GetName(), err)
+	}
+	return applied, nil
+}
+
+// deleteAppliedResource deletes the object applied for an additional resource, if still labelled with the Tenant.
+func deleteAppliedResource(c client.Client, tenant, namespace string, applied appliedResource) error {
+	tl, err := capsulev1alpha1.GetTypeLabel(&capsulev1alpha1.Tenant{})
+	if err != nil {
+		return err
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.FromAPIVersionAndKind(applied.APIVersion, applied.Kind))
+	if err = c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: applied.Name}, obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if obj.GetLabels()[tl] != tenant {
+		return nil
+	}
+	if err = c.Delete(context.TODO(), obj); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("cannot delete the %s %s/%s: %w", applied.Kind, namespace, applied.Name, err)
+	}
+	return nil
+}
+
+// additionalResourceChecksum changes along with the item manifest or protection.
+func additionalResourceChecksum(item capsulev1alpha1.AdditionalResourceSpec) string {
+	return fmt.Sprintf("%x", sha256.Sum256(append([]byte(fmt.Sprintf("%t", item.Protected)), item.Object.Raw...)))
+}
+
+func copyAnnotations(annotations map[string]string) map[string]string {
+	out := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/pkg/configuration"
+)
+
+// CapsuleConfigurationReconciler applies the default CapsuleConfiguration to the configuration Store, so the changes
+// take effect without restarting Capsule: upon its deletion the command line options are restored, while an invalid
+// spec is reported by an Event, keeping the previous settings.
+type CapsuleConfigurationReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Store    *configuration.Store
+	Recorder record.EventRecorder
+}
+
+func (r *CapsuleConfigurationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	isDefault := func(name string) bool {
+		return name == capsulev1alpha1.CapsuleConfigurationName
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&capsulev1alpha1.CapsuleConfiguration{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(event event.CreateEvent) bool {
+				return isDefault(event.Meta.GetName())
+			},
+			DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
+				return isDefault(deleteEvent.Meta.GetName())
+			},
+			UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+				return isDefault(updateEvent.MetaNew.GetName())
+			},
+			GenericFunc: func(genericEvent event.GenericEvent) bool {
+				return isDefault(genericEvent.Meta.GetName())
+			},
+		})).
+		Complete(r)
+}
+
+func (r CapsuleConfigurationReconciler) Reconcile(request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("Request.Name", request.Name)
+
+	cfg := &capsulev1alpha1.CapsuleConfiguration{}
+	if err = r.Get(context.TODO(), request.NamespacedName, cfg); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("CapsuleConfiguration not found, restoring the command line options")
+			r.Store.Reset()
+			return reconcile.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return reconcile.Result{}, err
+	}
+
+	if err = r.Store.Apply(cfg.Spec); err != nil {
+		// retrying is pointless until the spec is fixed, triggering a new reconciliation
+		log.Error(err, "Invalid CapsuleConfiguration, keeping the previous settings")
+		r.Recorder.Eventf(cfg, corev1.EventTypeWarning, "InvalidConfiguration", "The CapsuleConfiguration cannot be applied: %s", err.Error())
+		return reconcile.Result{}, nil
+	}
+
+	log.Info("CapsuleConfiguration applied")
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+)
+
+// ExcludedNamespacesReconciler labels the system Namespaces with the exclusion label, so they're skipped by the
+// webhooks selecting the Namespaces, and a Capsule outage cannot block the cluster-critical operations.
+// The label is restored if removed, while it's left in place when a Namespace is not listed anymore.
+type ExcludedNamespacesReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	// Namespaces are the names of the Namespaces to exclude.
+	Namespaces []string
+}
+
+func (r *ExcludedNamespacesReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("excludednamespaces").
+		For(&corev1.Namespace{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(event event.CreateEvent) bool {
+				return r.isExcluded(event.Meta.GetName())
+			},
+			DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
+				return false
+			},
+			UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+				return r.isExcluded(updateEvent.MetaNew.GetName())
+			},
+			GenericFunc: func(genericEvent event.GenericEvent) bool {
+				return r.isExcluded(genericEvent.Meta.GetName())
+			},
+		})).
+		Complete(r)
+}
+
+func (r ExcludedNamespacesReconciler) isExcluded(name string) bool {
+	for _, ns := range r.Namespaces {
+		if ns == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r ExcludedNamespacesReconciler) Reconcile(request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("Request.Name", request.Name)
+
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		ns := &corev1.Namespace{}
+		if err := r.Get(context.TODO(), request.NamespacedName, ns); err != nil {
+			return err
+		}
+		if ns.GetLabels()[capsulev1alpha1.ExcludedNamespaceLabel] == "true" {
+			return nil
+		}
+		labels := ns.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[capsulev1alpha1.ExcludedNamespaceLabel] = "true"
+		ns.SetLabels(labels)
+		return r.Update(context.TODO(), ns)
+	})
+	if errors.IsNotFound(err) {
+		log.Info("Request object not found, could have been deleted after reconcile request")
+		return reconcile.Result{}, nil
+	}
+	if err != nil {
+		log.Error(err, "Cannot label the excluded Namespace")
+		return reconcile.Result{}, err
+	}
+
+	log.Info("Excluded Namespace reconciling completed")
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"github.com/hashicorp/go-multierror"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+)
+
+// ImagePullSecretsReconciler replicates the Tenant image pull secrets from their source Namespace into each Tenant
+// Namespace, keeping them in sync with the source and deleting the ones no more listed, and appends them to the
+// default ServiceAccount if requested.
+// The replicas keep the source name and are labelled with the Tenant and the source Namespace, to be pruned.
+type ImagePullSecretsReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+func (r *ImagePullSecretsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("imagepullsecrets").
+		For(&capsulev1alpha1.Tenant{}).
+		// both the source Secrets and the replicas changed by the Tenant users
+		Watches(&source.Kind{Type: &corev1.Secret{}}, r.enqueueSecretTenants()).
+		Watches(&source.Kind{Type: &corev1.ServiceAccount{}}, r.enqueueSecretTenants()).
+		Complete(r)
+}
+
+// enqueueSecretTenants maps the object to the Tenants listing it as image pull secret source, and to the Tenant owning
+// its Namespace, if any.
+func (r *ImagePullSecretsReconciler) enqueueSecretTenants() handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) (requests []reconcile.Request) {
+			tl := &capsulev1alpha1.TenantList{}
+			if err := r.List(context.TODO(), tl); err != nil {
+				r.Log.Error(err, "Cannot list Tenants")
+				return nil
+			}
+			for _, tnt := range tl.Items {
+				if tnt.Status.Namespaces.IsStringInList(a.Meta.GetNamespace()) || isImagePullSecretSource(tnt.Spec.ImagePullSecrets, a.Meta.GetNamespace(), a.Meta.GetName()) {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: tnt.GetName()}})
+				}
+			}
+			return
+		}),
+	}
+}
+
+func (r ImagePullSecretsReconciler) Reconcile(request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("Request.Name", request.Name)
+
+	tnt := &capsulev1alpha1.Tenant{}
+	if err = r.Get(context.TODO(), request.NamespacedName, tnt); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Request object not found, could have been deleted after reconcile request")
+			return reconcile.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return reconcile.Result{}, err
+	}
+
+	// the missing sources are skipped, replicated as soon as created
+	sources := make([]*corev1.Secret, 0, len(tnt.Spec.ImagePullSecrets))
+	for _, ref := range tnt.Spec.ImagePullSecrets {
+		secret := &corev1.Secret{}
+		if err = r.Get(context.TODO(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+			if errors.IsNotFound(err) {
+				log.Info("Image pull secret source not found", "namespace", ref.Namespace, "name", ref.Name)
+				continue
+			}
+			return reconcile.Result{}, err
+		}
+		sources = append(sources, secret)
+	}
+	err = nil
+
+	for _, ns := range tnt.Status.Namespaces {
+		if e := r.syncNamespace(tnt, ns, sources); e != nil {
+			err = multierror.Append(e, err)
+		}
+	}
+	if err != nil {
+		log.Error(err, "Cannot sync image pull secrets")
+		return reconcile.Result{}, err
+	}
+
+	log.Info("Image pull secrets reconciling completed")
+	return ctrl.Result{}, nil
+}
+
+// syncNamespace replicates the source Secrets into the Namespace, deleting the replicas no more listed, and updates the
+// default ServiceAccount image pull secrets.
+func (r ImagePullSecretsReconciler) syncNamespace(tnt *capsulev1alpha1.Tenant, namespace string, sources []*corev1.Secret) error {
+	tl, err := capsulev1alpha1.GetTypeLabel(&capsulev1alpha1.Tenant{})
+	if err != nil {
+		return err
+	}
+
+	desired := make(map[string]struct{}, len(sources))
+	for _, src := range sources {
+		// the source is not replicated into its own Namespace
+		if src.GetNamespace() == namespace {
+			continue
+		}
+		if err = r.syncSecret(tnt, tl, namespace, src); err != nil {
+			return err
+		}
+		desired[src.GetName()] = struct{}{}
+	}
+
+	sl := &corev1.SecretList{}
+	if err = r.List(context.TODO(), sl, client.InNamespace(namespace), client.MatchingLabels{tl: tnt.GetName()}, client.HasLabels{capsulev1alpha1.ImagePullSecretLabel}); err != nil {
+		return fmt.Errorf("cannot list the image pull secrets in the Namespace %s: %w", namespace, err)
+	}
+	pruned := make(map[string]struct{})
+	for i := range sl.Items {
+		s := &sl.Items[i]
+		if _, ok := desired[s.GetName()]; ok {
+			continue
+		}
+		if err = r.Delete(context.TODO(), s); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("cannot delete the image pull secret %s/%s: %w", namespace, s.GetName(), err)
+		}
+		pruned[s.GetName()] = struct{}{}
+	}
+
+	return r.syncDefaultServiceAccount(namespace, tnt.Spec.PatchDefaultServiceAccount, desired, pruned)
+}
+
+func (r ImagePullSecretsReconciler) syncSecret(tnt *capsulev1alpha1.Tenant, tl, namespace string, src *corev1.Secret) error {
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      src.GetName(),
+			Namespace: namespace,
+		},
+	}
+
+	// a Secret with the same name not created by Capsule is left untouched
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: src.GetName()}, target); err == nil {
+		if target.GetLabels()[tl] != tnt.GetName() {
+			r.Log.Info("Skipping the image pull secret, since a Secret with the same name exists", "name", src.GetName(), "namespace", namespace)
+			return nil
+		}
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	res, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, target, func() (err error) {
+		target.Type = src.Type
+		target.Data = src.Data
+		managedObjectMetadata(target, tnt.Spec.AdditionalMetadata, map[string]string{
+			tl:                                   tnt.GetName(),
+			capsulev1alpha1.ImagePullSecretLabel: src.GetNamespace(),
+		})
+		return controllerutil.SetControllerReference(tnt, target, r.Scheme)
+	})
+	r.Log.Info("Image pull secret sync result: "+string(res), "name", target.GetName(), "namespace", namespace)
+	if err != nil {
+		return fmt.Errorf("cannot sync the image pull secret %s/%s: %w", namespace, target.GetName(), err)
+	}
+	return nil
+}
+
+// syncDefaultServiceAccount appends the replicas to the default ServiceAccount image pull secrets, removing the pruned
+// ones: when the patching is disabled, all the replicas are removed from it.
+func (r ImagePullSecretsReconciler) syncDefaultServiceAccount(namespace string, patch bool, desired, pruned map[string]struct{}) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		sa := &corev1.ServiceAccount{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "default"}, sa); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+
+		changed := false
+		refs := make([]corev1.LocalObjectReference, 0, len(sa.ImagePullSecrets)+len(desired))
+		present := make(map[string]struct{}, len(sa.ImagePullSecrets))
+		for _, ref := range sa.ImagePullSecrets {
+			_, isPruned := pruned[ref.Name]
+			_, isDesired := desired[ref.Name]
+			if isPruned || (isDesired && !patch) {
+				changed = true
+				continue
+			}
+			refs = append(refs, ref)
+			present[ref.Name] = struct{}{}
+		}
+		if patch {
+			// sorting the names to avoid a different order at each reconciliation
+			names := make([]string, 0, len(desired))
+			for name := range desired {
+				if _, ok := present[name]; !ok {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				refs = append(refs, corev1.LocalObjectReference{Name: name})
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+
+		sa.ImagePullSecrets = refs
+		return r.Update(context.TODO(), sa)
+	})
+}
+
+func isImagePullSecretSource(secrets []capsulev1alpha1.ImagePullSecretSpec, namespace, name string) bool {
+	for _, s := range secrets {
+		if s.Namespace == namespace && s.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/hashicorp/go-multierror"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+)
+
+// PodDisruptionBudgetReconciler creates a PodDisruptionBudget for each Deployment and StatefulSet in the Tenant
+// Namespaces, according to the Tenant PodDisruptionBudget spec.
+// The PodDisruptionBudget is controlled by its workload, thus garbage collected along with it, and it is labelled with
+// the Tenant to be pruned once the spec is removed.
+type PodDisruptionBudgetReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+func (r *PodDisruptionBudgetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("poddisruptionbudget").
+		For(&capsulev1alpha1.Tenant{}).
+		Watches(&source.Kind{Type: &appsv1.Deployment{}}, r.enqueueNamespaceTenant()).
+		Watches(&source.Kind{Type: &appsv1.StatefulSet{}}, r.enqueueNamespaceTenant()).
+		// the PodDisruptionBudgets created by the users could start, or stop, covering a workload
+		Watches(&source.Kind{Type: &policyv1beta1.PodDisruptionBudget{}}, r.enqueueNamespaceTenant()).
+		Complete(r)
+}
+
+// enqueueNamespaceTenant maps the object to the Tenant owning its Namespace, if any.
+func (r *PodDisruptionBudgetReconciler) enqueueNamespaceTenant() handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) (requests []reconcile.Request) {
+			tl := &capsulev1alpha1.TenantList{}
+			if err := r.List(context.TODO(), tl, client.MatchingFieldsSelector{
+				Selector: fields.OneTermEqualSelector(".status.namespaces", a.Meta.GetNamespace()),
+			}); err != nil {
+				r.Log.Error(err, "Cannot list Tenants", "namespace", a.Meta.GetNamespace())
+				return nil
+			}
+			for _, tnt := range tl.Items {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: tnt.GetName()}})
+			}
+			return
+		}),
+	}
+}
+
+func (r PodDisruptionBudgetReconciler) Reconcile(request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("Request.Name", request.Name)
+
+	tnt := &capsulev1alpha1.Tenant{}
+	if err = r.Get(context.TODO(), request.NamespacedName, tnt); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Request object not found, could have been deleted after reconcile request")
+			return reconcile.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return reconcile.Result{}, err
+	}
+
+	for _, ns := range tnt.Status.Namespaces {
+		if e := r.syncNamespace(tnt, ns); e != nil {
+			err = multierror.Append(e, err)
+		}
+	}
+	if err != nil {
+		log.Error(err, "Cannot sync PodDisruptionBudgets")
+		return reconcile.Result{}, err
+	}
+
+	log.Info("PodDisruptionBudgets reconciling completed")
+	return ctrl.Result{}, nil
+}
+
+// syncNamespace creates or updates the PodDisruptionBudget of each workload in the Namespace, deleting the ones
+// created by Capsule that are no more required.
+func (r PodDisruptionBudgetReconciler) syncNamespace(tnt *capsulev1alpha1.Tenant, namespace string) error {
+	tl, err := capsulev1alpha1.GetTypeLabel(&capsulev1alpha1.Tenant{})
+	if err != nil {
+		return err
+	}
+
+	pdbl := &policyv1beta1.PodDisruptionBudgetList{}
+	if err = r.List(context.TODO(), pdbl, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("cannot list PodDisruptionBudgets in the Namespace %s: %w", namespace, err)
+	}
+
+	// the desired PodDisruptionBudgets, by name
+	desired := map[string]metav1.Object{}
+	if tnt.Spec.PodDisruptionBudget != nil {
+		dl := &appsv1.DeploymentList{}
+		if err = r.List(context.TODO(), dl, client.InNamespace(namespace)); err != nil {
+			return fmt.Errorf("cannot list Deployments in the Namespace %s: %w", namespace, err)
+		}
+		for i := range dl.Items {
+			d := &dl.Items[i]
+			if d.GetDeletionTimestamp() == nil && !isCoveredByPodDisruptionBudget(pdbl.Items, tl, d.Spec.Template.GetLabels()) {
+				desired[podDisruptionBudgetName("deployment", d.GetName())] = d
+			}
+		}
+		sl := &appsv1.StatefulSetList{}
+		if err = r.List(context.TODO(), sl, client.InNamespace(namespace)); err != nil {
+			return fmt.Errorf("cannot list StatefulSets in the Namespace %s: %w", namespace, err)
+		}
+		for i := range sl.Items {
+			s := &sl.Items[i]
+			if s.GetDeletionTimestamp() == nil && !isCoveredByPodDisruptionBudget(pdbl.Items, tl, s.Spec.Template.GetLabels()) {
+				desired[podDisruptionBudgetName("statefulset", s.GetName())] = s
+			}
+		}
+	}
+
+	for i := range pdbl.Items {
+		pdb := &pdbl.Items[i]
+		if pdb.GetLabels()[tl] != tnt.GetName() {
+			continue
+		}
+		if _, ok := desired[pdb.GetName()]; ok {
+			continue
+		}
+		if err = r.Delete(context.TODO(), pdb); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("cannot delete the PodDisruptionBudget %s/%s: %w", namespace, pdb.GetName(), err)
+		}
+	}
+
+	for name, workload := range desired {
+		if err = r.syncPodDisruptionBudget(tnt, tl, name, workload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r PodDisruptionBudgetReconciler) syncPodDisruptionBudget(tnt *capsulev1alpha1.Tenant, tl string, name string, workload metav1.Object) error {
+	var selector *metav1.LabelSelector
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		selector = w.Spec.Selector
+	case *appsv1.StatefulSet:
+		selector = w.Spec.Selector
+	}
+
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: workload.GetNamespace(),
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, pdb, func() (err error) {
+		l := pdb.GetLabels()
+		if l == nil {
+			l = map[string]string{}
+		}
+		l[tl] = tnt.GetName()
+		pdb.SetLabels(l)
+		pdb.Spec.Selector = selector.DeepCopy()
+		pdb.Spec.MinAvailable = tnt.Spec.PodDisruptionBudget.MinAvailable
+		pdb.Spec.MaxUnavailable = tnt.Spec.PodDisruptionBudget.MaxUnavailable
+		return controllerutil.SetControllerReference(workload, pdb, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("cannot sync the PodDisruptionBudget %s/%s: %w", workload.GetNamespace(), name, err)
+	}
+	return nil
+}
+
+// podDisruptionBudgetName is prefixed by the workload kind, since a Deployment and a StatefulSet can share the name.
+func podDisruptionBudgetName(kind string, name string) string {
+	return fmt.Sprintf("capsule-%s-%s", kind, name)
+}
+
+// isCoveredByPodDisruptionBudget checks if a PodDisruptionBudget not created by Capsule is already selecting the
+// workload Pods: the eviction of a Pod matched by more than one PodDisruptionBudget would be refused.
+func isCoveredByPodDisruptionBudget(pdbs []policyv1beta1.PodDisruptionBudget, tl string, podLabels map[string]string) bool {
+	for _, pdb := range pdbs {
+		if _, ok := pdb.GetLabels()[tl]; ok || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		if selector.Matches(labels.Set(podLabels)) {
+			return true
+		}
+	}
+	return false
+}
@@ -22,8 +22,9 @@ import (
 )
 
 const (
-	ProvisionerRoleName = "capsule-namespace-provisioner"
-	DeleterRoleName     = "capsule-namespace-deleter"
+	ProvisionerRoleName    = "capsule-namespace-provisioner"
+	DeleterRoleName        = "capsule-namespace-deleter"
+	MetadataEditorRoleName = "capsule-namespace-metadata-editor"
 )
 
 var (
@@ -52,6 +53,18 @@ var (
 				},
 			},
 		},
+		MetadataEditorRoleName: {
+			ObjectMeta: metav1.ObjectMeta{
+				Name: MetadataEditorRoleName,
+			},
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{""},
+					Resources: []string{"namespaces"},
+					Verbs:     []string{"patch", "update"},
+				},
+			},
+		},
 	}
 
 	provisionerClusterRoleBinding = &rbacv1.ClusterRoleBinding{
@@ -25,19 +25,24 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/clastix/capsule/pkg/configuration"
 )
 
 type Manager struct {
-	CapsuleGroup string
-	Log          logr.Logger
-	Client       client.Client
+	Configuration *configuration.Store
+	Log           logr.Logger
+	Client        client.Client
 }
 
 // Using the Client interface, required by the Runnable interface
@@ -47,7 +52,7 @@ func (r *Manager) InjectClient(c client.Client) error {
 }
 
 func (r *Manager) filterByClusterRolesNames(name string) bool {
-	return name == ProvisionerRoleName || name == DeleterRoleName
+	return name == ProvisionerRoleName || name == DeleterRoleName || name == MetadataEditorRoleName
 }
 
 func (r *Manager) SetupWithManager(mgr ctrl.Manager) (err error) {
@@ -85,6 +90,12 @@ func (r *Manager) SetupWithManager(mgr ctrl.Manager) (err error) {
 				return genericEvent.Meta.GetName() == ProvisionerRoleName
 			},
 		})).
+		// the Capsule user groups can be changed at runtime by the CapsuleConfiguration
+		Watches(&source.Channel{Source: r.Configuration.Subscribe()}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(func(handler.MapObject) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: ProvisionerRoleName}}}
+			}),
+		}).
 		Complete(r)
 	if crbErr != nil {
 		err = multierror.Append(err, crbErr)
@@ -110,6 +121,11 @@ func (r *Manager) Reconcile(request reconcile.Request) (res reconcile.Result, er
 			r.Log.Error(err, "Reconciliation for ClusterRole failed", "ClusterRole", DeleterRoleName)
 			break
 		}
+	case MetadataEditorRoleName:
+		if err = r.EnsureClusterRole(MetadataEditorRoleName); err != nil {
+			r.Log.Error(err, "Reconciliation for ClusterRole failed", "ClusterRole", MetadataEditorRoleName)
+			break
+		}
 	}
 	return reconcile.Result{}, err
 }
@@ -127,11 +143,13 @@ func (r *Manager) EnsureClusterRoleBinding() (err error) {
 			return ImmutableClusterRoleBindingError{}
 		}
 		crb.RoleRef = provisionerClusterRoleBinding.RoleRef
-		crb.Subjects = []rbacv1.Subject{
-			{
+		groups := r.Configuration.Load().UserGroups
+		crb.Subjects = make([]rbacv1.Subject, 0, len(groups))
+		for _, group := range groups {
+			crb.Subjects = append(crb.Subjects, rbacv1.Subject{
 				Kind: "Group",
-				Name: r.CapsuleGroup,
-			},
+				Name: group,
+			})
 		}
 		return nil
 	})
@@ -19,15 +19,21 @@ package secret
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"errors"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -35,6 +41,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/clastix/capsule/pkg/cert"
+	"github.com/clastix/capsule/pkg/configuration"
 )
 
 type CaReconciler struct {
@@ -42,77 +49,197 @@ type CaReconciler struct {
 	Log       logr.Logger
 	Scheme    *runtime.Scheme
 	Namespace string
+	// KeyType is the algorithm used to generate a new CA private key: already existing CA are kept until the natural
+	// rotation, regardless of their key algorithm.
+	KeyType cert.KeyType
+	// RsaKeySize is the size in bits of a newly generated RSA key, recorded in the CA Secret annotations.
+	RsaKeySize int
+	// KeyEncoding is the PEM format of the CA private key.
+	KeyEncoding cert.KeyEncoding
+	// Configuration provides the lifetime of a newly generated CA and of the webhook serving certificate, along with
+	// the percentage of their lifetime before the expiration when they get renewed.
+	Configuration *configuration.Store
+	// DeploymentName is the name of the Capsule Deployment, set as controller of the generated Secrets.
+	DeploymentName string
+	// TlsExtraSans are used to issue the webhook serving certificate as soon as the CA is rotated.
+	TlsExtraSans []string
+	// CaSecretName is the name of the Secret holding the CA: when it differs from the default one, the CA is
+	// provided by the cluster administrator and never generated by Capsule.
+	CaSecretName string
+	// TlsSecretName is the name of the Secret holding the webhook serving certificate.
+	TlsSecretName string
+	// ValidatingWebhookConfigurationName is the name of the ValidatingWebhookConfiguration to inject the CABundle.
+	ValidatingWebhookConfigurationName string
+	// MutatingWebhookConfigurationName is the name of the MutatingWebhookConfiguration to inject the CABundle.
+	MutatingWebhookConfigurationName string
+	// MinRequeue is the minimum interval between two CA checks.
+	MinRequeue time.Duration
+	// Clock provides the current time to compute the CA expiration, defaulting to the real one.
+	Clock cert.Clock
+	// Recorder emits the Events upon the CA rotation and the webhooks CABundle update.
+	Recorder record.EventRecorder
 }
 
 func (r *CaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Secret{}, forOptionPerInstanceName(caSecretName)).
+		For(&corev1.Secret{}, forOptionPerInstanceName(r.caSecretName(), dataChangedPredicate())).
 		Complete(r)
 }
 
-func (r CaReconciler) UpdateValidatingWebhookConfiguration(wg *sync.WaitGroup, ch chan error, caBundle []byte) {
-	defer wg.Done()
+func (r CaReconciler) clock() cert.Clock {
+	if r.Clock == nil {
+		return cert.RealClock{}
+	}
+	return r.Clock
+}
 
-	var err error
+func (r CaReconciler) caSecretName() string {
+	if r.isExternalCa() {
+		return r.CaSecretName
+	}
+	return caSecretName
+}
+
+func (r CaReconciler) tlsSecretName() string {
+	if len(r.TlsSecretName) > 0 {
+		return r.TlsSecretName
+	}
+	return tlsSecretName
+}
 
-	ch <- retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+func (r CaReconciler) UpdateValidatingWebhookConfiguration(ctx context.Context, caBundle []byte) error {
+	return r.updateValidatingWebhookConfiguration(ctx, r.ValidatingWebhookConfigurationName, caBundle)
+}
+
+func (r CaReconciler) updateValidatingWebhookConfiguration(ctx context.Context, name string, caBundle []byte) (err error) {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		// Giving up the retries when the reconciliation has been cancelled or its deadline is exceeded
+		if err = ctx.Err(); err != nil {
+			return err
+		}
 		vw := &v1.ValidatingWebhookConfiguration{}
-		err = r.Get(context.TODO(), types.NamespacedName{Name: "capsule-validating-webhook-configuration"}, vw)
+		err = r.Get(ctx, types.NamespacedName{Name: name}, vw)
+		if apierrors.IsNotFound(err) {
+			return NewMissingWebhookConfigurationError("ValidatingWebhookConfiguration", name)
+		}
 		if err != nil {
 			r.Log.Error(err, "cannot retrieve ValidatingWebhookConfiguration")
 			return err
 		}
+		if isCaInjected(vw) {
+			r.Log.Info("ValidatingWebhookConfiguration CABundle is handled by cert-manager, skipping")
+			return nil
+		}
 		for i, w := range vw.Webhooks {
 			// Updating CABundle only in case of an internal service reference
 			if w.ClientConfig.Service != nil {
 				vw.Webhooks[i].ClientConfig.CABundle = caBundle
 			}
 		}
-		return r.Update(context.TODO(), vw, &client.UpdateOptions{})
+		return r.Update(ctx, vw, &client.UpdateOptions{})
 	})
 }
 
-func (r CaReconciler) UpdateMutatingWebhookConfiguration(wg *sync.WaitGroup, ch chan error, caBundle []byte) {
-	defer wg.Done()
-
-	var err error
+func (r CaReconciler) UpdateMutatingWebhookConfiguration(ctx context.Context, caBundle []byte) error {
+	return r.updateMutatingWebhookConfiguration(ctx, r.MutatingWebhookConfigurationName, caBundle)
+}
 
-	ch <- retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+func (r CaReconciler) updateMutatingWebhookConfiguration(ctx context.Context, name string, caBundle []byte) (err error) {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
 		mw := &v1.MutatingWebhookConfiguration{}
-		err = r.Get(context.TODO(), types.NamespacedName{Name: "capsule-mutating-webhook-configuration"}, mw)
+		err = r.Get(ctx, types.NamespacedName{Name: name}, mw)
+		if apierrors.IsNotFound(err) {
+			return NewMissingWebhookConfigurationError("MutatingWebhookConfiguration", name)
+		}
 		if err != nil {
 			r.Log.Error(err, "cannot retrieve MutatingWebhookConfiguration")
 			return err
 		}
+		if isCaInjected(mw) {
+			r.Log.Info("MutatingWebhookConfiguration CABundle is handled by cert-manager, skipping")
+			return nil
+		}
 		for i, w := range mw.Webhooks {
 			// Updating CABundle only in case of an internal service reference
 			if w.ClientConfig.Service != nil {
 				mw.Webhooks[i].ClientConfig.CABundle = caBundle
 			}
 		}
-		return r.Update(context.TODO(), mw, &client.UpdateOptions{})
+		return r.Update(ctx, mw, &client.UpdateOptions{})
+	})
+}
+
+func (r CaReconciler) UpdateCustomResourceDefinition(ctx context.Context, caBundle []byte) (err error) {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		err = r.Get(ctx, types.NamespacedName{Name: tenantCustomResourceDefinitionName}, crd)
+		if err != nil {
+			r.Log.Error(err, "cannot retrieve CustomResourceDefinition")
+			return err
+		}
+		if isCaInjected(crd) {
+			r.Log.Info("CustomResourceDefinition CABundle is handled by cert-manager, skipping")
+			return nil
+		}
+		// Updating CABundle only in case of a conversion webhook with an internal service reference
+		if crd.Spec.Conversion == nil || crd.Spec.Conversion.Webhook == nil || crd.Spec.Conversion.Webhook.ClientConfig == nil {
+			return nil
+		}
+		if crd.Spec.Conversion.Webhook.ClientConfig.Service == nil {
+			return nil
+		}
+		crd.Spec.Conversion.Webhook.ClientConfig.CABundle = caBundle
+		return r.Update(ctx, crd, &client.UpdateOptions{})
 	})
 }
 
 func (r CaReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 	var err error
 
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
 	r.Log = r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	r.Log.Info("Reconciling CA Secret")
 
 	// Fetch the CA instance
 	instance := &corev1.Secret{}
-	err = r.Client.Get(context.TODO(), request.NamespacedName, instance)
+	err = r.Client.Get(ctx, request.NamespacedName, instance)
 	if err != nil {
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
 
+	// The whole certificate management is delegated to cert-manager when it issued the TLS Secret
+	tls := &corev1.Secret{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: r.tlsSecretName()}, tls)
+	if err == nil && isExternallyManaged(tls) {
+		r.Log.Info("Capsule TLS is managed by cert-manager, skipping CA reconciliation")
+		return reconcile.Result{}, nil
+	}
+
+	if r.isExternalCa() {
+		return r.reconcileExternalCa(ctx, instance)
+	}
+
+	cfg := r.Configuration.Load()
+
 	var ca cert.Ca
 	var rq time.Duration
-	ca, err = getCertificateAuthority(r.Client, r.Namespace)
+	ca, err = getCertificateAuthority(ctx, r.Client, r.clock(), r.Namespace, caSecretName)
+	if invalid := (cert.InvalidCaError{}); errors.As(err, &invalid) {
+		r.Log.Info("CA is corrupted, generating a new one", "reason", err.Error())
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "InvalidCA", "CA is corrupted and will be regenerated: %s", err.Error())
+		err = MissingCaError{}
+	}
 	if err != nil && errors.Is(err, MissingCaError{}) {
-		ca, err = cert.GenerateCertificateAuthority()
+		ca, err = cert.GenerateCertificateAuthorityWithOptions(cert.NewCaOpts(r.KeyType, r.RsaKeySize, cfg.CaValidity).WithClock(r.clock()))
 		if err != nil {
 			return reconcile.Result{}, err
 		}
@@ -122,70 +249,123 @@ func (r CaReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 
 	r.Log.Info("Handling CA Secret")
 
-	rq, err = ca.ExpiresIn(time.Now())
-	if err != nil {
-		r.Log.Info("CA is expired, cleaning to obtain a new one")
-		instance.Data = map[string][]byte{}
-	} else {
-		r.Log.Info("Updating CA secret with new PEM and RSA")
+	// A different RSA key size than the recorded one forces the CA generation
+	recorded, ok := instance.GetAnnotations()[rsaKeySizeAnnotation]
+	keySizeChanged := r.KeyType == cert.RSAKeyType && ok && recorded != strconv.Itoa(r.RsaKeySize)
 
-		var crt *bytes.Buffer
-		var key *bytes.Buffer
-		crt, _ = ca.CaCertificatePem()
-		key, _ = ca.CaPrivateKeyPem()
+	// The rotation can be forced by the cluster administrator, as when the CA private key has been exposed
+	forced := isForcedRotation(instance)
 
-		instance.Data = map[string][]byte{
-			certSecretKey:       crt.Bytes(),
-			privateKeySecretKey: key.Bytes(),
+	// Rotating the CA while it's still valid, the webhooks CABundle and the TLS certificate are updated accordingly
+	if _, err = ca.ExpiresIn(r.clock().Now()); err != nil || ca.RenewIn(r.clock().Now(), cfg.RenewBefore) <= 0 || keySizeChanged || forced {
+		r.Log.Info("CA is expired, approaching its expiration, its key size has been changed or its rotation has been forced, generating a new one")
+		ca, err = cert.GenerateCertificateAuthorityWithOptions(cert.NewCaOpts(r.KeyType, r.RsaKeySize, cfg.CaValidity).WithClock(r.clock()))
+		if err != nil {
+			return reconcile.Result{}, err
 		}
+	}
+	rq = requeueAfter(ca.RenewIn(r.clock().Now(), cfg.RenewBefore), r.MinRequeue)
+	ca.SetKeyEncoding(r.KeyEncoding)
 
-		wg := &sync.WaitGroup{}
-		wg.Add(2)
-		ch := make(chan error, 2)
+	r.Log.Info("Updating CA secret with new PEM and RSA")
 
-		go r.UpdateMutatingWebhookConfiguration(wg, ch, crt.Bytes())
-		go r.UpdateValidatingWebhookConfiguration(wg, ch, crt.Bytes())
+	var crt *bytes.Buffer
+	var key *bytes.Buffer
+	crt, _ = ca.CaCertificatePem()
+	key, _ = ca.CaPrivateKeyPem()
 
-		wg.Wait()
-		close(ch)
+	previous := instance.Data[certSecretKey]
+	rotated := !bytes.Equal(previous, crt.Bytes())
 
-		for err = range ch {
-			if err != nil {
-				return reconcile.Result{}, err
-			}
-		}
+	instance.Data = map[string][]byte{
+		certSecretKey:       crt.Bytes(),
+		privateKeySecretKey: key.Bytes(),
+	}
+
+	if err = r.updateWebhooksCaBundle(ctx, instance, crt.Bytes()); err != nil {
+		return reconcile.Result{}, err
+	}
+	if rotated {
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "CABundleUpdated", "Webhooks CABundle has been updated with the new CA")
 	}
 
-	var res controllerutil.OperationResult
 	t := &corev1.Secret{ObjectMeta: instance.ObjectMeta}
-	res, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, t, func() error {
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, t, func() error {
 		t.Data = instance.Data
-		return nil
+		if t.Annotations == nil {
+			t.Annotations = map[string]string{}
+		}
+		t.Annotations[rsaKeySizeAnnotation] = strconv.Itoa(r.RsaKeySize)
+		t.Annotations[nextCheckAnnotation] = r.clock().Now().Add(rq).UTC().Format(time.RFC3339)
+		delete(t.Annotations, forceRotationAnnotation)
+		setProtectedLabel(t)
+		return setDeploymentOwnerReference(ctx, r.Client, r.Scheme, r.DeploymentName, t)
 	})
 	if err != nil {
 		r.Log.Error(err, "cannot update Capsule TLS")
 		return reconcile.Result{}, err
 	}
 
-	if res == controllerutil.OperationResultUpdated {
-		r.Log.Info("Capsule CA has been updated, we need to trigger TLS update too")
-		tls := &corev1.Secret{}
-		err = r.Get(context.TODO(), types.NamespacedName{
-			Namespace: r.Namespace,
-			Name:      tlsSecretName,
-		}, tls)
-		if err != nil {
-			r.Log.Error(err, "Capsule TLS Secret missing")
+	now := r.clock().Now()
+	d, _ := ca.ExpiresIn(now)
+	setExpiration(caExpiration, now.Add(d))
+
+	if rotated {
+		rotations.WithLabelValues(instance.Name).Inc()
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "CertificateRotated", "CA certificate has been rotated, valid until %s", now.Add(d).UTC().Format(time.RFC3339))
+		if forced {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "ForcedRotation", "CA rotation has been forced, serial number changed from %s to %s", serialNumber(previous), serialNumber(crt.Bytes()))
 		}
-		err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-			_, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, tls, func() error {
-				tls.Data = map[string][]byte{}
-				return nil
-			})
-			return err
-		})
-		if err != nil {
-			r.Log.Error(err, "Cannot clean Capsule TLS Secret due to CA update")
+
+		// The CA Secret could be updated just due to a different key encoding, keeping the issued TLS certificate
+		r.Log.Info("Capsule CA has been updated, issuing a new TLS certificate too")
+		if err = r.reissueTls(ctx, ca); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	r.Log.Info("Reconciliation completed, processing back in " + rq.String())
+	return reconcile.Result{Requeue: true, RequeueAfter: rq}, nil
+}
+
+func (r CaReconciler) isExternalCa() bool {
+	return len(r.CaSecretName) > 0 && r.CaSecretName != caSecretName
+}
+
+// The external CA is provided by the cluster administrator: Capsule never generates nor rotates it, just keeping
+// the webhooks CABundle in sync and triggering the TLS certificate issuing when it's not signed by the current CA.
+func (r CaReconciler) reconcileExternalCa(ctx context.Context, instance *corev1.Secret) (reconcile.Result, error) {
+	ca, err := getCertificateAuthority(ctx, r.Client, r.clock(), r.Namespace, r.CaSecretName)
+	if err != nil {
+		r.Log.Error(err, "Cannot load the external CA")
+		return reconcile.Result{}, err
+	}
+
+	var rq time.Duration
+	now := r.clock().Now()
+	if rq, err = ca.ExpiresIn(now); err != nil {
+		r.Log.Error(err, "The external CA must be rotated by the cluster administrator")
+		return reconcile.Result{}, err
+	}
+	setExpiration(caExpiration, now.Add(rq))
+	rq = requeueAfter(rq, r.MinRequeue)
+
+	var crt *bytes.Buffer
+	if crt, err = ca.CaCertificatePem(); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err = r.updateWebhooksCaBundle(ctx, instance, crt.Bytes()); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	tls := &corev1.Secret{}
+	if err = r.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: r.tlsSecretName()}, tls); err != nil {
+		r.Log.Error(err, "Capsule TLS Secret missing")
+		return reconcile.Result{}, err
+	}
+	if c, err := parseCertificate(tls.Data[certSecretKey]); err != nil || ca.ValidateCert(c) != nil {
+		r.Log.Info("Capsule TLS is not signed by the external CA, issuing a new one")
+		if err = r.reissueTls(ctx, ca); err != nil {
 			return reconcile.Result{}, err
 		}
 	}
@@ -193,3 +373,103 @@ func (r CaReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 	r.Log.Info("Reconciliation completed, processing back in " + rq.String())
 	return reconcile.Result{Requeue: true, RequeueAfter: rq}, nil
 }
+
+func (r CaReconciler) updateWebhooksCaBundle(ctx context.Context, instance *corev1.Secret, caBundle []byte) (err error) {
+	// Webhook configurations are selected by label, falling back to the configured names for the upgraded installations
+	vwl := &v1.ValidatingWebhookConfigurationList{}
+	if err = r.List(ctx, vwl, client.MatchingLabels{caInjectionLabel: caInjectionEnabled}); err != nil {
+		return
+	}
+	vwn := []string{r.ValidatingWebhookConfigurationName}
+	if len(vwl.Items) > 0 {
+		vwn = make([]string, 0, len(vwl.Items))
+		for _, i := range vwl.Items {
+			vwn = append(vwn, i.Name)
+		}
+	}
+
+	mwl := &v1.MutatingWebhookConfigurationList{}
+	if err = r.List(ctx, mwl, client.MatchingLabels{caInjectionLabel: caInjectionEnabled}); err != nil {
+		return
+	}
+	mwn := []string{r.MutatingWebhookConfigurationName}
+	if len(mwl.Items) > 0 {
+		mwn = make([]string, 0, len(mwl.Items))
+		for _, i := range mwl.Items {
+			mwn = append(mwn, i.Name)
+		}
+	}
+
+	n := len(vwn) + len(mwn) + 1
+	wg := &sync.WaitGroup{}
+	wg.Add(n)
+	ch := make(chan error, n)
+
+	run := func(name string, fn func() error) {
+		defer wg.Done()
+		if err := fn(); err != nil {
+			caBundlePatchFailures.WithLabelValues(name).Inc()
+			ch <- err
+		}
+	}
+	for _, name := range mwn {
+		name := name
+		go run(name, func() error { return r.updateMutatingWebhookConfiguration(ctx, name, caBundle) })
+	}
+	for _, name := range vwn {
+		name := name
+		go run(name, func() error { return r.updateValidatingWebhookConfiguration(ctx, name, caBundle) })
+	}
+	go run(tenantCustomResourceDefinitionName, func() error { return r.UpdateCustomResourceDefinition(ctx, caBundle) })
+
+	wg.Wait()
+	close(ch)
+
+	var errs []error
+	for e := range ch {
+		r.Log.Error(e, "cannot update the webhooks CABundle")
+		errs = append(errs, e)
+	}
+	err = utilerrors.NewAggregate(errs)
+	setCaBundleInjectionError(err)
+	if err != nil {
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "CABundleUpdateFailed", "Cannot update the webhooks CABundle: %s", err.Error())
+	}
+	return
+}
+
+// reissueTls replaces the TLS Secret certificate with a new one signed by the current CA in a single update, avoiding
+// the webhook server being left without a certificate.
+func (r CaReconciler) reissueTls(ctx context.Context, ca cert.Ca) (err error) {
+	var data map[string][]byte
+	var c *x509.Certificate
+	if data, c, err = issueTlsCertificate(ca, r.clock(), r.Configuration.Load().TlsValidity, r.TlsExtraSans); err != nil {
+		r.Log.Error(err, "Cannot generate new TLS certificate")
+		return
+	}
+
+	tls := &corev1.Secret{}
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if err = r.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: r.tlsSecretName()}, tls); err != nil {
+			return err
+		}
+		tls.Data = data
+		setProtectedLabel(tls)
+		if err = setDeploymentOwnerReference(ctx, r.Client, r.Scheme, r.DeploymentName, tls); err != nil {
+			return err
+		}
+		return r.Update(ctx, tls, &client.UpdateOptions{})
+	})
+	if err != nil {
+		r.Log.Error(err, "Cannot update Capsule TLS Secret due to CA update")
+		return
+	}
+
+	setExpiration(tlsExpiration, c.NotAfter)
+	rotations.WithLabelValues(r.tlsSecretName()).Inc()
+	r.Recorder.Eventf(tls, corev1.EventTypeNormal, "CertificateRotated", "TLS certificate has been rotated, valid until %s", c.NotAfter.UTC().Format(time.RFC3339))
+	return
+}
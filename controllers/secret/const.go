@@ -16,10 +16,44 @@ limitations under the License.
 
 package secret
 
+import "time"
+
 const (
 	certSecretKey       = "tls.crt"
 	privateKeySecretKey = "tls.key"
 
+	caCertSecretKey       = "ca.crt"
+	caPrivateKeySecretKey = "ca.key"
+
 	caSecretName  = "capsule-ca"
 	tlsSecretName = "capsule-tls"
+
+	webhookServiceDnsName = "capsule-webhook-service.capsule-system.svc"
+
+	// Annotation recording the RSA key size of the generated CA
+	rsaKeySizeAnnotation = "capsule.clastix.io/rsa-key-size"
+
+	// CustomResourceDefinition serving the conversion webhook
+	tenantCustomResourceDefinitionName = "tenants.capsule.clastix.io"
+
+	// Annotation exposing the time of the next CA check
+	nextCheckAnnotation = "capsule.clastix.io/next-check"
+
+	// Annotation forcing the CA rotation, removed once done
+	forceRotationAnnotation = "capsule.clastix.io/force-rotation"
+
+	// Label selecting the Secrets protected by the Capsule Secret webhook
+	protectedSecretLabel = "capsule.clastix.io/protected-secret"
+
+	// Label selecting the webhook configurations the CA bundle must be injected to
+	caInjectionLabel   = "capsule.clastix.io/ca-injection"
+	caInjectionEnabled = "enabled"
+
+	// Annotations used by cert-manager to mark the issued Secrets and the resources handled by the cainjector
+	certManagerCertificateAnnotation        = "cert-manager.io/certificate-name"
+	certManagerInjectCaFromAnnotation       = "cert-manager.io/inject-ca-from"
+	certManagerInjectCaFromSecretAnnotation = "cert-manager.io/inject-ca-from-secret"
 )
+
+// reconcileTimeout is the deadline of a single reconciliation, cancelling the pending API calls once exceeded
+const reconcileTimeout = 30 * time.Second
@@ -16,9 +16,37 @@ limitations under the License.
 
 package secret
 
+import "fmt"
+
 type MissingCaError struct {
 }
 
 func (MissingCaError) Error() string {
 	return "CA has not been created yet, please generate a new"
 }
+
+type MissingCaPrivateKeyError struct {
+	secretName string
+	key        string
+}
+
+func NewMissingCaPrivateKeyError(secretName, key string) error {
+	return &MissingCaPrivateKeyError{secretName: secretName, key: key}
+}
+
+func (m MissingCaPrivateKeyError) Error() string {
+	return fmt.Sprintf("The CA Secret %s is missing the private key %s, cannot issue the TLS certificate", m.secretName, m.key)
+}
+
+type MissingWebhookConfigurationError struct {
+	kind string
+	name string
+}
+
+func NewMissingWebhookConfigurationError(kind, name string) error {
+	return &MissingWebhookConfigurationError{kind: kind, name: name}
+}
+
+func (m MissingWebhookConfigurationError) Error() string {
+	return fmt.Sprintf("The %s %s doesn't exist, cannot inject the CABundle", m.kind, m.name)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// caBundleInjection holds the outcome of the last CABundle injection, shared between the CA reconciler and the
+// readiness check.
+var caBundleInjection = struct {
+	sync.RWMutex
+	err error
+}{}
+
+func setCaBundleInjectionError(err error) {
+	caBundleInjection.Lock()
+	defer caBundleInjection.Unlock()
+	caBundleInjection.err = err
+}
+
+// CaBundleInjectionCheck reports the manager as not ready when the last CABundle injection failed, as happens with
+// missing RBAC to patch the webhook configurations.
+func CaBundleInjectionCheck(_ *http.Request) error {
+	caBundleInjection.RLock()
+	defer caBundleInjection.RUnlock()
+	if caBundleInjection.err != nil {
+		return fmt.Errorf("cannot inject the CABundle: %w", caBundleInjection.err)
+	}
+	return nil
+}
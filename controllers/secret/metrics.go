@@ -0,0 +1,51 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	caExpiration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "capsule_ca_certificate_expiration_seconds",
+		Help: "The expiration of the Capsule CA certificate, as Unix timestamp.",
+	})
+	tlsExpiration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "capsule_tls_certificate_expiration_seconds",
+		Help: "The expiration of the Capsule webhook TLS certificate, as Unix timestamp.",
+	})
+	rotations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "capsule_certificate_rotations_total",
+		Help: "The number of rotations of the Capsule certificates.",
+	}, []string{"secret"})
+	caBundlePatchFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "capsule_webhook_cabundle_patch_failures_total",
+		Help: "The number of failed CABundle patches of the webhook configurations.",
+	}, []string{"configuration"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(caExpiration, tlsExpiration, rotations, caBundlePatchFailures)
+}
+
+func setExpiration(gauge prometheus.Gauge, notAfter time.Time) {
+	gauge.Set(float64(notAfter.Unix()))
+}
@@ -17,44 +17,165 @@ limitations under the License.
 package secret
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"math/rand"
+	"reflect"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/clastix/capsule/pkg/cert"
 )
 
-func getCertificateAuthority(client client.Client, namespace string) (ca cert.Ca, err error) {
+func getCertificateAuthority(ctx context.Context, client client.Client, clock cert.Clock, namespace, name string) (ca cert.Ca, err error) {
 	instance := &corev1.Secret{}
 
-	err = client.Get(context.TODO(), types.NamespacedName{
+	err = client.Get(ctx, types.NamespacedName{
 		Namespace: namespace,
-		Name:      caSecretName,
+		Name:      name,
 	}, instance)
 	if err != nil {
-		return nil, fmt.Errorf("missing secret %s, cannot reconcile", caSecretName)
+		return nil, fmt.Errorf("missing secret %s, cannot reconcile", name)
 	}
 
 	if instance.Data == nil {
 		return nil, MissingCaError{}
 	}
 
-	ca, err = cert.NewCertificateAuthorityFromBytes(instance.Data[certSecretKey], instance.Data[privateKeySecretKey])
+	// Externally provided CA uses the ca.crt and ca.key keys, falling back to the TLS ones
+	crtKey, keyKey := certSecretKey, privateKeySecretKey
+	if _, ok := instance.Data[caCertSecretKey]; ok {
+		crtKey, keyKey = caCertSecretKey, caPrivateKeySecretKey
+	}
+
+	if _, ok := instance.Data[keyKey]; !ok {
+		return nil, NewMissingCaPrivateKeyError(name, keyKey)
+	}
+
+	ca, err = cert.NewCertificateAuthorityFromBytes(instance.Data[crtKey], instance.Data[keyKey])
 	if err != nil {
 		return
 	}
+	ca.SetClock(clock)
 
 	return
 }
 
-func forOptionPerInstanceName(instanceName string) builder.ForOption {
-	return builder.WithPredicates(predicate.Funcs{
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	b, _ := pem.Decode(data)
+	if b == nil {
+		return nil, fmt.Errorf("cannot decode the PEM certificate")
+	}
+	return x509.ParseCertificate(b.Bytes)
+}
+
+// serialNumber returns the hexadecimal serial number of the PEM certificate, if any.
+func serialNumber(data []byte) string {
+	c, err := parseCertificate(data)
+	if err != nil {
+		return "none"
+	}
+	return c.SerialNumber.Text(16)
+}
+
+func webhookSans(extraSans []string) []string {
+	return append([]string{webhookServiceDnsName}, extraSans...)
+}
+
+// issueTlsCertificate returns the TLS Secret data holding a new webhook serving certificate signed by the CA, valid
+// from the current time of the given clock.
+func issueTlsCertificate(ca cert.Ca, clock cert.Clock, validity time.Duration, extraSans []string) (data map[string][]byte, certificate *x509.Certificate, err error) {
+	var crt, key *bytes.Buffer
+	if crt, key, err = ca.GenerateCertificate(cert.NewCertOpts(clock.Now().Add(validity), webhookSans(extraSans)...)); err != nil {
+		return
+	}
+	var caCrt *bytes.Buffer
+	if caCrt, err = ca.CaCertificatePem(); err != nil {
+		return
+	}
+	if certificate, err = parseCertificate(crt.Bytes()); err != nil {
+		return
+	}
+	data = map[string][]byte{
+		certSecretKey:       crt.Bytes(),
+		privateKeySecretKey: key.Bytes(),
+		caCertSecretKey:     caCrt.Bytes(),
+	}
+	return
+}
+
+// emptyTlsData returns the data of a blank kubernetes.io/tls Secret, since the type requires both keys to be present.
+func emptyTlsData() map[string][]byte {
+	return map[string][]byte{
+		certSecretKey:       {},
+		privateKeySecretKey: {},
+	}
+}
+
+// setDeploymentOwnerReference sets the Capsule Deployment as controller of the Secret, letting it to be garbage
+// collected upon Capsule uninstall: a missing Deployment, as running out of the cluster, is tolerated.
+func setDeploymentOwnerReference(ctx context.Context, c client.Client, scheme *runtime.Scheme, deploymentName string, secret *corev1.Secret) error {
+	if len(deploymentName) == 0 {
+		return nil
+	}
+
+	d := &appsv1.Deployment{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: secret.Namespace, Name: deploymentName}, d); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	err := controllerutil.SetControllerReference(d, secret, scheme)
+	if _, ok := err.(*controllerutil.AlreadyOwnedError); ok {
+		return nil
+	}
+	return err
+}
+
+// setProtectedLabel labels the Secret to be selected by the webhook protecting it from the changes not performed by
+// Capsule.
+func setProtectedLabel(secret *corev1.Secret) {
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[protectedSecretLabel] = "true"
+}
+
+// isExternallyManaged returns true when the TLS Secret has been issued by cert-manager: in this case Capsule must not
+// overwrite it.
+func isExternallyManaged(secret *corev1.Secret) (ok bool) {
+	_, ok = secret.GetAnnotations()[certManagerCertificateAnnotation]
+	return
+}
+
+// isCaInjected returns true when the CABundle of the resource is handled by the cert-manager cainjector.
+func isCaInjected(obj metav1.Object) bool {
+	for _, a := range []string{certManagerInjectCaFromAnnotation, certManagerInjectCaFromSecretAnnotation} {
+		if _, ok := obj.GetAnnotations()[a]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func forOptionPerInstanceName(instanceName string, predicates ...predicate.Predicate) builder.Predicates {
+	return builder.WithPredicates(append([]predicate.Predicate{predicate.Funcs{
 		CreateFunc: func(event event.CreateEvent) bool {
 			return filterByName(event.Meta.GetName(), instanceName)
 		},
@@ -67,7 +188,41 @@ func forOptionPerInstanceName(instanceName string) builder.ForOption {
 		GenericFunc: func(genericEvent event.GenericEvent) bool {
 			return filterByName(genericEvent.Meta.GetName(), instanceName)
 		},
-	})
+	}}, predicates...)...)
+}
+
+// dataChangedPredicate filters out the Secret updates not changing its data, as the annotations refresh, unless the
+// rotation has been requested.
+func dataChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+			o, ok := updateEvent.ObjectOld.(*corev1.Secret)
+			if !ok {
+				return true
+			}
+			n, ok := updateEvent.ObjectNew.(*corev1.Secret)
+			if !ok {
+				return true
+			}
+			return !reflect.DeepEqual(o.Data, n.Data) || isForcedRotation(n)
+		},
+	}
+}
+
+// requeueAfter adds a jitter of ±10% to the requeue interval, spreading the rotations of the certificates issued at
+// the same time, and clamps it to the given minimum avoiding hot loops.
+func requeueAfter(d, min time.Duration) time.Duration {
+	if d > 0 {
+		d += time.Duration((rand.Float64()*0.2 - 0.1) * float64(d))
+	}
+	if d < min {
+		return min
+	}
+	return d
+}
+
+func isForcedRotation(obj metav1.Object) bool {
+	return obj.GetAnnotations()[forceRotationAnnotation] == "true"
 }
 
 func filterByName(objName, desired string) bool {
@@ -0,0 +1,76 @@
+package secret
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/clastix/capsule/pkg/cert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func TestIssueTlsCertificate_Clock(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	clock := &fakeClock{now: t0}
+
+	ca, err := cert.GenerateCertificateAuthorityWithOptions(cert.NewCaOpts(cert.ECDSAKeyType, 0, 365*day).WithClock(clock))
+	assert.Nil(t, err)
+
+	_, c, err := issueTlsCertificate(ca, clock, 30*day, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, t0.Add(30*day), c.NotAfter.UTC())
+	assert.Equal(t, t0.Add(-day), c.NotBefore.UTC())
+
+	// the renewal is due once the remaining lifetime, backdated by a day, drops below the given percentage
+	assert.Equal(t, 30*day-31*day/100*20, cert.RenewIn(c, clock.Now(), 20))
+	clock.now = t0.Add(25 * day)
+	assert.True(t, cert.RenewIn(c, clock.Now(), 20) <= 0)
+	assert.Nil(t, ca.ValidateCert(c))
+	clock.now = t0.Add(31 * day)
+	assert.NotNil(t, ca.ValidateCert(c))
+}
+
+func TestGetCertificateAuthority_Clock(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	clock := &fakeClock{now: t0}
+
+	generated, err := cert.GenerateCertificateAuthorityWithOptions(cert.NewCaOpts(cert.ECDSAKeyType, 0, 365*day).WithClock(clock))
+	assert.Nil(t, err)
+	crt, err := generated.CaCertificatePem()
+	assert.Nil(t, err)
+	key, err := generated.CaPrivateKeyPem()
+	assert.Nil(t, err)
+
+	c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: caSecretName, Namespace: "capsule-system"},
+		Data: map[string][]byte{
+			certSecretKey:       crt.Bytes(),
+			privateKeySecretKey: key.Bytes(),
+		},
+	})
+
+	ca, err := getCertificateAuthority(context.TODO(), c, clock, "capsule-system", caSecretName)
+	assert.Nil(t, err)
+
+	// the certificates issued by the loaded CA are validated against the injected clock, not the real one
+	_, issued, err := issueTlsCertificate(ca, clock, 30*day, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, ca.ValidateCert(issued))
+	clock.now = t0.Add(31 * day)
+	assert.NotNil(t, ca.ValidateCert(issued))
+}
@@ -17,21 +17,28 @@ limitations under the License.
 package secret
 
 import (
+	"bytes"
 	"context"
 	"crypto/x509"
-	"encoding/pem"
 	"syscall"
 	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/clastix/capsule/pkg/cert"
+	"github.com/clastix/capsule/pkg/configuration"
 )
 
 type TlsReconciler struct {
@@ -39,14 +46,63 @@ type TlsReconciler struct {
 	Log       logr.Logger
 	Scheme    *runtime.Scheme
 	Namespace string
+	// KeyEncoding is the PEM format of the webhook serving certificate private key.
+	KeyEncoding cert.KeyEncoding
+	// Configuration provides the lifetime of a newly issued webhook serving certificate, along with the percentage of
+	// its lifetime before the expiration when it gets renewed.
+	Configuration *configuration.Store
+	// ExtraSans are the additional DNS names and IP addresses of the webhook serving certificate.
+	ExtraSans []string
+	// CaSecretName is the name of the Secret holding the CA used to sign the webhook serving certificate.
+	CaSecretName string
+	// TlsSecretName is the name of the Secret holding the webhook serving certificate.
+	TlsSecretName string
+	// RestartOnUpdate must be enabled when the webhooks have not been registered due to the missing serving
+	// certificate at startup: otherwise, the webhook server is reloading the updated certificate on its own.
+	RestartOnUpdate bool
+	// DeploymentName is the name of the Capsule Deployment, set as controller of the TLS Secret.
+	DeploymentName string
+	// Recorder emits the Events upon the serving certificate rotation.
+	Recorder record.EventRecorder
+	// Clock provides the current time to issue and renew the serving certificate, defaulting to the real one.
+	Clock cert.Clock
 }
 
 func (r *TlsReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Secret{}, forOptionPerInstanceName(tlsSecretName)).
+		For(&corev1.Secret{}, forOptionPerInstanceName(r.tlsSecretName())).
+		// Any change to the CA, as a restore from a backup, must be verified against the served certificate
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+				return []reconcile.Request{
+					{NamespacedName: types.NamespacedName{Namespace: a.Meta.GetNamespace(), Name: r.tlsSecretName()}},
+				}
+			}),
+		}, forOptionPerInstanceName(r.caSecretName())).
 		Complete(r)
 }
 
+func (r TlsReconciler) clock() cert.Clock {
+	if r.Clock == nil {
+		return cert.RealClock{}
+	}
+	return r.Clock
+}
+
+func (r TlsReconciler) caSecretName() string {
+	if len(r.CaSecretName) > 0 {
+		return r.CaSecretName
+	}
+	return caSecretName
+}
+
+func (r TlsReconciler) tlsSecretName() string {
+	if len(r.TlsSecretName) > 0 {
+		return r.TlsSecretName
+	}
+	return tlsSecretName
+}
+
 func (r TlsReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 	var err error
 
@@ -61,67 +117,110 @@ func (r TlsReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 		return reconcile.Result{}, err
 	}
 
+	if isExternallyManaged(instance) {
+		r.Log.Info("Capsule TLS is managed by cert-manager, skipping")
+		return reconcile.Result{}, nil
+	}
+
+	cfg := r.Configuration.Load()
+
 	var ca cert.Ca
 	var rq time.Duration
 
-	ca, err = getCertificateAuthority(r.Client, r.Namespace)
+	ca, err = getCertificateAuthority(context.TODO(), r.Client, r.clock(), r.Namespace, r.caSecretName())
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	ca.SetKeyEncoding(r.KeyEncoding)
+
+	// Keeping track of the served certificate, the Controller must be restarted just upon its change
+	old := instance.Data[certSecretKey]
 
 	var shouldCreate bool
+	var notAfter time.Time
 	for _, key := range []string{certSecretKey, privateKeySecretKey} {
-		if _, ok := instance.Data[key]; !ok {
+		if len(instance.Data[key]) == 0 {
 			shouldCreate = true
 			break
 		}
 	}
 
+	if !shouldCreate {
+		var c *x509.Certificate
+		c, err = parseCertificate(instance.Data[certSecretKey])
+		if err != nil {
+			r.Log.Error(err, "cannot parse Capsule TLS")
+			return reconcile.Result{}, err
+		}
+
+		rq = cert.RenewIn(c, r.clock().Now(), cfg.RenewBefore)
+		notAfter = c.NotAfter
+
+		switch {
+		case ca.CheckSignature(c) != nil:
+			// The CA has been replaced, e.g. restored from a backup, and the certificate doesn't chain to it anymore
+			r.Log.Info("Capsule TLS is not signed by the current CA, issuing a new one")
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "CertificateSignatureMismatch", "TLS certificate is not signed by the current CA and will be reissued")
+			shouldCreate = true
+		case ca.ValidateCert(c) != nil:
+			r.Log.Info("Capsule TLS is expired or invalid, issuing a new one")
+			shouldCreate = true
+		case rq <= 0:
+			// Renewing the certificate while it's still valid, avoiding webhooks downtime
+			r.Log.Info("Capsule TLS is approaching its expiration, issuing a new one")
+			shouldCreate = true
+		case !cert.HasSans(c, cert.NewCertOpts(c.NotAfter, webhookSans(r.ExtraSans)...)):
+			r.Log.Info("Capsule TLS Subject Alternative Names have been changed, issuing a new one")
+			shouldCreate = true
+		}
+	}
+
 	if shouldCreate {
 		r.Log.Info("Missing Capsule TLS certificate")
-		rq = 6 * 30 * 24 * time.Hour
 
-		opts := cert.NewCertOpts(time.Now().Add(rq), "capsule-webhook-service.capsule-system.svc")
-		crt, key, err := ca.GenerateCertificate(opts)
-		if err != nil {
+		var c *x509.Certificate
+		if instance.Data, c, err = issueTlsCertificate(ca, r.clock(), cfg.TlsValidity, r.ExtraSans); err != nil {
 			r.Log.Error(err, "Cannot generate new TLS certificate")
 			return reconcile.Result{}, err
 		}
-		instance.Data = map[string][]byte{
-			certSecretKey:       crt.Bytes(),
-			privateKeySecretKey: key.Bytes(),
+		// Requeue according to the renewal time of the issued certificate
+		rq = cert.RenewIn(c, r.clock().Now(), cfg.RenewBefore)
+		notAfter = c.NotAfter
+	}
+
+	var updated bool
+	if instance.Type != corev1.SecretTypeTLS {
+		// Legacy Opaque Secret is using the same keys, although the type is immutable and it must be recreated
+		if err = r.migrate(instance); err != nil {
+			r.Log.Error(err, "cannot migrate Capsule TLS to the kubernetes.io/tls type")
+			return reconcile.Result{}, err
 		}
+		updated = !bytes.Equal(old, instance.Data[certSecretKey])
 	} else {
-		var c *x509.Certificate
-		var b *pem.Block
-		b, _ = pem.Decode(instance.Data[certSecretKey])
-		c, err = x509.ParseCertificate(b.Bytes)
+		var res controllerutil.OperationResult
+		t := &corev1.Secret{ObjectMeta: instance.ObjectMeta}
+		res, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, t, func() error {
+			t.Data = instance.Data
+			setProtectedLabel(t)
+			return setDeploymentOwnerReference(context.TODO(), r.Client, r.Scheme, r.DeploymentName, t)
+		})
 		if err != nil {
-			r.Log.Error(err, "cannot parse Capsule TLS")
+			r.Log.Error(err, "cannot update Capsule TLS")
 			return reconcile.Result{}, err
 		}
+		updated = res == controllerutil.OperationResultUpdated && !bytes.Equal(old, instance.Data[certSecretKey])
+	}
 
-		rq = time.Until(c.NotAfter)
-
-		err = ca.ValidateCert(c)
-		if err != nil {
-			r.Log.Info("Capsule TLS is expired or invalid, cleaning to obtain a new one")
-			instance.Data = map[string][]byte{}
-		}
+	if !notAfter.IsZero() {
+		setExpiration(tlsExpiration, notAfter)
 	}
 
-	var res controllerutil.OperationResult
-	t := &corev1.Secret{ObjectMeta: instance.ObjectMeta}
-	res, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, t, func() error {
-		t.Data = instance.Data
-		return nil
-	})
-	if err != nil {
-		r.Log.Error(err, "cannot update Capsule TLS")
-		return reconcile.Result{}, err
+	if updated && shouldCreate {
+		rotations.WithLabelValues(instance.Name).Inc()
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "CertificateRotated", "TLS certificate has been rotated, valid until %s", notAfter.UTC().Format(time.RFC3339))
 	}
 
-	if instance.Name == tlsSecretName && res == controllerutil.OperationResultUpdated {
+	if instance.Name == r.tlsSecretName() && updated && r.RestartOnUpdate && len(instance.Data[certSecretKey]) > 0 {
 		r.Log.Info("Capsule TLS certificates has been updated, we need to restart the Controller")
 		_ = syscall.Kill(syscall.Getpid(), syscall.SIGINT)
 	}
@@ -129,3 +228,32 @@ func (r TlsReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 	r.Log.Info("Reconciliation completed, processing back in " + rq.String())
 	return reconcile.Result{Requeue: true, RequeueAfter: rq}, nil
 }
+
+// migrate replaces the legacy Opaque Secret with a kubernetes.io/tls one, since the Secret type is immutable.
+func (r TlsReconciler) migrate(instance *corev1.Secret) (err error) {
+	r.Log.Info("Migrating Capsule TLS Secret to the kubernetes.io/tls type")
+
+	if err = r.Delete(context.TODO(), instance); err != nil && !apierrors.IsNotFound(err) {
+		return
+	}
+
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        instance.Name,
+			Namespace:   instance.Namespace,
+			Labels:      instance.Labels,
+			Annotations: instance.Annotations,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: emptyTlsData(),
+	}
+	for k, v := range instance.Data {
+		s.Data[k] = v
+	}
+	setProtectedLabel(s)
+	if err = setDeploymentOwnerReference(context.TODO(), r.Client, r.Scheme, r.DeploymentName, s); err != nil {
+		return
+	}
+
+	return r.Create(context.TODO(), s)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/hashicorp/go-multierror"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/pkg/utils"
+)
+
+// ServicesMetadataReconciler keeps the metadata of the existing Services in sync with the Tenant ServicesMetadata,
+// since the mutating webhook applies it only upon the Services creation or update.
+type ServicesMetadataReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+func (r *ServicesMetadataReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("servicesmetadata").
+		For(&capsulev1alpha1.Tenant{}).
+		Complete(r)
+}
+
+func (r ServicesMetadataReconciler) Reconcile(request ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("Request.Name", request.Name)
+
+	tnt := &capsulev1alpha1.Tenant{}
+	if err = r.Get(context.TODO(), request.NamespacedName, tnt); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Request object not found, could have been deleted after reconcile request")
+			return reconcile.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return reconcile.Result{}, err
+	}
+
+	for _, ns := range tnt.Status.Namespaces {
+		// the Services are retrieved from the cache, indexed by Namespace
+		sl := &corev1.ServiceList{}
+		if err := r.List(context.TODO(), sl, client.InNamespace(ns)); err != nil {
+			log.Error(err, "Cannot list Services", "namespace", ns)
+			return reconcile.Result{}, err
+		}
+		for _, svc := range sl.Items {
+			if e := r.syncService(svc, tnt.Spec.ServicesMetadata); e != nil {
+				err = multierror.Append(e, err)
+			}
+		}
+	}
+	if err != nil {
+		log.Error(err, "Cannot sync Services metadata")
+		return reconcile.Result{}, err
+	}
+
+	log.Info("Services metadata reconciling completed")
+	return ctrl.Result{}, nil
+}
+
+// syncService updates the Service metadata only if changed: the labels and annotations set by the users are retained,
+// since only the ones previously applied by Capsule are removed.
+func (r ServicesMetadataReconciler) syncService(svc corev1.Service, metadata capsulev1alpha1.AdditionalMetadata) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		if !utils.SyncObjectMetadata(&svc, metadata) {
+			return nil
+		}
+		if err = r.Update(context.TODO(), &svc); errors.IsConflict(err) {
+			_ = r.Get(context.TODO(), client.ObjectKey{Namespace: svc.GetNamespace(), Name: svc.GetName()}, &svc)
+		}
+		return err
+	})
+}
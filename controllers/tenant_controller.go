@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,7 +28,9 @@ import (
 	"github.com/hashicorp/go-multierror"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,6 +39,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -44,6 +48,12 @@ import (
 
 	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
 	"github.com/clastix/capsule/controllers/rbac"
+	"github.com/clastix/capsule/pkg/utils"
+)
+
+const (
+	// namespacesFinalizer defers the Tenant deletion until its Namespaces have been handled by the deletion policy.
+	namespacesFinalizer = "capsule.clastix.io/namespaces"
 )
 
 // TenantReconciler reconciles a Tenant object
@@ -51,6 +61,9 @@ type TenantReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+	// Recorder emits the Events upon the restore of the managed resources changed by third parties, and upon the
+	// release or deletion of the Namespaces of the deleted Tenants.
+	Recorder record.EventRecorder
 }
 
 func (r *TenantReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -78,6 +91,22 @@ func (r TenantReconciler) Reconcile(request ctrl.Request) (result ctrl.Result, e
 		r.Log.Error(err, "Error reading the object")
 		return reconcile.Result{}, err
 	}
+	// The Tenant is being deleted, its Namespaces are handled according to the deletion policy
+	if instance.GetDeletionTimestamp() != nil {
+		r.Log.Info("Finalizing the Tenant Namespaces", "policy", instance.Spec.NamespaceDeletionPolicy)
+		return reconcile.Result{}, r.finalizeNamespaces(instance)
+	}
+	if err = r.ensureFinalizer(instance); err != nil {
+		r.Log.Error(err, "Cannot add the Tenant finalizer")
+		return reconcile.Result{}, err
+	}
+	// Reporting the reconciliation result as the Ready condition, even upon a failure
+	defer func() {
+		instance.SetCondition(newCondition(capsulev1alpha1.TenantConditionReady, err, "Reconciled", "ReconcileFailed"))
+		if e := r.updateStatus(instance); e != nil {
+			r.Log.Error(e, "Cannot update the Tenant conditions")
+		}
+	}()
 
 	// Ensuring all namespaces are collected
 	r.Log.Info("Ensuring all Namespaces are collected")
@@ -111,20 +140,24 @@ func (r TenantReconciler) Reconcile(request ctrl.Request) (result ctrl.Result, e
 	}
 
 	r.Log.Info("Starting processing of Resource Quotas", "items", len(instance.Spec.ResourceQuota))
-	if err := r.syncResourceQuotas(instance); err != nil {
+	err = r.syncResourceQuotas(instance)
+	instance.SetCondition(newCondition(capsulev1alpha1.TenantConditionQuotaSynced, err, "Synced", "SyncFailed"))
+	if err != nil {
 		r.Log.Error(err, "Cannot sync ResourceQuota items")
 		return reconcile.Result{}, err
 	}
 
 	r.Log.Info("Ensuring RoleBinding for owner")
-	if err := r.ownerRoleBinding(instance); err != nil {
+	if err = r.ownerRoleBinding(instance); err != nil {
 		r.Log.Error(err, "Cannot sync owner RoleBinding")
-		return reconcile.Result{}, err
+	} else {
+		r.Log.Info("Starting processing of additional RoleBindings", "items", len(instance.Spec.AdditionalRoleBindings))
+		if err = r.syncAdditionalRoleBindings(instance); err != nil {
+			r.Log.Error(err, "Cannot sync additional RoleBinding items")
+		}
 	}
-
-	r.Log.Info("Ensuring Namespace count")
-	if err := r.ensureNamespaceCount(instance); err != nil {
-		r.Log.Error(err, "Cannot sync Namespace count")
+	instance.SetCondition(newCondition(capsulev1alpha1.TenantConditionRBACSynced, err, "Synced", "SyncFailed"))
+	if err != nil {
 		return reconcile.Result{}, err
 	}
 
@@ -132,9 +165,165 @@ func (r TenantReconciler) Reconcile(request ctrl.Request) (result ctrl.Result, e
 	return ctrl.Result{}, err
 }
 
+// ensureFinalizer adds the finalizer handling the Tenant Namespaces upon its deletion.
+func (r *TenantReconciler) ensureFinalizer(tenant *capsulev1alpha1.Tenant) error {
+	if hasNamespacesFinalizer(tenant) {
+		return nil
+	}
+	tenant.SetFinalizers(append(tenant.GetFinalizers(), namespacesFinalizer))
+	return r.Update(context.TODO(), tenant)
+}
+
+func hasNamespacesFinalizer(tenant *capsulev1alpha1.Tenant) bool {
+	for _, f := range tenant.GetFinalizers() {
+		if f == namespacesFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// finalizeNamespaces releases or deletes the Tenant Namespaces according to the deletion policy, removing the finalizer
+// once none is left: the Namespaces are collected again at each run, so a deletion interrupted by a restart is resumed.
+// The Namespaces re-assigned to another Tenant are no more controlled by the deleted one, and are skipped.
+func (r *TenantReconciler) finalizeNamespaces(tenant *capsulev1alpha1.Tenant) error {
+	if !hasNamespacesFinalizer(tenant) {
+		return nil
+	}
+
+	nl := &corev1.NamespaceList{}
+	if err := r.Client.List(context.TODO(), nl, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".metadata.ownerReferences[*].capsule", tenant.GetName()),
+	}); err != nil {
+		return err
+	}
+
+	var pending int
+	for i := range nl.Items {
+		ns := &nl.Items[i]
+		if ref := metav1.GetControllerOf(ns); ref == nil || ref.UID != tenant.GetUID() {
+			continue
+		}
+		if tenant.Spec.NamespaceDeletionPolicy == capsulev1alpha1.NamespaceDeletionPolicyDelete {
+			// the Tenant is reconciled again upon the Namespace deletion, since owning it
+			pending++
+			if ns.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if err := r.Delete(context.TODO(), ns); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("cannot delete the Namespace %s: %w", ns.GetName(), err)
+			}
+			r.Recorder.Eventf(tenant, corev1.EventTypeNormal, "NamespaceDeleted", "Namespace %s has been deleted along with the Tenant", ns.GetName())
+			continue
+		}
+		if err := r.orphanNamespace(tenant, ns.GetName()); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(tenant, corev1.EventTypeNormal, "NamespaceOrphaned", "Namespace %s has been released by the Tenant", ns.GetName())
+	}
+	if pending > 0 {
+		r.Log.Info("Waiting for the Tenant Namespaces deletion", "items", pending)
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		found := &capsulev1alpha1.Tenant{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: tenant.GetName()}, found); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		var finalizers []string
+		for _, f := range found.GetFinalizers() {
+			if f != namespacesFinalizer {
+				finalizers = append(finalizers, f)
+			}
+		}
+		found.SetFinalizers(finalizers)
+		return r.Update(context.TODO(), found)
+	})
+}
+
+// orphanNamespace releases the Namespace from the Tenant: the objects created by Capsule are deleted first, since
+// selected by the Tenant label, then the Tenant owner reference, label, and the applied metadata are removed.
+// The Secrets can be labelled by the Tenant users too, so only the ones controlled by the Tenant are deleted.
+func (r *TenantReconciler) orphanNamespace(tenant *capsulev1alpha1.Tenant, namespace string) error {
+	tl, err := capsulev1alpha1.GetTypeLabel(&capsulev1alpha1.Tenant{})
+	if err != nil {
+		return err
+	}
+	for _, obj := range []runtime.Object{&corev1.ResourceQuota{}, &corev1.LimitRange{}, &networkingv1.NetworkPolicy{}, &rbacv1.RoleBinding{}, &policyv1beta1.PodDisruptionBudget{}} {
+		if err := r.DeleteAllOf(context.TODO(), obj, client.InNamespace(namespace), client.MatchingLabels{tl: tenant.GetName()}); err != nil {
+			return fmt.Errorf("cannot delete the %T items in the Namespace %s: %w", obj, namespace, err)
+		}
+	}
+	sl := &corev1.SecretList{}
+	if err = r.List(context.TODO(), sl, client.InNamespace(namespace), client.MatchingLabels{tl: tenant.GetName()}); err != nil {
+		return fmt.Errorf("cannot list the Secrets in the Namespace %s: %w", namespace, err)
+	}
+	for i := range sl.Items {
+		if !metav1.IsControlledBy(&sl.Items[i], tenant) {
+			continue
+		}
+		if err = r.Delete(context.TODO(), &sl.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("cannot delete the Secret %s/%s: %w", namespace, sl.Items[i].GetName(), err)
+		}
+	}
+	// the additional resources can be of any kind, so they're selected by the Namespace tracking annotation
+	ns := &corev1.Namespace{}
+	if err = r.Get(context.TODO(), types.NamespacedName{Name: namespace}, ns); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	applied, err := appliedResources(ns)
+	if err != nil {
+		return err
+	}
+	for _, ar := range applied {
+		if err = deleteAppliedResource(r.Client, tenant.GetName(), namespace, ar); err != nil {
+			return err
+		}
+	}
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		ns := &corev1.Namespace{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: namespace}, ns); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		var refs []metav1.OwnerReference
+		for _, ref := range ns.GetOwnerReferences() {
+			if ref.UID != tenant.GetUID() {
+				refs = append(refs, ref)
+			}
+		}
+		ns.SetOwnerReferences(refs)
+		// removing the additional metadata applied by Capsule, along with the tracking annotations
+		utils.SyncObjectMetadata(ns, capsulev1alpha1.AdditionalMetadata{})
+		l := ns.GetLabels()
+		delete(l, tl)
+		ns.SetLabels(l)
+		a := ns.GetAnnotations()
+		for _, k := range []string{
+			capsulev1alpha1.AvailableIngressClassesAnnotation,
+			capsulev1alpha1.AvailableIngressClassesRegexpAnnotation,
+			capsulev1alpha1.AvailableStorageClassesAnnotation,
+			capsulev1alpha1.AvailableStorageClassesRegexpAnnotation,
+			capsulev1alpha1.AppliedResourcesAnnotation,
+		} {
+			delete(a, k)
+		}
+		ns.SetAnnotations(a)
+		return r.Update(context.TODO(), ns)
+	})
+}
+
 // pruningResources is taking care of removing the no more requested sub-resources as LimitRange, ResourceQuota or
-// NetworkPolicy using the "exists" and "notin" LabelSelector to perform an outer-join removal.
-func (r *TenantReconciler) pruningResources(ns string, keys []string, obj runtime.Object) error {
+// NetworkPolicy using the "exists" and "notin" LabelSelector to perform an outer-join removal: only the objects
+// labelled with the Tenant are selected, the ones owned by Capsule.
+// The sub-resources are named and labelled by their index in the Tenant spec: reordering or removing the entries
+// updates the remaining objects in place, pruning just the exceeding indexes rather than recreating them all.
+func (r *TenantReconciler) pruningResources(tenant *capsulev1alpha1.Tenant, ns string, keys []string, obj runtime.Object) error {
+	tenantLabel, err := capsulev1alpha1.GetTypeLabel(&capsulev1alpha1.Tenant{})
+	if err != nil {
+		return err
+	}
 	capsuleLabel, err := capsulev1alpha1.GetTypeLabel(obj)
 	if err != nil {
 		return err
@@ -142,6 +331,12 @@ func (r *TenantReconciler) pruningResources(ns string, keys []string, obj runtim
 
 	s := labels.NewSelector()
 
+	owned, err := labels.NewRequirement(tenantLabel, selection.Equals, []string{tenant.GetName()})
+	if err != nil {
+		return err
+	}
+	s = s.Add(*owned)
+
 	exists, err := labels.NewRequirement(capsuleLabel, selection.Exists, []string{})
 	if err != nil {
 		return err
@@ -221,9 +416,11 @@ func (r *TenantReconciler) resourceQuotasUpdate(resourceName corev1.ResourceName
 // We're relying on the ResourceQuota resource to represent the resource quota for the single Tenant rather than the
 // single Namespace, so abusing of this API although its Namespaced scope.
 // Since a Namespace could take-up all the available resource quota, the Namespace ResourceQuota will be a 1:1 mapping
-// to the Tenant one: in a second time Capsule is going to sum all the analogous ResourceQuota resources on other Tenant
-// namespaces to check if the Tenant quota has been exceeded or not, reusing the native Kubernetes policy putting the
-// .Status.Used value as the .Hard value.
+// to the Tenant one: Capsule is going to sum all the analogous ResourceQuota resources on other Tenant namespaces to
+// compute the remaining Tenant budget, reusing the native Kubernetes policy putting the .Status.Used value plus an even
+// share of the said budget as the .Hard value of each Namespace.
+// The ResourceQuota status updates trigger the reconciliation, thus the budget is recomputed as soon as the usage
+// changes, also when a Namespace is deleted and its usage released.
 // This will trigger a following reconciliation but that's ok: the mutateFn will re-use the same business logic, letting
 // the mutateFn along with the CreateOrUpdate to don't perform the update since resources are identical.
 func (r *TenantReconciler) syncResourceQuotas(tenant *capsulev1alpha1.Tenant) error {
@@ -243,9 +440,14 @@ func (r *TenantReconciler) syncResourceQuotas(tenant *capsulev1alpha1.Tenant) er
 		return err
 	}
 
+	overrides, err := r.quotaOverrides(tenant)
+	if err != nil {
+		return err
+	}
+
 	for _, ns := range tenant.Status.Namespaces {
-		if err := r.pruningResources(ns, keys, &corev1.ResourceQuota{}); err != nil {
-			return err
+		if err := r.pruningResources(tenant, ns, keys, &corev1.ResourceQuota{}); err != nil {
+			return fmt.Errorf("cannot prune the ResourceQuotas in the Namespace %s: %w", ns, err)
 		}
 		for i, q := range tenant.Spec.ResourceQuota {
 			target := &corev1.ResourceQuota{
@@ -259,7 +461,11 @@ func (r *TenantReconciler) syncResourceQuotas(tenant *capsulev1alpha1.Tenant) er
 					},
 				},
 			}
+			if err := r.ensureResourceQuotaScopes(target.Namespace, target.Name, q); err != nil {
+				return err
+			}
 			res, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, target, func() (err error) {
+				r.recordDrift(tenant, "ResourceQuota", target)
 				// Requirement to list ResourceQuota of the current Tenant
 				tr, err := labels.NewRequirement(tenantLabel, selection.Equals, []string{tenant.Name})
 				if err != nil {
@@ -283,13 +489,31 @@ func (r *TenantReconciler) syncResourceQuotas(tenant *capsulev1alpha1.Tenant) er
 					return err
 				}
 
+				// The reconciled ResourceQuota is updated by the CreateOrUpdate itself, the other ones of the Tenant
+				// are updated separately: excluding it avoids conflicting updates.
+				others := make([]corev1.ResourceQuota, 0, len(rql.Items))
+				for _, rq := range rql.Items {
+					if rq.Namespace == target.Namespace && rq.Name == target.Name {
+						continue
+					}
+					others = append(others, rq)
+				}
+
+				target.Spec.Scopes = append([]corev1.ResourceQuotaScope(nil), q.Scopes...)
+				target.Spec.ScopeSelector = q.ScopeSelector.DeepCopy()
+				target.Spec.Hard = make(corev1.ResourceList, len(q.Hard))
+
 				// Iterating over all the options declared for the ResourceQuota,
 				// summing all the used quota across different Namespaces to determinate
-				// if we're hitting a Hard quota at Tenant level.
-				// For this case, we're going to block the Quota setting the Hard as the
-				// used one.
-				for rn, rq := range q.Hard {
-					r.Log.Info("Desired hard " + rn.String() + " quota is " + rq.String())
+				// the budget still available to the whole Tenant.
+				// Each Namespace hard quota is shrunk to its usage plus an even share of
+				// the remaining budget, the rounding remainder going to the reconciled
+				// one, so the sum across the Tenant Namespaces never exceeds the Tenant
+				// one: once reached, the hard quota is the used one, blocking further
+				// allocations, while the usage released by deleted Namespaces or
+				// resources goes back to the pool.
+				for rn, hard := range q.Hard {
+					r.Log.Info("Desired hard " + rn.String() + " quota is " + hard.String())
 
 					// Getting the whole usage across all the Tenant Namespaces
 					var qt resource.Quantity
@@ -298,47 +522,44 @@ func (r *TenantReconciler) syncResourceQuotas(tenant *capsulev1alpha1.Tenant) er
 					}
 					r.Log.Info("Computed " + rn.String() + " quota for the whole Tenant is " + qt.String())
 
-					switch qt.Cmp(q.Hard[rn]) {
-					case 0:
-						// The Tenant is matching exactly the Quota:
-						// falling through next case since we have to block further
-						// resource allocations.
-						fallthrough
-					case 1:
-						// The Tenant is OverQuota:
-						// updating all the related ResourceQuota with the current
-						// used Quota to block further creations.
-						for i := range rql.Items {
-							if _, ok := rql.Items[i].Status.Used[rn]; ok {
-								rql.Items[i].Spec.Hard[rn] = rql.Items[i].Status.Used[rn]
-							} else {
-								um := make(map[corev1.ResourceName]resource.Quantity)
-								um[rn] = resource.Quantity{}
-								rql.Items[i].Spec.Hard = um
-							}
-						}
-					default:
-						// The Tenant is respecting the Hard quota:
-						// restoring the default one for all the elements,
-						// also for the reconciliated one.
-						for i := range rql.Items {
-							if rql.Items[i].Spec.Hard == nil {
-								rql.Items[i].Spec.Hard = map[corev1.ResourceName]resource.Quantity{}
-							}
-							rql.Items[i].Spec.Hard[rn] = q.Hard[rn]
+					remaining := hard.DeepCopy()
+					remaining.Sub(qt)
+					if remaining.Sign() < 0 {
+						remaining = *resource.NewQuantity(0, hard.Format)
+					}
+
+					share, remainder := splitRemainingQuota(remaining, len(others)+1)
+					for i := range others {
+						if others[i].Spec.Hard == nil {
+							others[i].Spec.Hard = corev1.ResourceList{}
 						}
-						target.Spec = q
+						others[i].Spec.Hard[rn], _ = overriddenHardQuota(namespaceHardQuota(others[i].Status.Used[rn], share), hard, overrides[others[i].Namespace][capsulev1alpha1.QuotaOverrideFor(rn)])
 					}
-					if err := r.resourceQuotasUpdate(rn, qt, rql.Items...); err != nil {
+					if err := r.resourceQuotasUpdate(rn, qt, others...); err != nil {
 						r.Log.Error(err, "cannot proceed with outer ResourceQuota")
 						return err
 					}
+
+					share.Add(remainder)
+					var warning string
+					target.Spec.Hard[rn], warning = overriddenHardQuota(namespaceHardQuota(target.Status.Used[rn], share), hard, overrides[ns][capsulev1alpha1.QuotaOverrideFor(rn)])
+					if len(warning) > 0 {
+						r.Recorder.Eventf(tenant, corev1.EventTypeWarning, "QuotaOverrideClamped", "The Namespace %s %s", ns, warning)
+					}
+					if target.Annotations == nil {
+						target.Annotations = make(map[string]string)
+					}
+					target.Annotations[capsulev1alpha1.UsedQuotaFor(rn)] = qt.String()
 				}
+				managedObjectMetadata(target, tenant.Spec.AdditionalMetadata, map[string]string{
+					tenantLabel: tenant.Name,
+					typeLabel:   strconv.Itoa(i),
+				})
 				return controllerutil.SetControllerReference(tenant, target, r.Scheme)
 			})
 			r.Log.Info("Resource Quota sync result: "+string(res), "name", target.Name, "namespace", target.Namespace)
 			if err != nil {
-				return err
+				return fmt.Errorf("cannot sync the ResourceQuota %s/%s: %w", target.Namespace, target.Name, err)
 			}
 		}
 	}
@@ -346,6 +567,129 @@ func (r *TenantReconciler) syncResourceQuotas(tenant *capsulev1alpha1.Tenant) er
 	return nil
 }
 
+// ensureResourceQuotaScopes deletes the ResourceQuota whose scopes differ from the Tenant ones at the same index, as
+// upon the reordering of the Tenant quotas: the ResourceQuota scopes are immutable, so it's recreated by the
+// CreateOrUpdate rather than failing the update forever.
+func (r *TenantReconciler) ensureResourceQuotaScopes(namespace, name string, spec corev1.ResourceQuotaSpec) error {
+	rq := &corev1.ResourceQuota{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, rq); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if equality.Semantic.DeepEqual(rq.Spec.Scopes, spec.Scopes) && equality.Semantic.DeepEqual(rq.Spec.ScopeSelector, spec.ScopeSelector) {
+		return nil
+	}
+	r.Log.Info("Recreating the ResourceQuota since the scopes changed", "name", name, "namespace", namespace)
+	if err := r.Delete(context.TODO(), rq); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("cannot delete the ResourceQuota %s/%s with outdated scopes: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// managedObjectMetadata applies the Tenant additional metadata to an object created by Capsule in the Tenant
+// Namespaces, removing the entries no more in the spec: the Capsule labels are set afterwards, taking precedence since
+// required for the pruning and the webhook protection.
+func managedObjectMetadata(object metav1.Object, metadata capsulev1alpha1.AdditionalMetadata, capsuleLabels map[string]string) {
+	utils.SyncObjectMetadata(object, metadata)
+	l := object.GetLabels()
+	if l == nil {
+		l = make(map[string]string)
+	}
+	for k, v := range capsuleLabels {
+		l[k] = v
+	}
+	object.SetLabels(l)
+}
+
+// recordDrift emits an Event on the Tenant when the managed object spec has been changed by a third party after
+// Capsule, the field manager owning the controller reference: it's going to be restored by the mutateFn, reporting the
+// field manager as the managed fields are not tracking the user, rather the client, as kubectl.
+func (r *TenantReconciler) recordDrift(tenant *capsulev1alpha1.Tenant, kind string, object metav1.Object) {
+	var owner, latest *metav1.ManagedFieldsEntry
+	fields := object.GetManagedFields()
+	for i := range fields {
+		f := &fields[i]
+		if f.FieldsV1 == nil || f.Time == nil || !strings.Contains(string(f.FieldsV1.Raw), `"f:spec"`) {
+			continue
+		}
+		if strings.Contains(string(f.FieldsV1.Raw), `"f:ownerReferences"`) {
+			owner = f
+		}
+		if latest == nil || latest.Time.Before(f.Time) {
+			latest = f
+		}
+	}
+	if owner == nil || latest == nil || latest.Manager == owner.Manager {
+		return
+	}
+	r.Recorder.Eventf(tenant, corev1.EventTypeWarning, "ManagedResourceRestored", "The %s %s/%s changed by %s has been restored", kind, object.GetNamespace(), object.GetName(), latest.Manager)
+}
+
+// quotaOverrides returns the annotations of the Tenant Namespaces, keyed by name, to look up the quota overrides: these
+// can be set only by the Tenant owners, as enforced by the Namespace metadata webhook.
+func (r *TenantReconciler) quotaOverrides(tenant *capsulev1alpha1.Tenant) (map[string]map[string]string, error) {
+	overrides := make(map[string]map[string]string, len(tenant.Status.Namespaces))
+	for _, name := range tenant.Status.Namespaces {
+		ns := &corev1.Namespace{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: name}, ns); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("cannot retrieve the Namespace %s quota overrides: %w", name, err)
+		}
+		overrides[name] = ns.GetAnnotations()
+	}
+	return overrides, nil
+}
+
+// overriddenHardQuota shrinks the Namespace hard quota to the override requested by the Tenant owner, if any, in turn
+// clamped to the Tenant hard quota: the returned warning reports the invalid or clamped override, if any.
+func overriddenHardQuota(hard, tenantHard resource.Quantity, override string) (resource.Quantity, string) {
+	if len(override) == 0 {
+		return hard, ""
+	}
+	value, err := resource.ParseQuantity(override)
+	if err != nil {
+		return hard, fmt.Sprintf("quota override %s is not a valid quantity and has been ignored", override)
+	}
+	var warning string
+	if value.Cmp(tenantHard) > 0 {
+		warning = fmt.Sprintf("quota override %s exceeds the Tenant hard quota and has been clamped to %s", override, tenantHard.String())
+		value = tenantHard.DeepCopy()
+	}
+	if value.Cmp(hard) < 0 {
+		return value, warning
+	}
+	return hard, warning
+}
+
+// namespaceHardQuota returns the hard quota of a single Namespace, allowing it to consume its share of the remaining
+// Tenant budget on top of its actual usage.
+func namespaceHardQuota(used, share resource.Quantity) resource.Quantity {
+	hard := used.DeepCopy()
+	hard.Add(share)
+	return hard
+}
+
+// splitRemainingQuota splits the remaining Tenant budget evenly across the given number of Namespaces, returning the
+// share of each one along with the rounding remainder, so the shares plus the remainder never exceed the budget: the
+// integral budgets are split in units, the fractional ones, as the CPU cores, in thousandths.
+func splitRemainingQuota(remaining resource.Quantity, namespaces int) (share, remainder resource.Quantity) {
+	if namespaces <= 1 {
+		return remaining.DeepCopy(), *resource.NewQuantity(0, remaining.Format)
+	}
+	n := int64(namespaces)
+	if v := remaining.Value(); remaining.Cmp(*resource.NewQuantity(v, remaining.Format)) == 0 {
+		share = *resource.NewQuantity(v/n, remaining.Format)
+	} else {
+		share = *resource.NewMilliQuantity(remaining.MilliValue()/n, remaining.Format)
+	}
+	remainder = remaining.DeepCopy()
+	for i := int64(0); i < n; i++ {
+		remainder.Sub(share)
+	}
+	return share, remainder
+}
+
 // Ensuring all the LimitRange are applied to each Namespace handled by the Tenant.
 func (r *TenantReconciler) syncLimitRanges(tenant *capsulev1alpha1.Tenant) error {
 	// getting requested LimitRange keys
@@ -365,8 +709,8 @@ func (r *TenantReconciler) syncLimitRanges(tenant *capsulev1alpha1.Tenant) error
 	}
 
 	for _, ns := range tenant.Status.Namespaces {
-		if err := r.pruningResources(ns, keys, &corev1.LimitRange{}); err != nil {
-			return err
+		if err := r.pruningResources(tenant, ns, keys, &corev1.LimitRange{}); err != nil {
+			return fmt.Errorf("cannot prune the LimitRanges in the Namespace %s: %w", ns, err)
 		}
 		for i, spec := range tenant.Spec.LimitRanges {
 			t := &corev1.LimitRange{
@@ -376,16 +720,17 @@ func (r *TenantReconciler) syncLimitRanges(tenant *capsulev1alpha1.Tenant) error
 				},
 			}
 			res, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, t, func() (err error) {
-				t.ObjectMeta.Labels = map[string]string{
+				r.recordDrift(tenant, "LimitRange", t)
+				managedObjectMetadata(t, tenant.Spec.AdditionalMetadata, map[string]string{
 					tl: tenant.Name,
 					ll: strconv.Itoa(i),
-				}
+				})
 				t.Spec = spec
 				return controllerutil.SetControllerReference(tenant, t, r.Scheme)
 			})
 			r.Log.Info("LimitRange sync result: "+string(res), "name", t.Name, "namespace", t.Namespace)
 			if err != nil {
-				return err
+				return fmt.Errorf("cannot sync the LimitRange %s/%s: %w", t.Namespace, t.Name, err)
 			}
 		}
 	}
@@ -399,6 +744,7 @@ func (r *TenantReconciler) syncNamespace(namespace string, ingressClassesSpec ca
 	ns := &corev1.Namespace{}
 	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: namespace}, ns); err != nil {
 		channel <- err
+		return
 	}
 
 	channel <- retry.RetryOnConflict(retry.DefaultBackoff, func() error {
@@ -419,12 +765,6 @@ func (r *TenantReconciler) syncNamespace(namespace string, ingressClassesSpec ca
 			a[capsulev1alpha1.AvailableStorageClassesRegexpAnnotation] = storageClassesSpec.AllowedRegex
 		}
 
-		if aa := nsMetadata.AdditionalAnnotations; aa != nil {
-			for k, v := range aa {
-				a[k] = v
-			}
-		}
-
 		l := ns.GetLabels()
 		if l == nil {
 			l = make(map[string]string)
@@ -434,14 +774,11 @@ func (r *TenantReconciler) syncNamespace(namespace string, ingressClassesSpec ca
 			return err
 		}
 		l[capsuleLabel] = tenantLabel
-		if al := nsMetadata.AdditionalLabels; al != nil {
-			for k, v := range al {
-				l[k] = v
-			}
-		}
 
 		ns.SetLabels(l)
 		ns.SetAnnotations(a)
+		// removing the additional annotations and labels no more in the Tenant spec, previously applied by Capsule
+		utils.SyncObjectMetadata(ns, nsMetadata)
 
 		return r.Client.Update(context.TODO(), ns, &client.UpdateOptions{})
 	})
@@ -488,7 +825,7 @@ func (r *TenantReconciler) syncNetworkPolicies(tenant *capsulev1alpha1.Tenant) e
 	}
 
 	for _, ns := range tenant.Status.Namespaces {
-		if err := r.pruningResources(ns, keys, &networkingv1.NetworkPolicy{}); err != nil {
+		if err := r.pruningResources(tenant, ns, keys, &networkingv1.NetworkPolicy{}); err != nil {
 			return err
 		}
 		for i, spec := range tenant.Spec.NetworkPolicies {
@@ -496,13 +833,14 @@ func (r *TenantReconciler) syncNetworkPolicies(tenant *capsulev1alpha1.Tenant) e
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      fmt.Sprintf("capsule-%s-%d", tenant.Name, i),
 					Namespace: ns,
-					Labels: map[string]string{
-						tl: tenant.Name,
-						nl: strconv.Itoa(i),
-					},
 				},
 			}
 			res, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, t, func() (err error) {
+				// labels are restored too, since these are required for the pruning and the webhook protection
+				managedObjectMetadata(t, tenant.Spec.AdditionalMetadata, map[string]string{
+					tl: tenant.Name,
+					nl: strconv.Itoa(i),
+				})
 				t.Spec = spec
 				return controllerutil.SetControllerReference(tenant, t, r.Scheme)
 			})
@@ -528,11 +866,20 @@ func (r *TenantReconciler) ownerRoleBinding(tenant *capsulev1alpha1.Tenant) erro
 	}
 
 	l := map[string]string{tl: tenant.Name}
-	s := []rbacv1.Subject{
-		{
-			Kind: tenant.Spec.Owner.Kind.String(),
-			Name: tenant.Spec.Owner.Name,
-		},
+	// each Tenant owner is bound to the same roles
+	s := make([]rbacv1.Subject, 0, len(tenant.GetOwners()))
+	for _, o := range tenant.GetOwners() {
+		subject := rbacv1.Subject{
+			Kind: o.Kind.String(),
+			Name: o.Name,
+		}
+		// ServiceAccount subjects are namespaced and referred by their name, rather than by the username
+		if o.Kind == "ServiceAccount" {
+			if subject.Namespace, subject.Name, err = o.GetServiceAccountNamespacedName(); err != nil {
+				return err
+			}
+		}
+		s = append(s, subject)
 	}
 
 	rbl := make(map[types.NamespacedName]rbacv1.RoleRef)
@@ -547,6 +894,24 @@ func (r *TenantReconciler) ownerRoleBinding(tenant *capsulev1alpha1.Tenant) erro
 			Kind:     "ClusterRole",
 			Name:     rbac.DeleterRoleName,
 		}
+		// the Namespace update is granted only if the owners are allowed to change some of its metadata
+		if tenant.IsNamespaceMetadataEditable() {
+			rbl[types.NamespacedName{Namespace: i, Name: "namespace-metadata-editor"}] = rbacv1.RoleRef{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "ClusterRole",
+				Name:     rbac.MetadataEditorRoleName,
+			}
+			continue
+		}
+		rb := &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "namespace-metadata-editor",
+				Namespace: i,
+			},
+		}
+		if err = r.Delete(context.TODO(), rb); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("cannot delete the RoleBinding %s/%s: %w", rb.Namespace, rb.Name, err)
+		}
 	}
 
 	for nn, rr := range rbl {
@@ -559,19 +924,80 @@ func (r *TenantReconciler) ownerRoleBinding(tenant *capsulev1alpha1.Tenant) erro
 
 		var res controllerutil.OperationResult
 		res, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, target, func() (err error) {
-			target.ObjectMeta.Labels = l
+			managedObjectMetadata(target, tenant.Spec.AdditionalMetadata, l)
 			target.Subjects = s
 			target.RoleRef = rr
 			return controllerutil.SetControllerReference(tenant, target, r.Scheme)
 		})
 		r.Log.Info("Role Binding sync result: "+string(res), "name", target.Name, "namespace", target.Namespace)
 		if err != nil {
-			return err
+			return fmt.Errorf("cannot sync the RoleBinding %s/%s: %w", target.Namespace, target.Name, err)
 		}
 	}
 	return nil
 }
 
+// Ensuring all the additional RoleBindings are applied to each Namespace handled by the Tenant, as for auditing or
+// deploying purposes, pruning the ones no more requested.
+func (r *TenantReconciler) syncAdditionalRoleBindings(tenant *capsulev1alpha1.Tenant) error {
+	// getting requested RoleBinding keys
+	keys := make([]string, 0, len(tenant.Spec.AdditionalRoleBindings))
+	for i := range tenant.Spec.AdditionalRoleBindings {
+		keys = append(keys, strconv.Itoa(i))
+	}
+
+	// getting RoleBinding labels for the mutateFn
+	tl, err := capsulev1alpha1.GetTypeLabel(&capsulev1alpha1.Tenant{})
+	if err != nil {
+		return err
+	}
+	rl, err := capsulev1alpha1.GetTypeLabel(&rbacv1.RoleBinding{})
+	if err != nil {
+		return err
+	}
+
+	for _, ns := range tenant.Status.Namespaces {
+		if err := r.pruningResources(tenant, ns, keys, &rbacv1.RoleBinding{}); err != nil {
+			return fmt.Errorf("cannot prune the RoleBindings in the Namespace %s: %w", ns, err)
+		}
+		for i, binding := range tenant.Spec.AdditionalRoleBindings {
+			t := &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("capsule-%s-%d", tenant.Name, i),
+					Namespace: ns,
+				},
+			}
+			rr := rbacv1.RoleRef{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "ClusterRole",
+				Name:     binding.ClusterRoleName,
+			}
+			// the RoleRef is immutable: the RoleBinding must be recreated when the ClusterRole changes
+			found := &rbacv1.RoleBinding{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Namespace: t.Namespace, Name: t.Name}, found); err == nil && found.RoleRef != rr {
+				if err := r.Delete(context.TODO(), found); err != nil && !errors.IsNotFound(err) {
+					return fmt.Errorf("cannot recreate the RoleBinding %s/%s: %w", found.Namespace, found.Name, err)
+				}
+			}
+			res, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, t, func() (err error) {
+				managedObjectMetadata(t, tenant.Spec.AdditionalMetadata, map[string]string{
+					tl: tenant.Name,
+					rl: strconv.Itoa(i),
+				})
+				t.RoleRef = rr
+				t.Subjects = binding.Subjects
+				return controllerutil.SetControllerReference(tenant, t, r.Scheme)
+			})
+			r.Log.Info("Additional Role Binding sync result: "+string(res), "name", t.Name, "namespace", t.Namespace)
+			if err != nil {
+				return fmt.Errorf("cannot sync the RoleBinding %s/%s: %w", t.Namespace, t.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (r *TenantReconciler) ensureNodeSelector(tenant *capsulev1alpha1.Tenant) (err error) {
 	if tenant.Spec.NodeSelector == nil {
 		return
@@ -598,6 +1024,8 @@ func (r *TenantReconciler) ensureNodeSelector(tenant *capsulev1alpha1.Tenant) (e
 			for k, v := range selectorMap {
 				selector = append(selector, fmt.Sprintf("%s=%s", k, v))
 			}
+			// sorting the selector to avoid a different annotation at each reconciliation
+			sort.Strings(selector)
 			ns.Annotations["scheduler.alpha.kubernetes.io/node-selector"] = strings.Join(selector, ",")
 			return nil
 		})
@@ -610,18 +1038,6 @@ func (r *TenantReconciler) ensureNodeSelector(tenant *capsulev1alpha1.Tenant) (e
 	return
 }
 
-func (r *TenantReconciler) ensureNamespaceCount(tenant *capsulev1alpha1.Tenant) error {
-	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-		tenant.Status.Size = uint(tenant.Status.Namespaces.Len())
-		found := &capsulev1alpha1.Tenant{}
-		if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: tenant.GetName()}, found); err != nil {
-			return err
-		}
-		found.Status.Size = tenant.Status.Size
-		return r.Client.Status().Update(context.TODO(), found, &client.UpdateOptions{})
-	})
-}
-
 func (r *TenantReconciler) collectNamespaces(tenant *capsulev1alpha1.Tenant) (err error) {
 	nl := &corev1.NamespaceList{}
 	err = r.Client.List(context.TODO(), nl, client.MatchingFieldsSelector{
@@ -631,8 +1047,61 @@ func (r *TenantReconciler) collectNamespaces(tenant *capsulev1alpha1.Tenant) (er
 		return
 	}
 	tenant.AssignNamespaces(nl.Items)
-	_, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, tenant.DeepCopy(), func() error {
-		return r.Client.Status().Update(context.TODO(), tenant, &client.UpdateOptions{})
+	tenant.AssignState()
+	tenant.SetCondition(namespaceQuotaCondition(tenant))
+	return r.updateStatus(tenant)
+}
+
+// namespaceQuotaCondition reports the Tenant Namespaces exceeding the quota, since lowered below the usage: these are
+// never deleted, although the creation of new ones is denied until the count drops below the quota.
+func namespaceQuotaCondition(tenant *capsulev1alpha1.Tenant) capsulev1alpha1.TenantCondition {
+	condition := capsulev1alpha1.TenantCondition{
+		Type:               capsulev1alpha1.TenantConditionNamespaceQuotaViolated,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             "WithinQuota",
+	}
+	if tenant.Status.Namespaces.Len() > int(tenant.Spec.NamespaceQuota) {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "QuotaExceeded"
+		condition.Message = fmt.Sprintf("The Tenant has %d Namespaces, exceeding the quota of %d: the creation of new ones is denied until some are deleted", tenant.Status.Namespaces.Len(), tenant.Spec.NamespaceQuota)
+	}
+	return condition
+}
+
+// newCondition returns the condition of a reconciliation step, reporting its error as message.
+func newCondition(conditionType capsulev1alpha1.TenantConditionType, err error, reason, failureReason string) capsulev1alpha1.TenantCondition {
+	condition := capsulev1alpha1.TenantCondition{
+		Type:               conditionType,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+	}
+	if err != nil {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = failureReason
+		condition.Message = err.Error()
+	}
+	return condition
+}
+
+// updateStatus updates the Tenant status with the collected Namespaces and the current state: the update is relying on
+// the resource version, since the Namespace quota webhook is reserving the slots of the Namespaces being created in the
+// status, and these must not be overwritten.
+func (r *TenantReconciler) updateStatus(tenant *capsulev1alpha1.Tenant) error {
+	return retry.OnError(retry.DefaultBackoff, func(err error) bool {
+		return !errors.IsNotFound(err)
+	}, func() error {
+		found := &capsulev1alpha1.Tenant{}
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: tenant.GetName()}, found); err != nil {
+			return err
+		}
+		found.Status.Namespaces = tenant.Status.Namespaces
+		found.Status.Size = tenant.Status.Size
+		found.Status.State = tenant.Status.State
+		found.Status.Conditions = tenant.Status.Conditions
+		found.PruneReservations()
+		found.Status.NamespaceQuotaExhausted = found.IsFull()
+		return r.Client.Status().Update(context.TODO(), found)
 	})
-	return
 }
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+)
+
+func TestNamespaceHardQuota(t *testing.T) {
+	hard := namespaceHardQuota(resource.MustParse("7"), resource.MustParse("1"))
+	assert.Equal(t, int64(8), hard.Value())
+
+	hard = namespaceHardQuota(resource.MustParse("1Gi"), resource.MustParse("512Mi"))
+	expected := resource.MustParse("1536Mi")
+	assert.Equal(t, expected.Value(), hard.Value())
+}
+
+func TestSplitRemainingQuota(t *testing.T) {
+	type testCase struct {
+		hard      string
+		used      []string
+		share     string
+		remainder string
+	}
+
+	for _, tc := range []testCase{
+		{"10", []string{"7"}, "3", "0"},
+		{"10", []string{"7", "0", "0"}, "1", "0"},
+		{"10", []string{"2", "0", "0"}, "2", "2"},
+		{"10", []string{"10", "0"}, "0", "0"},
+		{"16Gi", []string{"1Gi", "0", "0"}, "5Gi", "0"},
+		{"1500m", []string{"0", "0", "0", "0"}, "375m", "0"},
+		{"1", []string{"250m", "0", "0", "0"}, "187m", "2m"},
+	} {
+		hard := resource.MustParse(tc.hard)
+
+		var qt resource.Quantity
+		for _, u := range tc.used {
+			qt.Add(resource.MustParse(u))
+		}
+		remaining := hard.DeepCopy()
+		remaining.Sub(qt)
+
+		share, remainder := splitRemainingQuota(remaining, len(tc.used))
+		assert.Equal(t, 0, share.Cmp(resource.MustParse(tc.share)), "share of %s is %s", tc.hard, share.String())
+		assert.Equal(t, 0, remainder.Cmp(resource.MustParse(tc.remainder)), "remainder of %s is %s", tc.hard, remainder.String())
+
+		// the sum of the Namespaces hard quotas, the remainder going to the last one, never exceeds the Tenant one
+		var sum resource.Quantity
+		for i, u := range tc.used {
+			s := share.DeepCopy()
+			if i == len(tc.used)-1 {
+				s.Add(remainder)
+			}
+			h := namespaceHardQuota(resource.MustParse(u), s)
+			sum.Add(h)
+		}
+		assert.True(t, sum.Cmp(hard) <= 0, "sum %s exceeds %s", sum.String(), tc.hard)
+	}
+}
+
+func TestOrphanNamespace_Secrets(t *testing.T) {
+	tnt := &capsulev1alpha1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "oil", UID: "0i1"}}
+	tl, err := capsulev1alpha1.GetTypeLabel(tnt)
+	assert.Nil(t, err)
+
+	secret := func(name string, labels map[string]string, controlled bool) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "oil-production", Labels: labels}}
+		if controlled {
+			s.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(tnt, capsulev1alpha1.GroupVersion.WithKind("Tenant"))})
+		}
+		return s
+	}
+	r := &TenantReconciler{
+		Client: fake.NewFakeClientWithScheme(clientgoscheme.Scheme,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "oil-production", Labels: map[string]string{tl: tnt.GetName()}}},
+			secret("registry", map[string]string{tl: tnt.GetName(), capsulev1alpha1.ImagePullSecretLabel: "registries"}, true),
+			secret("user-labelled", map[string]string{tl: tnt.GetName()}, false),
+			secret("user-forged", map[string]string{tl: tnt.GetName(), capsulev1alpha1.ImagePullSecretLabel: "registries"}, false),
+		),
+		Log: ctrl.Log,
+	}
+
+	assert.Nil(t, r.orphanNamespace(tnt, "oil-production"))
+	for name, kept := range map[string]bool{"registry": false, "user-labelled": true, "user-forged": true} {
+		err := r.Get(context.TODO(), types.NamespacedName{Namespace: "oil-production", Name: name}, &corev1.Secret{})
+		if kept {
+			assert.Nil(t, err, name)
+		} else {
+			assert.True(t, errors.IsNotFound(err), name)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/clastix/capsule/pkg/configuration"
+)
+
+// InstalledFailurePoliciesAnnotation records on the webhook configurations the failure policies the overridden
+// webhooks have been installed with, restored once removed from the CapsuleConfiguration.
+const InstalledFailurePoliciesAnnotation = "capsule.clastix.io/installed-failure-policies"
+
+// WebhookFailurePolicyReconciler sets the failure policy of the Capsule webhooks from the CapsuleConfiguration, so
+// the cluster administrators can choose, webhook by webhook, between blocking the requests and letting them escape
+// the Tenant policies when Capsule is not available.
+type WebhookFailurePolicyReconciler struct {
+	client.Client
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	Configuration *configuration.Store
+	Recorder      record.EventRecorder
+	// ValidatingWebhookConfigurationName is the name of the ValidatingWebhookConfiguration of the Capsule webhooks.
+	ValidatingWebhookConfigurationName string
+	// MutatingWebhookConfigurationName is the name of the MutatingWebhookConfiguration of the Capsule webhooks.
+	MutatingWebhookConfigurationName string
+}
+
+func (r *WebhookFailurePolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	for name, obj := range map[string]runtime.Object{
+		r.ValidatingWebhookConfigurationName: &admissionregistrationv1.ValidatingWebhookConfiguration{},
+		r.MutatingWebhookConfigurationName:   &admissionregistrationv1.MutatingWebhookConfiguration{},
+	} {
+		name := name
+		err := ctrl.NewControllerManagedBy(mgr).
+			For(obj, builder.WithPredicates(predicate.Funcs{
+				CreateFunc: func(event event.CreateEvent) bool {
+					return event.Meta.GetName() == name
+				},
+				DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
+					return deleteEvent.Meta.GetName() == name
+				},
+				UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+					return updateEvent.MetaNew.GetName() == name
+				},
+				GenericFunc: func(genericEvent event.GenericEvent) bool {
+					return genericEvent.Meta.GetName() == name
+				},
+			})).
+			// the failure policies can be changed at runtime by the CapsuleConfiguration
+			Watches(&source.Channel{Source: r.Configuration.Subscribe()}, &handler.EnqueueRequestsFromMapFunc{
+				ToRequests: handler.ToRequestsFunc(func(handler.MapObject) []reconcile.Request {
+					return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+				}),
+			}).
+			Complete(r)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r WebhookFailurePolicyReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("Request.Name", request.Name)
+
+	var obj runtime.Object
+	switch request.Name {
+	case r.ValidatingWebhookConfigurationName:
+		obj = &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	case r.MutatingWebhookConfigurationName:
+		obj = &admissionregistrationv1.MutatingWebhookConfiguration{}
+	default:
+		return reconcile.Result{}, nil
+	}
+	if err := r.Get(context.TODO(), request.NamespacedName, obj); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Webhook configuration not found, skipping")
+			return reconcile.Result{}, nil
+		}
+		log.Error(err, "Error reading the object")
+		return reconcile.Result{}, err
+	}
+	original := obj.DeepCopyObject()
+
+	var names []string
+	var policies []**admissionregistrationv1.FailurePolicyType
+	switch wc := obj.(type) {
+	case *admissionregistrationv1.ValidatingWebhookConfiguration:
+		for i := range wc.Webhooks {
+			names = append(names, wc.Webhooks[i].Name)
+			policies = append(policies, &wc.Webhooks[i].FailurePolicy)
+		}
+	case *admissionregistrationv1.MutatingWebhookConfiguration:
+		for i := range wc.Webhooks {
+			names = append(names, wc.Webhooks[i].Name)
+			policies = append(policies, &wc.Webhooks[i].FailurePolicy)
+		}
+	}
+
+	changed, err := r.applyFailurePolicies(obj.(metav1.Object), names, policies)
+	if err != nil {
+		log.Error(err, "Cannot compute the failure policies")
+		return reconcile.Result{}, err
+	}
+	if equality.Semantic.DeepEqual(original, obj) {
+		return reconcile.Result{}, nil
+	}
+	if err = r.Update(context.TODO(), obj); err != nil {
+		log.Error(err, "Cannot update the failure policies")
+		return reconcile.Result{}, err
+	}
+	if len(changed) > 0 {
+		log.Info("Failure policies updated", "webhooks", changed)
+		r.Recorder.Eventf(obj, corev1.EventTypeNormal, "FailurePolicyUpdated", "Failure policy of %s", strings.Join(changed, ", "))
+	}
+	return reconcile.Result{}, nil
+}
+
+// applyFailurePolicies sets the failure policy of each webhook, returning the changed ones: the policy a webhook has
+// been installed with is recorded in the annotation as soon as it's overridden by the CapsuleConfiguration, restoring
+// it once the override is removed.
+func (r WebhookFailurePolicyReconciler) applyFailurePolicies(obj metav1.Object, names []string, policies []**admissionregistrationv1.FailurePolicyType) (changed []string, err error) {
+	installed := make(map[string]admissionregistrationv1.FailurePolicyType)
+	if v, ok := obj.GetAnnotations()[InstalledFailurePoliciesAnnotation]; ok {
+		if err = json.Unmarshal([]byte(v), &installed); err != nil {
+			return nil, fmt.Errorf("cannot decode the %s annotation: %w", InstalledFailurePoliciesAnnotation, err)
+		}
+	}
+
+	overrides := r.Configuration.Load().WebhookFailurePolicies
+	for i, name := range names {
+		current := failurePolicyOf(*policies[i])
+		desired := current
+		if override, ok := overrides[name]; ok {
+			if _, recorded := installed[name]; !recorded {
+				installed[name] = current
+			}
+			desired = admissionregistrationv1.FailurePolicyType(override)
+		} else if policy, recorded := installed[name]; recorded {
+			desired = policy
+			delete(installed, name)
+		}
+		if desired != current {
+			*policies[i] = &desired
+			changed = append(changed, fmt.Sprintf("%s to %s", name, desired))
+		}
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	delete(annotations, InstalledFailurePoliciesAnnotation)
+	if len(installed) > 0 {
+		v, _ := json.Marshal(installed)
+		annotations[InstalledFailurePoliciesAnnotation] = string(v)
+	}
+	obj.SetAnnotations(annotations)
+	return changed, nil
+}
+
+// failurePolicyOf returns the failure policy of a webhook, defaulted by the API server to Fail when missing.
+func failurePolicyOf(policy *admissionregistrationv1.FailurePolicyType) admissionregistrationv1.FailurePolicyType {
+	if policy == nil {
+		return admissionregistrationv1.Fail
+	}
+	return *policy
+}
@@ -0,0 +1,104 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+var _ = Describe("creating a Namespace over-quota as member of the break-glass group", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "breakglass",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "brenda",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     1,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	cfg := &v1alpha1.CapsuleConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: v1alpha1.CapsuleConfigurationName,
+		},
+		Spec: v1alpha1.CapsuleConfigurationSpec{
+			BreakGlassGroups: []string{"capsule:break-glass"},
+		},
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		cfg.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), cfg)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		if err := k8sClient.Delete(context.TODO(), cfg); err != nil && !errors.IsNotFound(err) {
+			Expect(err).ToNot(HaveOccurred())
+		}
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should be allowed and recorded", func() {
+		ns := NewNamespace("brenda-first")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		over := NewNamespace("brenda-second")
+		By("denying the Tenant owner", func() {
+			_, err := ownerClient(tnt).CoreV1().Namespaces().Create(context.TODO(), over, metav1.CreateOptions{})
+			Expect(err).Should(HaveDenialReason(capsulewebhook.ReasonNamespaceQuotaExceeded))
+		})
+		By("allowing the break-glass group", func() {
+			cs := groupsClient(tnt.Spec.Owner.Name, "capsule:break-glass")
+			Eventually(func() (err error) {
+				_, err = cs.CoreV1().Namespaces().Create(context.TODO(), over, metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+		By("recording the overridden denial", func() {
+			Eventually(func() bool {
+				el := &corev1.EventList{}
+				Expect(k8sClient.List(context.TODO(), el)).Should(Succeed())
+				for _, e := range el.Items {
+					if e.Reason == "BreakGlass" && e.InvolvedObject.Kind == "Namespace" && e.InvolvedObject.Name == over.GetName() {
+						return true
+					}
+				}
+				return false
+			}, defaultTimeoutInterval, defaultPollInterval).Should(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,126 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/controllers/rbac"
+)
+
+var _ = Describe("changing the Capsule settings with the CapsuleConfiguration", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "configurationtenant",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "yusuf",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     10,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	forceTenantPrefix := true
+	cfg := &v1alpha1.CapsuleConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: v1alpha1.CapsuleConfigurationName,
+		},
+		Spec: v1alpha1.CapsuleConfigurationSpec{
+			UserGroups:        []string{"capsule.clastix.io", "projectcapsule:users"},
+			ForceTenantPrefix: &forceTenantPrefix,
+		},
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+		if err := k8sClient.Delete(context.TODO(), cfg); err != nil && !errors.IsNotFound(err) {
+			Expect(err).ToNot(HaveOccurred())
+		}
+	})
+	It("should be applied without restarting Capsule", func() {
+		cfg.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), cfg)).Should(Succeed())
+
+		By("binding the configured user groups", func() {
+			Eventually(func() []string {
+				crb := &rbacv1.ClusterRoleBinding{}
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: rbac.ProvisionerRoleName}, crb)).Should(Succeed())
+				var groups []string
+				for _, s := range crb.Subjects {
+					groups = append(groups, s.Name)
+				}
+				return groups
+			}, defaultTimeoutInterval, defaultPollInterval).Should(ConsistOf("capsule.clastix.io", "projectcapsule:users"))
+		})
+		By("forcing the Tenant prefix", func() {
+			NamespaceCreationShouldNotSucceed(NewNamespace("no-prefix"), tnt, defaultTimeoutInterval)
+			ns := NewNamespace(tnt.GetName() + "-prefixed")
+			NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+			NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+		})
+		By("restoring the command line options upon deletion", func() {
+			Expect(k8sClient.Delete(context.TODO(), cfg)).Should(Succeed())
+			CapsuleClusterGroupParamShouldBeUpdated("capsule.clastix.io", defaultTimeoutInterval)
+			ns := NewNamespace("yusuf-no-prefix")
+			NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+			NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+		})
+	})
+	It("should keep the previous settings when invalid", func() {
+		invalid := cfg.DeepCopy()
+		invalid.ResourceVersion = ""
+		invalid.Spec.ProtectedNamespaceRegexpString = "[invalid"
+		Expect(k8sClient.Create(context.TODO(), invalid)).Should(Succeed())
+
+		Eventually(func() bool {
+			el := &corev1.EventList{}
+			Expect(k8sClient.List(context.TODO(), el)).Should(Succeed())
+			for _, e := range el.Items {
+				if e.Reason == "InvalidConfiguration" && e.InvolvedObject.Name == v1alpha1.CapsuleConfigurationName {
+					return true
+				}
+			}
+			return false
+		}, defaultTimeoutInterval, defaultPollInterval).Should(BeTrue())
+
+		ns := NewNamespace("yusuf-invalid")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+	})
+})
@@ -0,0 +1,113 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("enforcing a Container Registry", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "container-registry",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "matt",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			ContainerRegistries: v1alpha1.ContainerRegistriesSpec{
+				AllowedRegex: "quay.io/.*",
+			},
+			LimitRanges:     []corev1.LimitRangeSpec{},
+			NamespaceQuota:  3,
+			NodeSelector:    map[string]string{},
+			NetworkPolicies: []networkingv1.NetworkPolicySpec{},
+			ResourceQuota:   []corev1.ResourceQuotaSpec{},
+		},
+	}
+	pod := func(name, image string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  "container",
+						Image: image,
+					},
+				},
+			},
+		}
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should deny images from a non allowed registry", func() {
+		ns := NewNamespace("registry-denied")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		for _, image := range []string{"nginx", "docker.io/library/nginx:latest"} {
+			Eventually(func() (err error) {
+				_, err = cs.CoreV1().Pods(ns.GetName()).Create(context.TODO(), pod("denied", image), metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+		}
+		By("using a non allowed init container image", func() {
+			Eventually(func() (err error) {
+				p := pod("denied-init", "quay.io/clastix/capsule:latest")
+				p.Spec.InitContainers = []corev1.Container{{Name: "init", Image: "busybox"}}
+				_, err = cs.CoreV1().Pods(ns.GetName()).Create(context.TODO(), p, metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+		})
+	})
+	It("should allow images from the allowed registry", func() {
+		ns := NewNamespace("registry-allowed")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Pods(ns.GetName()).Create(context.TODO(), pod("allowed", "quay.io/clastix/capsule:latest"), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+	})
+})
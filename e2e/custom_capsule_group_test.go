@@ -54,7 +54,7 @@ var _ = Describe("creating a Namespace as Tenant owner with custom --capsule-gro
 		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
 	})
 	JustAfterEach(func() {
-		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
 	})
 	It("should fail", func() {
 		args := append(defaulManagerPodArgs, []string{"--capsule-user-group=test"}...)
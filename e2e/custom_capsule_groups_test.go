@@ -0,0 +1,128 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/controllers/rbac"
+)
+
+var _ = Describe("creating a Namespace as Tenant owner in one of the multiple --capsule-user-group", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenantmultiplegroups",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "wanda",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges: []corev1.LimitRangeSpec{
+				{
+					Limits: []corev1.LimitRangeItem{
+						{
+							Type: corev1.LimitTypeContainer,
+							Max: map[corev1.ResourceName]resource.Quantity{
+								corev1.ResourceCPU: resource.MustParse("1"),
+							},
+						},
+					},
+				},
+			},
+			NamespaceQuota: 1,
+			NodeSelector:   map[string]string{},
+			ResourceQuota:  []corev1.ResourceQuotaSpec{},
+		},
+	}
+	// the Tenant owner is not a member of the default capsule.clastix.io group
+	secondGroupClient := func() kubernetes.Interface {
+		c, err := config.GetConfig()
+		Expect(err).ToNot(HaveOccurred())
+		c.Impersonate.Groups = []string{"projectcapsule:users"}
+		c.Impersonate.UserName = tnt.Spec.Owner.Name
+		cs, err := kubernetes.NewForConfig(c)
+		Expect(err).ToNot(HaveOccurred())
+		return cs
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+		ModifyCapsuleManagerPodArgs(defaulManagerPodArgs)
+		CapsuleClusterGroupParamShouldBeUpdated("capsule.clastix.io", podRecreationTimeoutInterval)
+	})
+	It("should be handled as a Capsule user", func() {
+		args := append(defaulManagerPodArgs, []string{"--capsule-user-group=capsule.clastix.io,projectcapsule:users"}...)
+		ModifyCapsuleManagerPodArgs(args)
+
+		By("binding all the groups to the provisioner ClusterRole", func() {
+			Eventually(func() []string {
+				crb := &rbacv1.ClusterRoleBinding{}
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: rbac.ProvisionerRoleName}, crb)).Should(Succeed())
+				var groups []string
+				for _, s := range crb.Subjects {
+					groups = append(groups, s.Name)
+				}
+				return groups
+			}, podRecreationTimeoutInterval, defaultPollInterval).Should(ConsistOf("capsule.clastix.io", "projectcapsule:users"))
+		})
+
+		cs := secondGroupClient()
+		ns := NewNamespace("wanda-second-group")
+
+		By("creating the Namespace", func() {
+			Eventually(func() (err error) {
+				_, err = cs.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
+				return
+			}, podRecreationTimeoutInterval, defaultPollInterval).Should(Succeed())
+			NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+		})
+		By("exceeding the Namespace quota", func() {
+			_, err := cs.CoreV1().Namespaces().Create(context.TODO(), NewNamespace("wanda-second-group-fail"), metav1.CreateOptions{})
+			Expect(err).ShouldNot(Succeed())
+		})
+		By("deleting the managed Limit Range", func() {
+			n := fmt.Sprintf("capsule-%s-0", tnt.GetName())
+			Eventually(func() error {
+				_, err := cs.CoreV1().LimitRanges(ns.GetName()).Get(context.TODO(), n, metav1.GetOptions{})
+				return err
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Expect(cs.CoreV1().LimitRanges(ns.GetName()).Delete(context.TODO(), n, metav1.DeleteOptions{})).ShouldNot(Succeed())
+		})
+	})
+})
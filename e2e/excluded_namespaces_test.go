@@ -0,0 +1,87 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("excluding the system Namespaces from the webhooks", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenantexcludednamespaces",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "ines",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     10,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should label the excluded Namespaces", func() {
+		excluded := func(name string) func() string {
+			return func() string {
+				ns := &corev1.Namespace{}
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: name}, ns)).Should(Succeed())
+				return ns.GetLabels()[v1alpha1.ExcludedNamespaceLabel]
+			}
+		}
+
+		for _, name := range []string{"kube-system", "kube-public", capsuleNamespace} {
+			Eventually(excluded(name), defaultTimeoutInterval, defaultPollInterval).Should(Equal("true"))
+		}
+
+		By("restoring the removed label", func() {
+			ns := &corev1.Namespace{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "kube-public"}, ns)).Should(Succeed())
+			delete(ns.Labels, v1alpha1.ExcludedNamespaceLabel)
+			Expect(k8sClient.Update(context.TODO(), ns)).Should(Succeed())
+			Eventually(excluded("kube-public"), defaultTimeoutInterval, defaultPollInterval).Should(Equal("true"))
+		})
+	})
+	It("should be denied to the Tenant owners", func() {
+		ns := NewNamespace("ines-excluded")
+		ns.SetLabels(map[string]string{v1alpha1.ExcludedNamespaceLabel: "true"})
+		cs := ownerClient(tnt)
+		_, err := cs.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
+		Expect(err).ShouldNot(Succeed())
+	})
+})
@@ -75,7 +75,7 @@ var _ = Describe("creating a Namespace with --force-tenant-name flag", func() {
 		Expect(k8sClient.Create(context.TODO(), t2)).Should(Succeed())
 	})
 	JustAfterEach(func() {
-		Expect(k8sClient.Delete(context.TODO(), t1)).Should(Succeed())
+		TenantDeletionShouldSucceed(t1, defaultTimeoutInterval)
 		Expect(k8sClient.Delete(context.TODO(), t2)).Should(Succeed())
 	})
 	It("should fail", func() {
@@ -0,0 +1,119 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+var _ = Describe("enforcing the image pull policy", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "imagepullpolicy",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "quentin",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			ImagePullPolicies:  []v1alpha1.ImagePullPolicySpec{"Always"},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     3,
+			NodeSelector:       map[string]string{},
+			NetworkPolicies:    []networkingv1.NetworkPolicySpec{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	pod := func(name string, policy corev1.PullPolicy) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:            "container",
+						Image:           "quay.io/clastix/capsule:latest",
+						ImagePullPolicy: policy,
+					},
+				},
+			},
+		}
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should deny the non allowed image pull policy", func() {
+		ns := NewNamespace("image-pull-policy-denied")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Pods(ns.GetName()).Create(context.TODO(), pod("denied", corev1.PullIfNotPresent), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(HaveDenialReason(capsulewebhook.ReasonImagePullPolicyForbidden))
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Pods(ns.GetName()).Create(context.TODO(), pod("allowed", corev1.PullAlways), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+	})
+	It("should rewrite the non allowed image pull policy", func() {
+		ns := NewNamespace("image-pull-policy-rewritten")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		Eventually(func() error {
+			t := &v1alpha1.Tenant{}
+			if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t); err != nil {
+				return err
+			}
+			t.Spec.RewriteImagePullPolicy = true
+			return k8sClient.Update(context.TODO(), t)
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+
+		var p *corev1.Pod
+		Eventually(func() (err error) {
+			p, err = cs.CoreV1().Pods(ns.GetName()).Create(context.TODO(), pod("rewritten", corev1.PullIfNotPresent), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		Expect(p.Spec.Containers[0].ImagePullPolicy).Should(Equal(corev1.PullAlways))
+	})
+})
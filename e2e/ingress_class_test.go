@@ -57,6 +57,8 @@ var _ = Describe("when Tenant handles Ingress classes", func() {
 			NodeSelector:    map[string]string{},
 			NetworkPolicies: []networkingv1.NetworkPolicySpec{},
 			ResourceQuota:   []corev1.ResourceQuotaSpec{},
+			// the same Namespace is created by several specs
+			NamespaceDeletionPolicy: v1alpha1.NamespaceDeletionPolicyDelete,
 		},
 	}
 	JustBeforeEach(func() {
@@ -64,7 +66,7 @@ var _ = Describe("when Tenant handles Ingress classes", func() {
 		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
 	})
 	JustAfterEach(func() {
-		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+		TenantDeletionShouldSucceed(tnt, podRecreationTimeoutInterval)
 	})
 	It("should block non allowed Ingress class", func() {
 		ns := NewNamespace("ingress-class-disallowed")
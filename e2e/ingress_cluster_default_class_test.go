@@ -0,0 +1,119 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("when the cluster has a default Ingress class", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ingressclusterdefault",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "greta",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			IngressClasses: v1alpha1.IngressClassesSpec{
+				Allowed: []string{"greta-internal"},
+			},
+			LimitRanges:    []corev1.LimitRangeSpec{},
+			NamespaceQuota: 3,
+			NodeSelector:   map[string]string{},
+			ResourceQuota:  []corev1.ResourceQuotaSpec{},
+		},
+	}
+	ingressClass := func(name string) *networkingv1beta1.IngressClass {
+		return &networkingv1beta1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					"ingressclass.kubernetes.io/is-default-class": "true",
+				},
+			},
+			Spec: networkingv1beta1.IngressClassSpec{
+				Controller: "example.com/" + name,
+			},
+		}
+	}
+	newIngress := func(name string) *extensionsv1beta1.Ingress {
+		return &extensionsv1beta1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: extensionsv1beta1.IngressSpec{
+				Backend: &extensionsv1beta1.IngressBackend{
+					ServiceName: "foo",
+					ServicePort: intstr.FromInt(8080),
+				},
+			},
+		}
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		for _, name := range []string{"greta-external", "greta-internal"} {
+			if err := k8sClient.Delete(context.TODO(), ingressClass(name)); err != nil && !errors.IsNotFound(err) {
+				Expect(err).ToNot(HaveOccurred())
+			}
+		}
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should validate the resolved class of the Ingresses not specifying one", func() {
+		ns := NewNamespace("ingress-cluster-default-class")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		By("defaulting to a forbidden class", func() {
+			Expect(k8sClient.Create(context.TODO(), ingressClass("greta-external"))).Should(Succeed())
+			Eventually(func() (err error) {
+				_, err = cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Create(context.TODO(), newIngress("denied"), metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+		})
+		By("defaulting to an allowed class", func() {
+			Expect(k8sClient.Delete(context.TODO(), ingressClass("greta-external"))).Should(Succeed())
+			Expect(k8sClient.Create(context.TODO(), ingressClass("greta-internal"))).Should(Succeed())
+			Eventually(func() (err error) {
+				_, err = cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Create(context.TODO(), newIngress("allowed"), metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+	})
+})
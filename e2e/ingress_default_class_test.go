@@ -0,0 +1,112 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("when Tenant has a default Ingress class", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ingressdefaultclass",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "ingrid",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			IngressClasses: v1alpha1.IngressClassesSpec{
+				Allowed: []string{
+					"nginx",
+					"haproxy",
+				},
+				Default: "nginx",
+			},
+			LimitRanges:    []corev1.LimitRangeSpec{},
+			NamespaceQuota: 3,
+			NodeSelector:   map[string]string{},
+			ResourceQuota:  []corev1.ResourceQuotaSpec{},
+		},
+	}
+	newIngress := func(name string, class *string) *extensionsv1beta1.Ingress {
+		return &extensionsv1beta1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: extensionsv1beta1.IngressSpec{
+				IngressClassName: class,
+				Backend: &extensionsv1beta1.IngressBackend{
+					ServiceName: "foo",
+					ServicePort: intstr.FromInt(8080),
+				},
+			},
+		}
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should assign it only to the Ingresses not specifying one", func() {
+		ns := NewNamespace("ingress-default-class")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		By("non-specifying the class", func() {
+			var i *extensionsv1beta1.Ingress
+			Eventually(func() (err error) {
+				i, err = cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Create(context.TODO(), newIngress("defaulted", nil), metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Expect(i.Spec.IngressClassName).Should(Equal(pointer.StringPtr("nginx")))
+			Expect(i.GetAnnotations()).Should(HaveKeyWithValue("kubernetes.io/ingress.class", "nginx"))
+		})
+		By("specifying an allowed class", func() {
+			var i *extensionsv1beta1.Ingress
+			Eventually(func() (err error) {
+				i, err = cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Create(context.TODO(), newIngress("explicit", pointer.StringPtr("haproxy")), metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Expect(i.Spec.IngressClassName).Should(Equal(pointer.StringPtr("haproxy")))
+			Expect(i.GetAnnotations()).ShouldNot(HaveKey("kubernetes.io/ingress.class"))
+		})
+		By("specifying a forbidden class", func() {
+			_, err := cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Create(context.TODO(), newIngress("forbidden", pointer.StringPtr("the-worst-ingress-available")), metav1.CreateOptions{})
+			Expect(err).ShouldNot(Succeed())
+		})
+	})
+})
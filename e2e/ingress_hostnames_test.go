@@ -0,0 +1,135 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1beta12 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("when Tenant handles Ingress hostnames", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ingresshostnames",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "hostname",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			IngressClasses: v1alpha1.IngressClassesSpec{
+				Allowed: []string{"nginx"},
+			},
+			IngressHostnames: v1alpha1.IngressHostnamesSpec{
+				Allowed:      []string{"www.oil.acme.com", "*.gas.acme.com"},
+				AllowedRegex: "^.*\\.oil\\.acme\\.com$",
+			},
+			LimitRanges:     []corev1.LimitRangeSpec{},
+			NamespaceQuota:  3,
+			NodeSelector:    map[string]string{},
+			NetworkPolicies: []networkingv1.NetworkPolicySpec{},
+			ResourceQuota:   []corev1.ResourceQuotaSpec{},
+		},
+	}
+	ingress := func(name string, hostname string) *v1beta12.Ingress {
+		return &v1beta12.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: v1beta12.IngressSpec{
+				IngressClassName: pointer.StringPtr("nginx"),
+				Rules: []v1beta12.IngressRule{
+					{
+						Host: hostname,
+						IngressRuleValue: v1beta12.IngressRuleValue{
+							HTTP: &v1beta12.HTTPIngressRuleValue{
+								Paths: []v1beta12.HTTPIngressPath{
+									{
+										Path: "/",
+										Backend: v1beta12.IngressBackend{
+											ServiceName: "foo",
+											ServicePort: intstr.FromInt(8080),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should block non allowed hostnames", func() {
+		ns := NewNamespace("ingress-hostnames-disallowed")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		for i, h := range []string{"www.water.acme.com", "*.oil.acme.com"} {
+			Eventually(func() (err error) {
+				_, err = cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Create(context.TODO(), ingress("denied-"+strconv.Itoa(i), h), metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+		}
+		By("using a forbidden TLS hostname", func() {
+			Eventually(func() (err error) {
+				i := ingress("denied-tls", "www.oil.acme.com")
+				i.Spec.TLS = []v1beta12.IngressTLS{{Hosts: []string{"www.water.acme.com"}}}
+				_, err = cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Create(context.TODO(), i, metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+		})
+	})
+	It("should allow the Tenant hostnames", func() {
+		ns := NewNamespace("ingress-hostnames-allowed")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		for i, h := range []string{"www.oil.acme.com", "api.oil.acme.com", "*.gas.acme.com"} {
+			Eventually(func() (err error) {
+				_, err = cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Create(context.TODO(), ingress("allowed-"+strconv.Itoa(i), h), metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		}
+	})
+})
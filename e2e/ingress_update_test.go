@@ -0,0 +1,152 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1beta12 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/pointer"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("updating the Tenant Ingresses", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ingressupdate",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "piper",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			IngressClasses: v1alpha1.IngressClassesSpec{
+				Allowed: []string{"nginx"},
+			},
+			IngressHostnames: v1alpha1.IngressHostnamesSpec{
+				AllowedRegex: "^.*\\.oil\\.acme\\.com$",
+			},
+			LimitRanges:     []corev1.LimitRangeSpec{},
+			NamespaceQuota:  3,
+			NodeSelector:    map[string]string{},
+			NetworkPolicies: []networkingv1.NetworkPolicySpec{},
+			ResourceQuota:   []corev1.ResourceQuotaSpec{},
+		},
+	}
+	ingress := func(name string, hostname string) *v1beta12.Ingress {
+		return &v1beta12.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: v1beta12.IngressSpec{
+				IngressClassName: pointer.StringPtr("nginx"),
+				Rules: []v1beta12.IngressRule{
+					{
+						Host: hostname,
+						IngressRuleValue: v1beta12.IngressRuleValue{
+							HTTP: &v1beta12.HTTPIngressRuleValue{
+								Paths: []v1beta12.HTTPIngressPath{
+									{
+										Path: "/",
+										Backend: v1beta12.IngressBackend{
+											ServiceName: "foo",
+											ServicePort: intstr.FromInt(8080),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should deny the patches introducing a violation", func() {
+		ns := NewNamespace("ingress-update-denied")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		Eventually(func() (err error) {
+			_, err = cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Create(context.TODO(), ingress("patched", "www.oil.acme.com"), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		By("patching the Ingress Class", func() {
+			_, err := cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Patch(context.TODO(), "patched", types.MergePatchType, []byte(`{"spec":{"ingressClassName":"traefik"}}`), metav1.PatchOptions{})
+			Expect(err).ShouldNot(Succeed())
+		})
+		By("adding a forbidden TLS hostname", func() {
+			_, err := cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Patch(context.TODO(), "patched", types.MergePatchType, []byte(`{"spec":{"tls":[{"hosts":["www.water.acme.com"]}]}}`), metav1.PatchOptions{})
+			Expect(err).ShouldNot(Succeed())
+		})
+	})
+	It("should tolerate the existing violations", func() {
+		ns := NewNamespace("ingress-update-tolerated")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		Eventually(func() (err error) {
+			_, err = cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Create(context.TODO(), ingress("existing", "www.oil.acme.com"), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		By("tightening the Tenant hostnames", func() {
+			Expect(retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+				if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, tnt); err != nil {
+					return err
+				}
+				tnt.Spec.IngressHostnames.AllowedRegex = "^.*\\.gas\\.acme\\.com$"
+				return k8sClient.Update(context.TODO(), tnt)
+			})).Should(Succeed())
+			Eventually(func() (err error) {
+				_, err = cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Create(context.TODO(), ingress("probe", "api.oil.acme.com"), metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+		})
+		By("patching an unrelated field", func() {
+			_, err := cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Patch(context.TODO(), "existing", types.MergePatchType, []byte(`{"metadata":{"labels":{"app":"existing"}}}`), metav1.PatchOptions{})
+			Expect(err).Should(Succeed())
+		})
+		By("adding a TLS hostname not allowed anymore", func() {
+			_, err := cs.ExtensionsV1beta1().Ingresses(ns.GetName()).Patch(context.TODO(), "existing", types.MergePatchType, []byte(`{"spec":{"tls":[{"hosts":["api.oil.acme.com"]}]}}`), metav1.PatchOptions{})
+			Expect(err).ShouldNot(Succeed())
+		})
+	})
+})
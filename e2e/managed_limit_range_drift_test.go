@@ -0,0 +1,114 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("changing the Tenant LimitRange bypassing the webhook", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "limitrangedrift",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "paula",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges: []corev1.LimitRangeSpec{
+				{
+					Limits: []corev1.LimitRangeItem{
+						{
+							Type: corev1.LimitTypeContainer,
+							Max: map[corev1.ResourceName]resource.Quantity{
+								corev1.ResourceCPU: resource.MustParse("1"),
+							},
+						},
+					},
+				},
+			},
+			NamespaceQuota: 1,
+			NodeSelector:   map[string]string{},
+			ResourceQuota:  []corev1.ResourceQuotaSpec{},
+		},
+	}
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should restore the spec recording it on the Tenant", func() {
+		ns := NewNamespace("limit-range-drift")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		key := types.NamespacedName{Namespace: ns.GetName(), Name: fmt.Sprintf("capsule-%s-0", tnt.GetName())}
+		maxCPU := func() string {
+			lr := &corev1.LimitRange{}
+			if err := k8sClient.Get(context.TODO(), key, lr); err != nil || len(lr.Spec.Limits) == 0 {
+				return ""
+			}
+			q := lr.Spec.Limits[0].Max[corev1.ResourceCPU]
+			return q.String()
+		}
+		Eventually(maxCPU, defaultTimeoutInterval, defaultPollInterval).Should(Equal("1"))
+
+		By("raising the limit as cluster administrator", func() {
+			Eventually(func() error {
+				lr := &corev1.LimitRange{}
+				if err := k8sClient.Get(context.TODO(), key, lr); err != nil {
+					return err
+				}
+				lr.Spec.Limits[0].Max[corev1.ResourceCPU] = resource.MustParse("4")
+				return k8sClient.Update(context.TODO(), lr)
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+		By("restoring the Tenant limit", func() {
+			Eventually(maxCPU, defaultTimeoutInterval, defaultPollInterval).Should(Equal("1"))
+		})
+		By("recording the restore on the Tenant", func() {
+			Eventually(func() bool {
+				el := &corev1.EventList{}
+				Expect(k8sClient.List(context.TODO(), el)).Should(Succeed())
+				for _, e := range el.Items {
+					if e.Reason == "ManagedResourceRestored" && e.InvolvedObject.Name == tnt.GetName() {
+						return true
+					}
+				}
+				return false
+			}, defaultTimeoutInterval, defaultPollInterval).Should(BeTrue())
+		})
+	})
+})
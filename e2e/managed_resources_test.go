@@ -0,0 +1,117 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("changing the Tenant managed resources as Tenant owner", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenantmanagedresources",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "olga",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges: []corev1.LimitRangeSpec{
+				{
+					Limits: []corev1.LimitRangeItem{
+						{
+							Type: corev1.LimitTypeContainer,
+							Max: map[corev1.ResourceName]resource.Quantity{
+								corev1.ResourceCPU: resource.MustParse("1"),
+							},
+						},
+					},
+				},
+			},
+			NamespaceQuota: 1,
+			NodeSelector:   map[string]string{},
+			ResourceQuota: []corev1.ResourceQuotaSpec{
+				{
+					Hard: map[corev1.ResourceName]resource.Quantity{
+						corev1.ResourcePods: resource.MustParse("10"),
+					},
+				},
+			},
+		},
+	}
+	ns := NewNamespace("olga-managed")
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should be denied", func() {
+		cs := ownerClient(tnt)
+		n := fmt.Sprintf("capsule-%s-0", tnt.GetName())
+
+		By("updating the Resource Quota", func() {
+			var rq *corev1.ResourceQuota
+			Eventually(func() (err error) {
+				rq, err = cs.CoreV1().ResourceQuotas(ns.GetName()).Get(context.TODO(), n, metav1.GetOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			rq.Spec.Hard[corev1.ResourcePods] = resource.MustParse("100")
+			_, err := cs.CoreV1().ResourceQuotas(ns.GetName()).Update(context.TODO(), rq, metav1.UpdateOptions{})
+			Expect(err).ShouldNot(Succeed())
+		})
+		By("deleting the Resource Quota", func() {
+			Expect(cs.CoreV1().ResourceQuotas(ns.GetName()).Delete(context.TODO(), n, metav1.DeleteOptions{})).ShouldNot(Succeed())
+		})
+		By("deleting the Limit Range", func() {
+			Eventually(func() error {
+				_, err := cs.CoreV1().LimitRanges(ns.GetName()).Get(context.TODO(), n, metav1.GetOptions{})
+				return err
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Expect(cs.CoreV1().LimitRanges(ns.GetName()).Delete(context.TODO(), n, metav1.DeleteOptions{})).ShouldNot(Succeed())
+		})
+		By("recording the attempts on the Tenant", func() {
+			Eventually(func() bool {
+				el := &corev1.EventList{}
+				Expect(k8sClient.List(context.TODO(), el)).Should(Succeed())
+				for _, e := range el.Items {
+					if e.Reason == "ManagedResourceChangeDenied" && e.InvolvedObject.Name == tnt.GetName() {
+						return true
+					}
+				}
+				return false
+			}, defaultTimeoutInterval, defaultPollInterval).Should(BeTrue())
+		})
+	})
+})
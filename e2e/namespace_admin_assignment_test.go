@@ -0,0 +1,114 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("creating a Namespace with the Tenant assignment annotation", func() {
+	assigned := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "adminassigned",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "rachel",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     1,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	other := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "adminassignedother",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "simon",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     1,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	// the cluster administrators are the break-glass group assigning the Namespaces
+	cfg := &v1alpha1.CapsuleConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: v1alpha1.CapsuleConfigurationName,
+		},
+		Spec: v1alpha1.CapsuleConfigurationSpec{
+			BreakGlassGroups: []string{"system:masters"},
+		},
+	}
+	annotated := func(name, tenant string) *corev1.Namespace {
+		ns := NewNamespace(name)
+		ns.SetAnnotations(map[string]string{v1alpha1.TenantAssignmentAnnotation: tenant})
+		return ns
+	}
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), assigned)).Should(Succeed())
+		Expect(k8sClient.Create(context.TODO(), other)).Should(Succeed())
+		cfg.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), cfg)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), cfg)).Should(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), assigned)).Should(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), other)).Should(Succeed())
+	})
+	It("should assign it as if created by the Tenant owner", func() {
+		By("denying the annotation naming a non-owned Tenant to a Tenant owner", func() {
+			NamespaceCreationShouldNotSucceed(annotated("admin-assigned-moved", assigned.GetName()), other, defaultTimeoutInterval)
+		})
+		By("assigning the Namespace created by the cluster administrator", func() {
+			ns := annotated("admin-assigned", assigned.GetName())
+			Eventually(func() error {
+				return k8sClient.Create(context.TODO(), ns)
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			NamespaceShouldBeManagedByTenant(ns, assigned, defaultTimeoutInterval)
+			Expect(metav1.IsControlledBy(ns, assigned)).Should(BeTrue())
+		})
+		By("overriding the Tenant Namespace quota, as break-glass", func() {
+			ns := annotated("admin-assigned-exceeding", assigned.GetName())
+			Expect(k8sClient.Create(context.TODO(), ns)).Should(Succeed())
+			NamespaceShouldBeManagedByTenant(ns, assigned, defaultTimeoutInterval)
+		})
+	})
+})
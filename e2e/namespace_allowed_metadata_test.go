@@ -0,0 +1,129 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+var _ = Describe("updating the Namespace metadata allowed by the Tenant", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "allowedmetadata",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "amelia",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			NamespaceOptions: &v1alpha1.NamespaceOptions{
+				ForbiddenLabels: v1alpha1.ForbiddenListSpec{
+					Denied: []string{"cost-center"},
+				},
+				AllowedLabels: v1alpha1.AllowedListSpec{
+					Allowed: []string{"team", "cost-center"},
+				},
+				AllowedAnnotations: v1alpha1.AllowedListSpec{
+					AllowedRegex: `^monitoring\.capsule\.io/.*`,
+				},
+			},
+			ServicesMetadata: v1alpha1.AdditionalMetadata{},
+			IngressClasses:   v1alpha1.IngressClassesSpec{},
+			StorageClasses:   v1alpha1.StorageClassesSpec{},
+			LimitRanges:      []corev1.LimitRangeSpec{},
+			NamespaceQuota:   3,
+			NodeSelector:     map[string]string{},
+			ResourceQuota:    []corev1.ResourceQuotaSpec{},
+		},
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should allow adding, changing, and removing the allowed keys", func() {
+		ns := NewNamespace("allowed-metadata-update")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		cs := ownerClient(tnt)
+		for _, patch := range []string{
+			`{"metadata":{"labels":{"team":"backend"},"annotations":{"monitoring.capsule.io/scrape":"true"}}}`,
+			`{"metadata":{"labels":{"team":"frontend"}}}`,
+			`{"metadata":{"labels":{"team":null},"annotations":{"monitoring.capsule.io/scrape":null}}}`,
+		} {
+			Eventually(func() (err error) {
+				_, err = cs.CoreV1().Namespaces().Patch(context.TODO(), ns.GetName(), types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		}
+
+		Eventually(func() map[string]string {
+			got := &corev1.Namespace{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: ns.GetName()}, got)).Should(Succeed())
+			return got.GetLabels()
+		}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(HaveKey("team"))
+	})
+	It("should deny the updates touching both allowed and not allowed keys", func() {
+		ns := NewNamespace("allowed-metadata-mixed")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		cs := ownerClient(tnt)
+		patch := `{"metadata":{"labels":{"team":"backend","environment":"production"}}}`
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Namespaces().Patch(context.TODO(), ns.GetName(), types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(HaveDenialReason(capsulewebhook.ReasonNamespaceMetadataNotAllowed))
+
+		patch = `{"metadata":{"labels":{"team":"backend"},"annotations":{"cost-allocation/owner":"amelia"}}}`
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Namespaces().Patch(context.TODO(), ns.GetName(), types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(HaveDenialReason(capsulewebhook.ReasonNamespaceMetadataNotAllowed))
+
+		got := &corev1.Namespace{}
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: ns.GetName()}, got)).Should(Succeed())
+		Expect(got.GetLabels()).ShouldNot(HaveKey("team"))
+	})
+	It("should deny the allowed keys that are forbidden too", func() {
+		ns := NewNamespace("allowed-metadata-forbidden")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		cs := ownerClient(tnt)
+		patch := `{"metadata":{"labels":{"team":"backend","cost-center":"marketing"}}}`
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Namespaces().Patch(context.TODO(), ns.GetName(), types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(HaveDenialReason(capsulewebhook.ReasonNamespaceMetadataForbidden))
+	})
+})
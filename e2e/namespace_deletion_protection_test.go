@@ -0,0 +1,89 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+var _ = Describe("deleting a protected Tenant Namespace", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "deletionprotection",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "bernard",
+				Kind: "User",
+			},
+			NamespacesMetadata:          v1alpha1.AdditionalMetadata{},
+			NamespaceDeletionProtection: true,
+			ServicesMetadata:            v1alpha1.AdditionalMetadata{},
+			IngressClasses:              v1alpha1.IngressClassesSpec{},
+			StorageClasses:              v1alpha1.StorageClassesSpec{},
+			LimitRanges:                 []corev1.LimitRangeSpec{},
+			NamespaceQuota:              3,
+			NodeSelector:                map[string]string{},
+			ResourceQuota:               []corev1.ResourceQuotaSpec{},
+		},
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should deny the deletion until the annotation is removed", func() {
+		ns := NewNamespace("deletion-protection")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		By("checking the default annotation", func() {
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: ns.GetName()}, ns)).Should(Succeed())
+			Expect(ns.GetAnnotations()).Should(HaveKeyWithValue(v1alpha1.DeletionProtectionAnnotation, "true"))
+		})
+		By("denying the deletion to the Tenant owner", func() {
+			err := cs.CoreV1().Namespaces().Delete(context.TODO(), ns.GetName(), metav1.DeleteOptions{})
+			Expect(err).Should(HaveDenialReason(capsulewebhook.ReasonNamespaceDeletionProtected))
+		})
+		By("denying the deletion to the cluster administrator", func() {
+			Expect(k8sClient.Delete(context.TODO(), ns)).Should(HaveDenialReason(capsulewebhook.ReasonNamespaceDeletionProtected))
+		})
+		By("allowing the deletion once the annotation is removed", func() {
+			Eventually(func() error {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: ns.GetName()}, ns)).Should(Succeed())
+				delete(ns.Annotations, v1alpha1.DeletionProtectionAnnotation)
+				return k8sClient.Update(context.TODO(), ns)
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Expect(cs.CoreV1().Namespaces().Delete(context.TODO(), ns.GetName(), metav1.DeleteOptions{})).Should(Succeed())
+		})
+	})
+})
@@ -0,0 +1,89 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("creating a Namespace in dry-run", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dryruntenant",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "dave",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     1,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should not reserve the Namespace slot", func() {
+		cs := ownerClient(tnt)
+		dryRun := metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}
+
+		By("admitting the dry-run creation", func() {
+			_, err := cs.CoreV1().Namespaces().Create(context.TODO(), NewNamespace("dave-dry"), dryRun)
+			Expect(err).Should(Succeed())
+		})
+		By("leaving the Tenant size unchanged", func() {
+			t := &v1alpha1.Tenant{}
+			Consistently(func() bool {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+				return t.Status.Size == 0 && len(t.Status.Reservations) == 0
+			}, defaultTimeoutInterval, defaultPollInterval).Should(BeTrue())
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "dave-dry"}, &corev1.Namespace{})).ShouldNot(Succeed())
+		})
+		By("creating the Namespace filling the quota", func() {
+			ns := NewNamespace("dave-dev")
+			NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+			NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+		})
+		By("denying the dry-run creation in the full Tenant", func() {
+			_, err := cs.CoreV1().Namespaces().Create(context.TODO(), NewNamespace("dave-dry"), dryRun)
+			Expect(err).ShouldNot(Succeed())
+
+			t := &v1alpha1.Tenant{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+			Expect(t.Status.NamespaceQuotaExhausted).Should(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,95 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+var _ = Describe("creating a Namespace with the metadata forbidden by the Tenant", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "forbiddenmetadata",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "abigail",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			NamespaceOptions: &v1alpha1.NamespaceOptions{
+				ForbiddenLabels: v1alpha1.ForbiddenListSpec{
+					Denied: []string{"cost-center"},
+				},
+				ForbiddenAnnotations: v1alpha1.ForbiddenListSpec{
+					DeniedRegex: `^openshift\.io/.*`,
+				},
+			},
+			ServicesMetadata: v1alpha1.AdditionalMetadata{},
+			IngressClasses:   v1alpha1.IngressClassesSpec{},
+			StorageClasses:   v1alpha1.StorageClassesSpec{},
+			LimitRanges:      []corev1.LimitRangeSpec{},
+			NamespaceQuota:   3,
+			NodeSelector:     map[string]string{},
+			ResourceQuota:    []corev1.ResourceQuotaSpec{},
+		},
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should deny the label listed as denied", func() {
+		ns := NewNamespace("forbidden-label")
+		ns.SetLabels(map[string]string{"cost-center": "marketing"})
+		cs := ownerClient(tnt)
+
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(HaveDenialReason(capsulewebhook.ReasonNamespaceMetadataForbidden))
+	})
+	It("should deny the annotation matching the denied regex", func() {
+		ns := NewNamespace("forbidden-annotation")
+		ns.SetAnnotations(map[string]string{"openshift.io/node-selector": "region=east"})
+		cs := ownerClient(tnt)
+
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(HaveDenialReason(capsulewebhook.ReasonNamespaceMetadataForbidden))
+	})
+	It("should allow the metadata not forbidden", func() {
+		ns := NewNamespace("forbidden-none")
+		ns.SetLabels(map[string]string{"team": "marketing"})
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+	})
+})
@@ -0,0 +1,99 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+var _ = Describe("creating Namespaces with a generated name", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "generatedname",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "nadia",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     2,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should be labelled, collected and counted against the quota", func() {
+		tl, err := v1alpha1.GetTypeLabel(&v1alpha1.Tenant{})
+		Expect(err).ToNot(HaveOccurred())
+
+		cs := ownerClient(tnt)
+		generated := func() *corev1.Namespace {
+			return &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "nadia-",
+				},
+			}
+		}
+
+		var names []string
+		By("creating the Namespaces up to the quota", func() {
+			for i := 0; i < 2; i++ {
+				var ns *corev1.Namespace
+				Eventually(func() (err error) {
+					ns, err = cs.CoreV1().Namespaces().Create(context.TODO(), generated(), metav1.CreateOptions{})
+					return
+				}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+				Expect(ns.GetName()).Should(HavePrefix("nadia-"))
+				Expect(ns.GetLabels()).Should(HaveKeyWithValue(tl, tnt.GetName()))
+				names = append(names, ns.GetName())
+			}
+		})
+		By("collecting the generated names in the Tenant status", func() {
+			t := &v1alpha1.Tenant{}
+			Eventually(func() []string {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+				return t.Status.Namespaces
+			}, defaultTimeoutInterval, defaultPollInterval).Should(ConsistOf(names))
+		})
+		By("denying the creation over the quota", func() {
+			_, err := cs.CoreV1().Namespaces().Create(context.TODO(), generated(), metav1.CreateOptions{})
+			Expect(err).Should(HaveDenialReason(capsulewebhook.ReasonNamespaceQuotaExceeded))
+		})
+	})
+})
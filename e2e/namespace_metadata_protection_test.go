@@ -0,0 +1,133 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("updating the metadata of a Tenant Namespace", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "nsmetadata",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "paula",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     3,
+			NodeSelector: map[string]string{
+				"kubernetes.io/os": "linux",
+			},
+			ResourceQuota: []corev1.ResourceQuotaSpec{},
+		},
+	}
+	// granting the owner the Namespace update, otherwise denied by RBAC before reaching the webhook
+	cr := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "nsmetadata-namespace-editor",
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"namespaces"},
+				Verbs:     []string{"get", "update"},
+			},
+		},
+	}
+	crb := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "nsmetadata-namespace-editor",
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     cr.GetName(),
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.UserKind,
+				Name:     "paula",
+			},
+		},
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		cr.ResourceVersion = ""
+		crb.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		Expect(k8sClient.Create(context.TODO(), cr)).Should(Succeed())
+		Expect(k8sClient.Create(context.TODO(), crb)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), crb)).Should(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), cr)).Should(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should protect the node selector annotation and the Capsule labels", func() {
+		ns := NewNamespace("nsmetadata-protected")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		for _, mutate := range []func(ns *corev1.Namespace){
+			func(ns *corev1.Namespace) {
+				ns.Annotations["scheduler.alpha.kubernetes.io/node-selector"] = "kubernetes.io/os=windows"
+			},
+			func(ns *corev1.Namespace) {
+				ns.Labels["capsule.clastix.io/tenant"] = "other"
+			},
+		} {
+			Eventually(func() (err error) {
+				if ns, err = cs.CoreV1().Namespaces().Get(context.TODO(), ns.GetName(), metav1.GetOptions{}); err != nil {
+					return
+				}
+				mutate(ns)
+				_, err = cs.CoreV1().Namespaces().Update(context.TODO(), ns, metav1.UpdateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+		}
+		By("changing a non protected label", func() {
+			Eventually(func() (err error) {
+				if ns, err = cs.CoreV1().Namespaces().Get(context.TODO(), ns.GetName(), metav1.GetOptions{}); err != nil {
+					return
+				}
+				ns.Labels["team"] = "platform"
+				_, err = cs.CoreV1().Namespaces().Update(context.TODO(), ns, metav1.UpdateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+	})
+})
@@ -0,0 +1,125 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("removing the additional metadata from a Tenant", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "metadataremoval",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "walter",
+				Kind: "User",
+			},
+			IngressClasses: v1alpha1.IngressClassesSpec{},
+			StorageClasses: v1alpha1.StorageClassesSpec{},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{
+				AdditionalLabels: map[string]string{
+					"clastix.io/old-label": "foo",
+				},
+				AdditionalAnnotations: map[string]string{
+					"clastix.io/old-annotation": "bar",
+				},
+			},
+			ServicesMetadata: v1alpha1.AdditionalMetadata{},
+			LimitRanges:      []corev1.LimitRangeSpec{},
+			NamespaceQuota:   3,
+			NodeSelector:     map[string]string{},
+			ResourceQuota:    []corev1.ResourceQuotaSpec{},
+		},
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should replace the renamed metadata retaining the user defined one", func() {
+		ns := NewNamespace("metadata-removal")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		By("waiting for the additional metadata", func() {
+			Eventually(func() map[string]string {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: ns.GetName()}, ns)).Should(Succeed())
+				return ns.GetLabels()
+			}, defaultTimeoutInterval, defaultPollInterval).Should(HaveKeyWithValue("clastix.io/old-label", "foo"))
+		})
+		By("adding user defined metadata", func() {
+			Eventually(func() error {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: ns.GetName()}, ns)).Should(Succeed())
+				ns.Labels["clastix.io/user-label"] = "user"
+				if ns.Annotations == nil {
+					ns.Annotations = map[string]string{}
+				}
+				ns.Annotations["clastix.io/user-annotation"] = "user"
+				return k8sClient.Update(context.TODO(), ns)
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+		By("renaming the Tenant additional metadata", func() {
+			Eventually(func() error {
+				t := &v1alpha1.Tenant{}
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+				t.Spec.NamespacesMetadata = v1alpha1.AdditionalMetadata{
+					AdditionalLabels: map[string]string{
+						"clastix.io/new-label": "foo",
+					},
+					AdditionalAnnotations: map[string]string{
+						"clastix.io/new-annotation": "bar",
+					},
+				}
+				return k8sClient.Update(context.TODO(), t)
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+		By("checking the labels", func() {
+			Eventually(func() map[string]string {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: ns.GetName()}, ns)).Should(Succeed())
+				return ns.GetLabels()
+			}, defaultTimeoutInterval, defaultPollInterval).Should(And(
+				HaveKeyWithValue("clastix.io/new-label", "foo"),
+				HaveKeyWithValue("clastix.io/user-label", "user"),
+				Not(HaveKey("clastix.io/old-label")),
+			))
+		})
+		By("checking the annotations", func() {
+			Eventually(func() map[string]string {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: ns.GetName()}, ns)).Should(Succeed())
+				return ns.GetAnnotations()
+			}, defaultTimeoutInterval, defaultPollInterval).Should(And(
+				HaveKeyWithValue("clastix.io/new-annotation", "bar"),
+				HaveKeyWithValue("clastix.io/user-annotation", "user"),
+				Not(HaveKey("clastix.io/old-annotation")),
+			))
+		})
+	})
+})
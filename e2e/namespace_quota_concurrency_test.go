@@ -0,0 +1,99 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("creating Namespaces concurrently over-quota", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "concurrentquotatenant",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "rosa",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     3,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	var names []string
+	for i := 0; i < int(tnt.Spec.NamespaceQuota)+5; i++ {
+		names = append(names, fmt.Sprintf("rosa-%d", i))
+	}
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		for _, name := range names {
+			_ = k8sClient.Delete(context.TODO(), NewNamespace(name))
+		}
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should admit exactly the Namespace quota", func() {
+		cs := ownerClient(tnt)
+
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		var created int
+		for _, name := range names {
+			wg.Add(1)
+			go func(ns *corev1.Namespace) {
+				defer wg.Done()
+				defer GinkgoRecover()
+				if _, err := cs.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{}); err == nil {
+					mutex.Lock()
+					created++
+					mutex.Unlock()
+				}
+			}(NewNamespace(name))
+		}
+		wg.Wait()
+		Expect(created).Should(Equal(int(tnt.Spec.NamespaceQuota)))
+
+		t := &v1alpha1.Tenant{}
+		Eventually(func() v1alpha1.NamespaceList {
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+			return t.Status.Namespaces
+		}, defaultTimeoutInterval, defaultPollInterval).Should(HaveLen(int(tnt.Spec.NamespaceQuota)))
+		Eventually(func() []v1alpha1.NamespaceReservation {
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+			return t.Status.Reservations
+		}, defaultTimeoutInterval, defaultPollInterval).Should(BeEmpty())
+	})
+})
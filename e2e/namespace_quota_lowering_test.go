@@ -0,0 +1,107 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("lowering the Namespace quota below the usage", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "loweredquotatenant",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "lena",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     3,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	names := []string{"lena-dev", "lena-staging", "lena-production"}
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		for _, name := range append(names, "lena-new") {
+			_ = k8sClient.Delete(context.TODO(), NewNamespace(name))
+		}
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	violated := func() corev1.ConditionStatus {
+		t := &v1alpha1.Tenant{}
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+		for _, c := range t.Status.Conditions {
+			if c.Type == v1alpha1.TenantConditionNamespaceQuotaViolated {
+				return c.Status
+			}
+		}
+		return corev1.ConditionUnknown
+	}
+	It("should retain the Namespaces and deny the new ones until below the quota", func() {
+		for _, name := range names {
+			ns := NewNamespace(name)
+			NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+			NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+		}
+
+		By("lowering the quota", func() {
+			Eventually(func() error {
+				t := &v1alpha1.Tenant{}
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+				t.Spec.NamespaceQuota = 2
+				return k8sClient.Update(context.TODO(), t)
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Eventually(violated, defaultTimeoutInterval, defaultPollInterval).Should(Equal(corev1.ConditionTrue))
+			for _, name := range names {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: name}, &corev1.Namespace{})).Should(Succeed())
+			}
+			NamespaceCreationShouldNotSucceed(NewNamespace("lena-new"), tnt, defaultTimeoutInterval)
+		})
+
+		By("deleting the Namespaces exceeding the quota", func() {
+			for _, name := range names[1:] {
+				Expect(k8sClient.Delete(context.TODO(), NewNamespace(name))).Should(Succeed())
+			}
+			Eventually(violated, defaultTimeoutInterval, defaultPollInterval).Should(Equal(corev1.ConditionFalse))
+		})
+
+		By("creating a Namespace below the quota", func() {
+			ns := NewNamespace("lena-new")
+			NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+			NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+		})
+	})
+})
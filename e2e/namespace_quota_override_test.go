@@ -0,0 +1,123 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("overriding the Namespace quota", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "quotaoverridetenant",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "otto",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     2,
+			NodeSelector:       map[string]string{},
+			ResourceQuota: []corev1.ResourceQuotaSpec{
+				{
+					Hard: map[corev1.ResourceName]resource.Quantity{
+						corev1.ResourcePods: resource.MustParse("10"),
+					},
+				},
+			},
+		},
+	}
+	ns := NewNamespace("otto-override")
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should be honored within the Tenant quota", func() {
+		key := v1alpha1.QuotaOverrideFor(corev1.ResourcePods)
+		setOverride := func(user string, value string) error {
+			cs := ownerClient(tnt)
+			if user != tnt.Spec.Owner.Name {
+				cs = groupsClient(user)
+			}
+			n, err := cs.CoreV1().Namespaces().Get(context.TODO(), ns.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if n.Annotations == nil {
+				n.Annotations = map[string]string{}
+			}
+			n.Annotations[key] = value
+			_, err = cs.CoreV1().Namespaces().Update(context.TODO(), n, metav1.UpdateOptions{})
+			return err
+		}
+		podsHard := func() string {
+			rq := &corev1.ResourceQuota{}
+			if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("capsule-%s-0", tnt.GetName()), Namespace: ns.GetName()}, rq); err != nil {
+				return ""
+			}
+			q := rq.Spec.Hard[corev1.ResourcePods]
+			return q.String()
+		}
+
+		By("setting the override as a non owner", func() {
+			Expect(setOverride("mallory", "3")).ShouldNot(Succeed())
+		})
+		By("shrinking the Namespace quota", func() {
+			Eventually(func() error {
+				return setOverride(tnt.Spec.Owner.Name, "3")
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Eventually(podsHard, defaultTimeoutInterval, defaultPollInterval).Should(Equal("3"))
+		})
+		By("exceeding the Tenant quota", func() {
+			Eventually(func() error {
+				return setOverride(tnt.Spec.Owner.Name, "20")
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Eventually(podsHard, defaultTimeoutInterval, defaultPollInterval).Should(Equal("10"))
+			Eventually(func() bool {
+				el := &corev1.EventList{}
+				Expect(k8sClient.List(context.TODO(), el)).Should(Succeed())
+				for _, e := range el.Items {
+					if e.Reason == "QuotaOverrideClamped" && e.InvolvedObject.Name == tnt.GetName() {
+						return true
+					}
+				}
+				return false
+			}, defaultTimeoutInterval, defaultPollInterval).Should(BeTrue())
+		})
+	})
+})
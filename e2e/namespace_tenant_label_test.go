@@ -0,0 +1,135 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("changing the Tenant label of a Namespace", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenantlabelforgery",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "zeno",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     10,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	// the cluster administrators are the only break-glass group
+	cfg := &v1alpha1.CapsuleConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: v1alpha1.CapsuleConfigurationName,
+		},
+		Spec: v1alpha1.CapsuleConfigurationSpec{
+			BreakGlassGroups: []string{"system:masters"},
+		},
+	}
+	// a cluster-wide administrator, although not a member of the break-glass groups
+	crb := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenant-label-forgery",
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     "cluster-admin",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "User",
+				Name:     "trent",
+			},
+		},
+	}
+	ns := NewNamespace("zeno-labels")
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		cfg.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), cfg)).Should(Succeed())
+		crb.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), crb)).Should(Succeed())
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), crb)).Should(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), cfg)).Should(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should be restricted to the break-glass groups", func() {
+		tl, err := v1alpha1.GetTypeLabel(&v1alpha1.Tenant{})
+		Expect(err).ToNot(HaveOccurred())
+
+		c, err := config.GetConfig()
+		Expect(err).ToNot(HaveOccurred())
+		c.Impersonate.UserName = "trent"
+		cs, err := kubernetes.NewForConfig(c)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("denying the forgery of the label", func() {
+			got, err := cs.CoreV1().Namespaces().Get(context.TODO(), ns.GetName(), metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			got.Labels[tl] = "another"
+			_, err = cs.CoreV1().Namespaces().Update(context.TODO(), got, metav1.UpdateOptions{})
+			Expect(err).ShouldNot(Succeed())
+		})
+		By("denying the removal of the label", func() {
+			got, err := cs.CoreV1().Namespaces().Get(context.TODO(), ns.GetName(), metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			delete(got.Labels, tl)
+			_, err = cs.CoreV1().Namespaces().Update(context.TODO(), got, metav1.UpdateOptions{})
+			Expect(err).ShouldNot(Succeed())
+		})
+		By("restoring the label removed by the break-glass group", func() {
+			got := &corev1.Namespace{}
+			Eventually(func() error {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: ns.GetName()}, got)).Should(Succeed())
+				delete(got.Labels, tl)
+				return k8sClient.Update(context.TODO(), got)
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Eventually(func() string {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: ns.GetName()}, got)).Should(Succeed())
+				return got.GetLabels()[tl]
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Equal(tnt.GetName()))
+		})
+	})
+})
@@ -0,0 +1,110 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("enforcing the Tenant node selector", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "nodeselector",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "oscar",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NetworkPolicies:    []networkingv1.NetworkPolicySpec{},
+			NamespaceQuota:     3,
+			NodeSelector: map[string]string{
+				"kubernetes.io/os": "linux",
+			},
+			ResourceQuota: []corev1.ResourceQuotaSpec{},
+		},
+	}
+	pod := func(name string, selector map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: corev1.PodSpec{
+				NodeSelector: selector,
+				Containers: []corev1.Container{
+					{
+						Name:  "pause",
+						Image: "gcr.io/google_containers/pause-amd64:3.0",
+					},
+				},
+			},
+		}
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should merge the Tenant node selector", func() {
+		ns := NewNamespace("nodeselector-merged")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		for name, selector := range map[string]map[string]string{
+			"none":  nil,
+			"other": {"disktype": "ssd"},
+		} {
+			p := pod(name, selector)
+			Eventually(func() (err error) {
+				p, err = cs.CoreV1().Pods(ns.GetName()).Create(context.TODO(), p, metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Expect(p.Spec.NodeSelector).Should(HaveKeyWithValue("kubernetes.io/os", "linux"))
+		}
+	})
+	It("should deny a conflicting node selector", func() {
+		ns := NewNamespace("nodeselector-conflicting")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Pods(ns.GetName()).Create(context.TODO(), pod("conflicting", map[string]string{"kubernetes.io/os": "windows"}), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+	})
+})
@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/clastix/capsule/api/v1alpha1"
 )
@@ -68,5 +69,32 @@ var _ = Describe("creating a Namespace over-quota", func() {
 		cs := ownerClient(tnt)
 		_, err := cs.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
 		Expect(err).ShouldNot(Succeed())
+
+		By("reporting the exhausted quota", func() {
+			t := &v1alpha1.Tenant{}
+			Eventually(func() bool {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+				return t.Status.NamespaceQuotaExhausted
+			}, defaultTimeoutInterval, defaultPollInterval).Should(BeTrue())
+			Eventually(func() bool {
+				el := &corev1.EventList{}
+				Expect(k8sClient.List(context.TODO(), el)).Should(Succeed())
+				for _, e := range el.Items {
+					if e.Reason == "NamespaceQuotaExceeded" && e.InvolvedObject.Name == tnt.GetName() {
+						return true
+					}
+				}
+				return false
+			}, defaultTimeoutInterval, defaultPollInterval).Should(BeTrue())
+		})
+
+		By("clearing the exhausted quota upon a Namespace deletion", func() {
+			Expect(k8sClient.Delete(context.TODO(), NewNamespace("bob-dev"))).Should(Succeed())
+			t := &v1alpha1.Tenant{}
+			Eventually(func() bool {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+				return t.Status.NamespaceQuotaExhausted
+			}, defaultTimeoutInterval, defaultPollInterval).Should(BeFalse())
+		})
 	})
 })
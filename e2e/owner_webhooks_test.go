@@ -31,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
 )
 
 var _ = Describe("when Tenant owner interacts with the webhooks", func() {
@@ -105,7 +106,7 @@ var _ = Describe("when Tenant owner interacts with the webhooks", func() {
 		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
 	})
 	JustAfterEach(func() {
-		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
 	})
 	It("should disallow deletions", func() {
 		By("blocking Capsule Limit ranges", func() {
@@ -134,7 +135,10 @@ var _ = Describe("when Tenant owner interacts with the webhooks", func() {
 			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
 
 			cs := ownerClient(tnt)
-			Expect(cs.NetworkingV1().NetworkPolicies(ns.GetName()).Delete(context.TODO(), np.Name, metav1.DeleteOptions{})).ShouldNot(Succeed())
+			Expect(cs.NetworkingV1().NetworkPolicies(ns.GetName()).Delete(context.TODO(), np.Name, metav1.DeleteOptions{})).Should(HaveDenialReason(capsulewebhook.ReasonNetworkPolicyProtected))
+			np.Spec.Egress = []networkingv1.NetworkPolicyEgressRule{}
+			_, err := cs.NetworkingV1().NetworkPolicies(ns.GetName()).Update(context.TODO(), np, metav1.UpdateOptions{})
+			Expect(err).ShouldNot(Succeed())
 		})
 		By("blocking blocking Capsule Resource Quota", func() {
 			ns := NewNamespace("resource-quota-disallow")
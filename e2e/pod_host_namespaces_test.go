@@ -0,0 +1,123 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("enforcing the Tenant host namespaces and ports", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "hostnamespaces",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "rachel",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NetworkPolicies:    []networkingv1.NetworkPolicySpec{},
+			NamespaceQuota:     3,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+			PodSecurity: v1alpha1.PodSecuritySpec{
+				AllowHostPorts: true,
+				HostPortRange: &v1alpha1.HostPortRange{
+					Min: 30000,
+					Max: 30100,
+				},
+			},
+		},
+	}
+	pod := func(name string, hostPort int32) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  "pause",
+						Image: "gcr.io/google_containers/pause-amd64:3.0",
+					},
+				},
+			},
+		}
+		if hostPort > 0 {
+			p.Spec.Containers[0].Ports = []corev1.ContainerPort{{ContainerPort: 8080, HostPort: hostPort}}
+		}
+		return p
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should deny the host namespaces", func() {
+		ns := NewNamespace("host-namespaces-denied")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		for name, mutate := range map[string]func(p *corev1.Pod){
+			"network": func(p *corev1.Pod) { p.Spec.HostNetwork = true },
+			"pid":     func(p *corev1.Pod) { p.Spec.HostPID = true },
+			"ipc":     func(p *corev1.Pod) { p.Spec.HostIPC = true },
+		} {
+			p := pod(name, 0)
+			mutate(p)
+			Eventually(func() (err error) {
+				_, err = cs.CoreV1().Pods(ns.GetName()).Create(context.TODO(), p, metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+		}
+	})
+	It("should allow only the host ports in range", func() {
+		ns := NewNamespace("host-ports")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Pods(ns.GetName()).Create(context.TODO(), pod("denied", 8080), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Pods(ns.GetName()).Create(context.TODO(), pod("allowed", 30080), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+	})
+})
@@ -0,0 +1,118 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("enforcing a Priority Class", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "priority-class",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "george",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			PriorityClasses: v1alpha1.PriorityClassesSpec{
+				Allowed: []string{"gold"},
+			},
+			LimitRanges:     []corev1.LimitRangeSpec{},
+			NamespaceQuota:  3,
+			NodeSelector:    map[string]string{},
+			NetworkPolicies: []networkingv1.NetworkPolicySpec{},
+			ResourceQuota:   []corev1.ResourceQuotaSpec{},
+		},
+	}
+	pcs := []*schedulingv1.PriorityClass{
+		{ObjectMeta: metav1.ObjectMeta{Name: "gold"}, Value: 1000},
+		{ObjectMeta: metav1.ObjectMeta{Name: "platinum"}, Value: 10000},
+	}
+	pod := func(name, priorityClassName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: corev1.PodSpec{
+				PriorityClassName: priorityClassName,
+				Containers: []corev1.Container{
+					{
+						Name:  "container",
+						Image: "quay.io/clastix/capsule:latest",
+					},
+				},
+			},
+		}
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		for _, pc := range pcs {
+			pc.ResourceVersion = ""
+			Expect(k8sClient.Create(context.TODO(), pc)).Should(Succeed())
+		}
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+		for _, pc := range pcs {
+			Expect(k8sClient.Delete(context.TODO(), pc)).Should(Succeed())
+		}
+	})
+	It("should deny a non allowed Priority Class", func() {
+		ns := NewNamespace("priority-class-denied")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Pods(ns.GetName()).Create(context.TODO(), pod("denied", "platinum"), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+	})
+	It("should allow the Tenant Priority Classes or none", func() {
+		ns := NewNamespace("priority-class-allowed")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		for name, pc := range map[string]string{"allowed": "gold", "none": ""} {
+			Eventually(func() (err error) {
+				_, err = cs.CoreV1().Pods(ns.GetName()).Create(context.TODO(), pod(name, pc), metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		}
+	})
+})
@@ -54,7 +54,7 @@ var _ = Describe("creating a Namespace with --protected-namespace-regex enabled"
 		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
 	})
 	JustAfterEach(func() {
-		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
 	})
 	It("should succeed and be available in Tenant namespaces list", func() {
 		args := append(defaulManagerPodArgs, []string{"--protected-namespace-regex=^.*[-.]system$"}...)
@@ -0,0 +1,96 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+var _ = Describe("creating a Namespace with a reserved name", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenantreservednames",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "kevin",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     10,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	cfg := &v1alpha1.CapsuleConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: v1alpha1.CapsuleConfigurationName,
+		},
+		Spec: v1alpha1.CapsuleConfigurationSpec{
+			ProtectedNamespaceRegexpString: "^kube-",
+		},
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		cfg.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), cfg)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), cfg)).Should(Succeed())
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+		if err := k8sClient.Delete(context.TODO(), NewNamespace("kube-foo")); err != nil && !errors.IsNotFound(err) {
+			Expect(err).ToNot(HaveOccurred())
+		}
+	})
+	It("should be denied to the Tenant owner only", func() {
+		cs := ownerClient(tnt)
+
+		By("denying the name matching the regex", func() {
+			// dry-running the creation until the CapsuleConfiguration is applied
+			Eventually(func() (err error) {
+				_, err = cs.CoreV1().Namespaces().Create(context.TODO(), NewNamespace("kube-foo"), metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(HaveDenialReason(capsulewebhook.ReasonNamespaceNameProtected))
+			_, err := cs.CoreV1().Namespaces().Create(context.TODO(), NewNamespace("kube-foo"), metav1.CreateOptions{})
+			Expect(err).ShouldNot(Succeed())
+		})
+		By("allowing the name not anchored at the start", func() {
+			ns := NewNamespace("foo-kube")
+			NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+			NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+		})
+		By("allowing the cluster administrators", func() {
+			Expect(k8sClient.Create(context.TODO(), NewNamespace("kube-foo"))).Should(Succeed())
+		})
+	})
+})
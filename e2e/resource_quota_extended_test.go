@@ -0,0 +1,138 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("limiting an extended resource with the Tenant resource quota", func() {
+	const dongle corev1.ResourceName = "example.com/dongle"
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "extendedquota",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "gemma",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     2,
+			NodeSelector:       map[string]string{},
+			ResourceQuota: []corev1.ResourceQuotaSpec{
+				{
+					Hard: map[corev1.ResourceName]resource.Quantity{
+						"requests." + dongle: resource.MustParse("2"),
+					},
+				},
+			},
+		},
+	}
+	nsl := []string{"extended-first", "extended-second"}
+	quota := func(namespace string) *corev1.ResourceQuota {
+		rq := &corev1.ResourceQuota{}
+		if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("capsule-%s-0", tnt.GetName()), Namespace: namespace}, rq); err != nil {
+			return nil
+		}
+		return rq
+	}
+	hardDongles := func(namespace string) func() string {
+		return func() string {
+			rq := quota(namespace)
+			if rq == nil {
+				return ""
+			}
+			q := rq.Spec.Hard["requests."+dongle]
+			return q.String()
+		}
+	}
+	pod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  "pause",
+						Image: "gcr.io/google_containers/pause-amd64:3.0",
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								dongle: resource.MustParse("1"),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		for _, i := range nsl {
+			ns := NewNamespace(i)
+			NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+			NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+		}
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should aggregate the extended resource requests across the Namespaces", func() {
+		cs := ownerClient(tnt)
+
+		By("requesting the extended resource in both Namespaces", func() {
+			for _, ns := range nsl {
+				Eventually(func() (err error) {
+					_, err = cs.CoreV1().Pods(ns).Create(context.TODO(), pod("dongle"), metav1.CreateOptions{})
+					return
+				}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			}
+		})
+		By("tracking the Tenant usage", func() {
+			Eventually(func() string {
+				rq := quota(nsl[0])
+				if rq == nil {
+					return ""
+				}
+				return rq.GetAnnotations()["quota.capsule.clastix.io/used-requests.example.com_dongle"]
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Equal("2"))
+		})
+		By("denying further requests once the Tenant quota is exhausted", func() {
+			Eventually(hardDongles(nsl[0]), defaultTimeoutInterval, defaultPollInterval).Should(Equal("1"))
+			_, err := cs.CoreV1().Pods(nsl[0]).Create(context.TODO(), pod("dongle-exceeding"), metav1.CreateOptions{})
+			Expect(err).Should(MatchError(ContainSubstring("exceeded quota")))
+		})
+	})
+})
@@ -0,0 +1,121 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("creating a Tenant with scoped resource quotas", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "scopedquota",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "oscar",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NetworkPolicies:    []networkingv1.NetworkPolicySpec{},
+			NamespaceQuota:     2,
+			NodeSelector:       map[string]string{},
+			ResourceQuota: []corev1.ResourceQuotaSpec{
+				{
+					Hard: map[corev1.ResourceName]resource.Quantity{
+						corev1.ResourcePods: resource.MustParse("10"),
+					},
+				},
+				{
+					Hard: map[corev1.ResourceName]resource.Quantity{
+						corev1.ResourcePods: resource.MustParse("2"),
+					},
+					Scopes: []corev1.ResourceQuotaScope{
+						corev1.ResourceQuotaScopeBestEffort,
+					},
+				},
+			},
+		},
+	}
+	scopes := func(namespace string, index int) func() []corev1.ResourceQuotaScope {
+		return func() []corev1.ResourceQuotaScope {
+			rq := &corev1.ResourceQuota{}
+			if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("capsule-%s-%d", tnt.GetName(), index), Namespace: namespace}, rq); err != nil {
+				return nil
+			}
+			return rq.Spec.Scopes
+		}
+	}
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should create a ResourceQuota per scope, recreating them upon the reordering", func() {
+		ns := NewNamespace("scoped-quota")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		By("creating the general and the best-effort scoped ResourceQuotas", func() {
+			Eventually(func() error {
+				return k8sClient.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("capsule-%s-0", tnt.GetName()), Namespace: ns.GetName()}, &corev1.ResourceQuota{})
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Expect(scopes(ns.GetName(), 0)()).Should(BeEmpty())
+			Eventually(scopes(ns.GetName(), 1), defaultTimeoutInterval, defaultPollInterval).Should(Equal([]corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}))
+		})
+		By("swapping the Tenant resource quotas", func() {
+			Expect(retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+				if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, tnt); err != nil {
+					return err
+				}
+				rq := tnt.Spec.ResourceQuota
+				tnt.Spec.ResourceQuota = []corev1.ResourceQuotaSpec{rq[1], rq[0]}
+				return k8sClient.Update(context.TODO(), tnt)
+			})).Should(Succeed())
+			Eventually(scopes(ns.GetName(), 0), defaultTimeoutInterval, defaultPollInterval).Should(Equal([]corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}))
+			Eventually(func() error {
+				rq := &corev1.ResourceQuota{}
+				if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("capsule-%s-1", tnt.GetName()), Namespace: ns.GetName()}, rq); err != nil {
+					return err
+				}
+				if len(rq.Spec.Scopes) > 0 {
+					return fmt.Errorf("the ResourceQuota still has the scopes %v", rq.Spec.Scopes)
+				}
+				return nil
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+	})
+})
@@ -0,0 +1,119 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("sharing the Tenant resource quota across Namespaces", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "sharedquota",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "ruth",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NetworkPolicies:    []networkingv1.NetworkPolicySpec{},
+			NamespaceQuota:     2,
+			NodeSelector:       map[string]string{},
+			ResourceQuota: []corev1.ResourceQuotaSpec{
+				{
+					Hard: map[corev1.ResourceName]resource.Quantity{
+						corev1.ResourcePods: resource.MustParse("4"),
+					},
+				},
+			},
+		},
+	}
+	nsl := []string{"shared-first", "shared-second"}
+	hardPods := func(namespace string) func() string {
+		return func() string {
+			rq := &corev1.ResourceQuota{}
+			if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("capsule-%s-0", tnt.GetName()), Namespace: namespace}, rq); err != nil {
+				return ""
+			}
+			q := rq.Spec.Hard[corev1.ResourcePods]
+			return q.String()
+		}
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		for _, i := range nsl {
+			ns := NewNamespace(i)
+			NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+			NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+		}
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should shrink and release the Namespaces quota", func() {
+		cs := ownerClient(tnt)
+
+		By("consuming the Tenant quota in the first Namespace", func() {
+			for i := 0; i < 3; i++ {
+				p := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "pause-" + strconv.Itoa(i),
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name:  "pause",
+								Image: "gcr.io/google_containers/pause-amd64:3.0",
+							},
+						},
+					},
+				}
+				Eventually(func() (err error) {
+					_, err = cs.CoreV1().Pods(nsl[0]).Create(context.TODO(), p, metav1.CreateOptions{})
+					return
+				}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			}
+		})
+		By("leaving the remaining budget to the second Namespace", func() {
+			Eventually(hardPods(nsl[1]), defaultTimeoutInterval, defaultPollInterval).Should(Equal("1"))
+		})
+		By("releasing the usage upon the first Namespace deletion", func() {
+			Expect(k8sClient.Delete(context.TODO(), NewNamespace(nsl[0]))).Should(Succeed())
+			Eventually(hardPods(nsl[1]), podRecreationTimeoutInterval, defaultPollInterval).Should(Equal("4"))
+		})
+	})
+})
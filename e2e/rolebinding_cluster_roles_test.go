@@ -0,0 +1,117 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("binding Cluster Roles in a Tenant restricting them", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "clusterroles",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "mike",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     3,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+			ClusterRoles: v1alpha1.ClusterRolesSpec{
+				Allowed:      []string{"view"},
+				AllowedRegex: "^tenant-.*$",
+			},
+		},
+	}
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should allow only the listed Cluster Roles", func() {
+		ns := NewNamespace("cluster-roles")
+		cs := ownerClient(tnt)
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		newRoleBinding := func(name, kind, role string) *rbacv1.RoleBinding {
+			return &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+				},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: "rbac.authorization.k8s.io",
+					Kind:     kind,
+					Name:     role,
+				},
+				Subjects: []rbacv1.Subject{
+					{
+						APIGroup: "rbac.authorization.k8s.io",
+						Kind:     "User",
+						Name:     "joe",
+					},
+				},
+			}
+		}
+		By("binding a forbidden Cluster Role", func() {
+			_, err := cs.RbacV1().RoleBindings(ns.GetName()).Create(context.TODO(), newRoleBinding("forbidden", "ClusterRole", "edit"), metav1.CreateOptions{})
+			Expect(err).Should(HaveOccurred())
+		})
+		By("binding an allowed Cluster Role", func() {
+			Eventually(func() (err error) {
+				_, err = cs.RbacV1().RoleBindings(ns.GetName()).Create(context.TODO(), newRoleBinding("allowed", "ClusterRole", "view"), metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+		By("binding a namespaced Role", func() {
+			role := &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pods-reader",
+				},
+				Rules: []rbacv1.PolicyRule{
+					{
+						APIGroups: []string{""},
+						Resources: []string{"pods"},
+						Verbs:     []string{"get"},
+					},
+				},
+			}
+			_, err := cs.RbacV1().Roles(ns.GetName()).Create(context.TODO(), role, metav1.CreateOptions{})
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = cs.RbacV1().RoleBindings(ns.GetName()).Create(context.TODO(), newRoleBinding("namespaced", "Role", role.GetName()), metav1.CreateOptions{})
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,89 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+var _ = Describe("creating a Namespace with the Tenant annotation when user owns multiple tenants", func() {
+	tenant := func(name string) *v1alpha1.Tenant {
+		return &v1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: v1alpha1.TenantSpec{
+				Owner: v1alpha1.OwnerSpec{
+					Name: "gina",
+					Kind: "User",
+				},
+				NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+				ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+				IngressClasses:     v1alpha1.IngressClassesSpec{},
+				StorageClasses:     v1alpha1.StorageClassesSpec{},
+				LimitRanges:        []corev1.LimitRangeSpec{},
+				NamespaceQuota:     10,
+				NodeSelector:       map[string]string{},
+				ResourceQuota:      []corev1.ResourceQuotaSpec{},
+			},
+		}
+	}
+	t1 := tenant("annotatedone")
+	t2 := tenant("annotatedtwo")
+	create := func(ns *corev1.Namespace) func() error {
+		return func() (err error) {
+			_, err = ownerClient(t1).CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
+			return
+		}
+	}
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), t1)).Should(Succeed())
+		Expect(k8sClient.Create(context.TODO(), t2)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), t1)).Should(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), t2)).Should(Succeed())
+	})
+	It("should require an existing and owned Tenant", func() {
+		By("listing the candidate Tenants when missing", func() {
+			Eventually(create(NewNamespace("annotated-missing")), defaultTimeoutInterval, defaultPollInterval).
+				Should(HaveDenialReason(capsulewebhook.ReasonTenantSelectionRequired))
+		})
+		By("denying a non-existent Tenant", func() {
+			ns := NewNamespace("annotated-non-existent")
+			ns.SetAnnotations(map[string]string{v1alpha1.TenantAssignmentAnnotation: "annotatedthree"})
+			Eventually(create(ns), defaultTimeoutInterval, defaultPollInterval).
+				Should(HaveDenialReason(capsulewebhook.ReasonTenantNotFound))
+		})
+		By("assigning to the selected Tenant", func() {
+			ns := NewNamespace("annotated-selected")
+			ns.SetAnnotations(map[string]string{v1alpha1.TenantAssignmentAnnotation: t2.GetName()})
+			Eventually(create(ns), defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			NamespaceShouldBeManagedByTenant(ns, t2, defaultTimeoutInterval)
+		})
+	})
+})
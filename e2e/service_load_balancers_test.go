@@ -0,0 +1,126 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+var _ = Describe("disabling the LoadBalancer Services", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "loadbalancers",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "victor",
+				Kind: "User",
+			},
+			NamespacesMetadata:  v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:    v1alpha1.AdditionalMetadata{},
+			IngressClasses:      v1alpha1.IngressClassesSpec{},
+			StorageClasses:      v1alpha1.StorageClassesSpec{},
+			LimitRanges:         []corev1.LimitRangeSpec{},
+			NetworkPolicies:     []networkingv1.NetworkPolicySpec{},
+			NamespaceQuota:      3,
+			NodeSelector:        map[string]string{},
+			ResourceQuota:       []corev1.ResourceQuotaSpec{},
+			EnableLoadBalancers: pointer.BoolPtr(false),
+			LoadBalancerAnnotations: map[string]string{
+				"networking.gke.io/load-balancer-type": "Internal",
+			},
+		},
+	}
+	svc := func(name string, serviceType corev1.ServiceType, annotations map[string]string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: annotations,
+			},
+			Spec: corev1.ServiceSpec{
+				Type: serviceType,
+				Ports: []corev1.ServicePort{
+					{
+						Port:       80,
+						TargetPort: intstr.FromInt(8080),
+					},
+				},
+			},
+		}
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should deny the LoadBalancer Services without the allowed annotations", func() {
+		ns := NewNamespace("loadbalancers-denied")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Services(ns.GetName()).Create(context.TODO(), svc("public", corev1.ServiceTypeLoadBalancer, nil), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(HaveDenialReason(capsulewebhook.ReasonLoadBalancerDisabled))
+	})
+	It("should deny turning a Service into a LoadBalancer one", func() {
+		ns := NewNamespace("loadbalancers-updated")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		s := svc("clusterip", corev1.ServiceTypeClusterIP, nil)
+		Eventually(func() (err error) {
+			s, err = cs.CoreV1().Services(ns.GetName()).Create(context.TODO(), s, metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+
+		s.Spec.Type = corev1.ServiceTypeLoadBalancer
+		_, err := cs.CoreV1().Services(ns.GetName()).Update(context.TODO(), s, metav1.UpdateOptions{})
+		Expect(err).Should(HaveDenialReason(capsulewebhook.ReasonLoadBalancerDisabled))
+	})
+	It("should allow the LoadBalancer Services with an allowed annotation", func() {
+		ns := NewNamespace("loadbalancers-internal")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Services(ns.GetName()).Create(context.TODO(), svc("internal", corev1.ServiceTypeLoadBalancer, tnt.Spec.LoadBalancerAnnotations), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+	})
+})
@@ -0,0 +1,131 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("changing the Tenant Services metadata", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "servicesmetadatasync",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "xavier",
+				Kind: "User",
+			},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata: v1alpha1.AdditionalMetadata{
+				AdditionalLabels: map[string]string{
+					"clastix.io/old-label": "foo",
+				},
+				AdditionalAnnotations: map[string]string{
+					"clastix.io/old-annotation": "bar",
+				},
+			},
+			LimitRanges:    []corev1.LimitRangeSpec{},
+			NamespaceQuota: 3,
+			NodeSelector:   map[string]string{},
+			ResourceQuota:  []corev1.ResourceQuotaSpec{},
+		},
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should update the existing Services retaining the user defined metadata", func() {
+		ns := NewNamespace("services-metadata-sync")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "existing",
+				Namespace: ns.GetName(),
+				Labels: map[string]string{
+					"clastix.io/user-label": "user",
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{
+					{
+						Port:       80,
+						TargetPort: intstr.FromInt(8080),
+					},
+				},
+			},
+		}
+		cs := ownerClient(tnt)
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Services(ns.GetName()).Create(context.TODO(), svc, metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+
+		By("renaming the Tenant Services metadata", func() {
+			Eventually(func() error {
+				t := &v1alpha1.Tenant{}
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+				t.Spec.ServicesMetadata = v1alpha1.AdditionalMetadata{
+					AdditionalLabels: map[string]string{
+						"clastix.io/new-label": "foo",
+					},
+					AdditionalAnnotations: map[string]string{
+						"clastix.io/new-annotation": "bar",
+					},
+				}
+				return k8sClient.Update(context.TODO(), t)
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+		By("checking the labels", func() {
+			Eventually(func() map[string]string {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: svc.GetName(), Namespace: ns.GetName()}, svc)).Should(Succeed())
+				return svc.GetLabels()
+			}, defaultTimeoutInterval, defaultPollInterval).Should(And(
+				HaveKeyWithValue("clastix.io/new-label", "foo"),
+				HaveKeyWithValue("clastix.io/user-label", "user"),
+				Not(HaveKey("clastix.io/old-label")),
+			))
+		})
+		By("checking the annotations", func() {
+			Eventually(func() map[string]string {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: svc.GetName(), Namespace: ns.GetName()}, svc)).Should(Succeed())
+				return svc.GetAnnotations()
+			}, defaultTimeoutInterval, defaultPollInterval).Should(And(
+				HaveKeyWithValue("clastix.io/new-annotation", "bar"),
+				Not(HaveKey("clastix.io/old-annotation")),
+			))
+		})
+	})
+})
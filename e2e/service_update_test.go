@@ -0,0 +1,139 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("updating the Tenant Services", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "serviceupdate",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "uma",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NetworkPolicies:    []networkingv1.NetworkPolicySpec{},
+			NamespaceQuota:     3,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+			ServiceOptions: &v1alpha1.ServiceOptions{
+				AllowedTypes: []v1alpha1.ServiceType{"ClusterIP"},
+			},
+			ExternalServiceIPs: &v1alpha1.ExternalServiceIPsSpec{
+				Allowed: []string{"10.30.0.0/16"},
+			},
+		},
+	}
+	svc := func(name string, externalIPs ...string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{
+					{
+						Port:       80,
+						TargetPort: intstr.FromInt(8080),
+					},
+				},
+				ExternalIPs: externalIPs,
+			},
+		}
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should deny the patches introducing a violation", func() {
+		ns := NewNamespace("service-update-denied")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Services(ns.GetName()).Create(context.TODO(), svc("patched"), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		By("patching the type", func() {
+			_, err := cs.CoreV1().Services(ns.GetName()).Patch(context.TODO(), "patched", types.StrategicMergePatchType, []byte(`{"spec":{"type":"NodePort"}}`), metav1.PatchOptions{})
+			Expect(err).ShouldNot(Succeed())
+		})
+		By("patching the external IPs", func() {
+			_, err := cs.CoreV1().Services(ns.GetName()).Patch(context.TODO(), "patched", types.StrategicMergePatchType, []byte(`{"spec":{"externalIPs":["8.8.8.8"]}}`), metav1.PatchOptions{})
+			Expect(err).ShouldNot(Succeed())
+		})
+	})
+	It("should tolerate the existing violations", func() {
+		ns := NewNamespace("service-update-tolerated")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().Services(ns.GetName()).Create(context.TODO(), svc("existing", "10.30.1.1"), metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		By("tightening the Tenant external IPs", func() {
+			Expect(retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+				if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, tnt); err != nil {
+					return err
+				}
+				tnt.Spec.ExternalServiceIPs.Allowed = []string{"10.40.0.0/16"}
+				return k8sClient.Update(context.TODO(), tnt)
+			})).Should(Succeed())
+			Eventually(func() (err error) {
+				_, err = cs.CoreV1().Services(ns.GetName()).Create(context.TODO(), svc("probe", "10.30.1.2"), metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+		})
+		By("patching an unrelated field", func() {
+			_, err := cs.CoreV1().Services(ns.GetName()).Patch(context.TODO(), "existing", types.StrategicMergePatchType, []byte(`{"metadata":{"labels":{"app":"existing"}}}`), metav1.PatchOptions{})
+			Expect(err).Should(Succeed())
+		})
+		By("adding an external IP out of the allowed CIDRs", func() {
+			_, err := cs.CoreV1().Services(ns.GetName()).Patch(context.TODO(), "existing", types.StrategicMergePatchType, []byte(`{"spec":{"externalIPs":["10.30.1.1","10.30.2.2"]}}`), metav1.PatchOptions{})
+			Expect(err).ShouldNot(Succeed())
+		})
+	})
+})
@@ -61,7 +61,7 @@ var _ = Describe("when Tenant handles Storage classes", func() {
 		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
 	})
 	JustAfterEach(func() {
-		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
 	})
 	It("should block non allowed Storage Class", func() {
 		ns := NewNamespace("storage-class-disallowed")
@@ -113,3 +113,13 @@ func ownerClient(tenant *capsulev1alpha.Tenant) (cs kubernetes.Interface) {
 	Expect(err).ToNot(HaveOccurred())
 	return
 }
+
+func groupsClient(user string, groups ...string) (cs kubernetes.Interface) {
+	c, err := config.GetConfig()
+	Expect(err).ToNot(HaveOccurred())
+	c.Impersonate.Groups = append([]string{capsulev1alpha.GroupVersion.Group}, groups...)
+	c.Impersonate.UserName = user
+	cs, err = kubernetes.NewForConfig(c)
+	Expect(err).ToNot(HaveOccurred())
+	return
+}
@@ -0,0 +1,115 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+var _ = Describe("seeding the Tenant additional resources in the Namespaces", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "additionalresources",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "helen",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     3,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+			AdditionalResources: []v1alpha1.AdditionalResourceSpec{
+				{
+					Name:      "registry",
+					Protected: true,
+					Object: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"registry"},"type":"kubernetes.io/dockerconfigjson","data":{".dockerconfigjson":"eyJhdXRocyI6e319"}}`),
+					},
+				},
+				{
+					Name: "proxy",
+					Object: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"proxy"},"data":{"HTTPS_PROXY":"http://proxy.acme.corp:3128"}}`),
+					},
+				},
+			},
+		},
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should seed, protect, and prune the additional resources", func() {
+		ns := NewNamespace("additional-resources")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		cs := ownerClient(tnt)
+
+		By("creating the objects in the Namespace", func() {
+			Eventually(func() (err error) {
+				_, err = cs.CoreV1().Secrets(ns.GetName()).Get(context.TODO(), "registry", metav1.GetOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Eventually(func() (err error) {
+				_, err = cs.CoreV1().ConfigMaps(ns.GetName()).Get(context.TODO(), "proxy", metav1.GetOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+		By("denying the deletion of the protected object", func() {
+			err := cs.CoreV1().Secrets(ns.GetName()).Delete(context.TODO(), "registry", metav1.DeleteOptions{})
+			Expect(err).Should(HaveDenialReason(capsulewebhook.ReasonManagedResourceProtected))
+		})
+		By("allowing the deletion of the not protected object", func() {
+			Expect(cs.CoreV1().ConfigMaps(ns.GetName()).Delete(context.TODO(), "proxy", metav1.DeleteOptions{})).Should(Succeed())
+		})
+		By("pruning the object removed from the Tenant", func() {
+			Eventually(func() error {
+				if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, tnt); err != nil {
+					return err
+				}
+				tnt.Spec.AdditionalResources = tnt.Spec.AdditionalResources[1:]
+				return k8sClient.Update(context.TODO(), tnt)
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Eventually(func() bool {
+				_, err := cs.CoreV1().Secrets(ns.GetName()).Get(context.TODO(), "registry", metav1.GetOptions{})
+				return err != nil
+			}, defaultTimeoutInterval, defaultPollInterval).Should(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,120 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+var _ = Describe("cordoning a Tenant", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cordoning",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "yolanda",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     3,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	cordon := func(cordoned bool) {
+		Eventually(func() error {
+			t := &v1alpha1.Tenant{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+			t.Spec.Cordoned = cordoned
+			return k8sClient.Update(context.TODO(), t)
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		state := v1alpha1.TenantStateActive
+		if cordoned {
+			state = v1alpha1.TenantStateCordoned
+		}
+		Eventually(func() v1alpha1.TenantState {
+			t := &v1alpha1.Tenant{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+			return t.Status.State
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Equal(state))
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should freeze the Tenant resources until uncordoned", func() {
+		ns := NewNamespace("cordoning-resources")
+		cs := ownerClient(tnt)
+
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "frozen",
+			},
+		}
+		Eventually(func() (err error) {
+			_, err = cs.CoreV1().ConfigMaps(ns.GetName()).Create(context.TODO(), cm, metav1.CreateOptions{})
+			return
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+
+		By("cordoning the Tenant", func() {
+			cordon(true)
+		})
+		By("denying the changes to the resources", func() {
+			Eventually(func() error {
+				return cs.CoreV1().ConfigMaps(ns.GetName()).Delete(context.TODO(), cm.GetName(), metav1.DeleteOptions{})
+			}, defaultTimeoutInterval, defaultPollInterval).Should(HaveDenialReason(capsulewebhook.ReasonTenantCordoned))
+			_, err := cs.CoreV1().ConfigMaps(ns.GetName()).Create(context.TODO(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "denied"}}, metav1.CreateOptions{})
+			Expect(err).Should(HaveDenialReason(capsulewebhook.ReasonTenantCordoned))
+		})
+		By("denying the Namespace creation", func() {
+			NamespaceCreationShouldNotSucceed(NewNamespace("cordoning-denied"), tnt, defaultTimeoutInterval)
+		})
+		By("allowing the changes of the cluster administrators", func() {
+			Expect(k8sClient.Create(context.TODO(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "admin", Namespace: ns.GetName()}})).Should(Succeed())
+		})
+		By("uncordoning the Tenant", func() {
+			cordon(false)
+		})
+		By("allowing the changes to the resources", func() {
+			Eventually(func() error {
+				return cs.CoreV1().ConfigMaps(ns.GetName()).Delete(context.TODO(), cm.GetName(), metav1.DeleteOptions{})
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+	})
+})
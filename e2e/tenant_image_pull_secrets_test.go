@@ -0,0 +1,131 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("replicating the Tenant image pull secrets", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pullsecrets",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "irene",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     3,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+			ImagePullSecrets: []v1alpha1.ImagePullSecretSpec{
+				{Namespace: "pull-secrets-source", Name: "registry"},
+			},
+			PatchDefaultServiceAccount: true,
+		},
+	}
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "registry",
+			Namespace: "pull-secrets-source",
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{}}`),
+		},
+	}
+	replica := func(namespace string) func() (*corev1.Secret, error) {
+		return func() (*corev1.Secret, error) {
+			s := &corev1.Secret{}
+			err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: source.GetName()}, s)
+			return s, err
+		}
+	}
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), NewNamespace(source.GetNamespace()))).Should(Succeed())
+		source.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), source)).Should(Succeed())
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+		Expect(k8sClient.Delete(context.TODO(), NewNamespace(source.GetNamespace()))).Should(Succeed())
+	})
+	It("should replicate, update, and prune the image pull secrets", func() {
+		ns := NewNamespace("pull-secrets")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		By("replicating the source Secret", func() {
+			Eventually(func() (err error) {
+				_, err = replica(ns.GetName())()
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+		By("appending the replica to the default ServiceAccount", func() {
+			Eventually(func() []corev1.LocalObjectReference {
+				sa := &corev1.ServiceAccount{}
+				if err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: ns.GetName(), Name: "default"}, sa); err != nil {
+					return nil
+				}
+				return sa.ImagePullSecrets
+			}, defaultTimeoutInterval, defaultPollInterval).Should(ContainElement(corev1.LocalObjectReference{Name: source.GetName()}))
+		})
+		By("updating the replica upon the source change", func() {
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: source.GetNamespace(), Name: source.GetName()}, source)).Should(Succeed())
+			source.Data[corev1.DockerConfigJsonKey] = []byte(`{"auths":{"registry.oil-inc.com":{}}}`)
+			Expect(k8sClient.Update(context.TODO(), source)).Should(Succeed())
+			Eventually(func() string {
+				s, err := replica(ns.GetName())()
+				if err != nil {
+					return ""
+				}
+				return string(s.Data[corev1.DockerConfigJsonKey])
+			}, defaultTimeoutInterval, defaultPollInterval).Should(ContainSubstring("registry.oil-inc.com"))
+		})
+		By("pruning the replica no more listed", func() {
+			Eventually(func() error {
+				if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, tnt); err != nil {
+					return err
+				}
+				tnt.Spec.ImagePullSecrets = nil
+				return k8sClient.Update(context.TODO(), tnt)
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Eventually(func() bool {
+				_, err := replica(ns.GetName())()
+				return err != nil
+			}, defaultTimeoutInterval, defaultPollInterval).Should(BeTrue())
+		})
+	})
+})
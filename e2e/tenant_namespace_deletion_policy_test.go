@@ -0,0 +1,103 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("deleting a Tenant", func() {
+	newTenant := func(name string, policy v1alpha1.NamespaceDeletionPolicy) *v1alpha1.Tenant {
+		return &v1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: v1alpha1.TenantSpec{
+				Owner: v1alpha1.OwnerSpec{
+					Name: "gus",
+					Kind: "User",
+				},
+				NamespacesMetadata: v1alpha1.AdditionalMetadata{
+					AdditionalLabels: map[string]string{
+						"clastix.io/tenant-label": "foo",
+					},
+				},
+				ServicesMetadata:        v1alpha1.AdditionalMetadata{},
+				IngressClasses:          v1alpha1.IngressClassesSpec{},
+				StorageClasses:          v1alpha1.StorageClassesSpec{},
+				LimitRanges:             []corev1.LimitRangeSpec{},
+				NamespaceQuota:          3,
+				NodeSelector:            map[string]string{},
+				ResourceQuota:           []corev1.ResourceQuotaSpec{},
+				NamespaceDeletionPolicy: policy,
+			},
+		}
+	}
+	It("should release the Namespaces with the Orphan policy", func() {
+		tnt := newTenant("orphanpolicy", v1alpha1.NamespaceDeletionPolicyOrphan)
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		ns := NewNamespace("orphan-policy")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		By("waiting for the owner RoleBinding", func() {
+			Eventually(func() error {
+				return k8sClient.Get(context.TODO(), types.NamespacedName{Name: "namespace:admin", Namespace: ns.GetName()}, &rbacv1.RoleBinding{})
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+
+		By("checking the Namespace has been released", func() {
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: ns.GetName()}, ns)).Should(Succeed())
+			Expect(ns.GetDeletionTimestamp()).Should(BeNil())
+			Expect(ns.GetOwnerReferences()).Should(BeEmpty())
+			Expect(ns.GetLabels()).ShouldNot(HaveKey("capsule.clastix.io/tenant"))
+			Expect(ns.GetLabels()).ShouldNot(HaveKey("clastix.io/tenant-label"))
+		})
+		By("checking the Capsule objects have been deleted", func() {
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "namespace:admin", Namespace: ns.GetName()}, &rbacv1.RoleBinding{}))
+			}, defaultTimeoutInterval, defaultPollInterval).Should(BeTrue())
+		})
+		Expect(k8sClient.Delete(context.TODO(), ns)).Should(Succeed())
+	})
+	It("should delete the Namespaces with the Delete policy", func() {
+		tnt := newTenant("deletepolicy", v1alpha1.NamespaceDeletionPolicyDelete)
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		ns := NewNamespace("delete-policy")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		TenantDeletionShouldSucceed(tnt, podRecreationTimeoutInterval)
+
+		By("checking the Namespace has been deleted", func() {
+			Expect(errors.IsNotFound(k8sClient.Get(context.TODO(), types.NamespacedName{Name: ns.GetName()}, ns))).Should(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,109 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("creating the Tenant resources with additional metadata", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "resourcesmetadata",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "hank",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			AdditionalMetadata: v1alpha1.AdditionalMetadata{
+				AdditionalLabels: map[string]string{
+					"clastix.io/cost-center":    "foo",
+					"capsule.clastix.io/tenant": "override",
+				},
+				AdditionalAnnotations: map[string]string{
+					"clastix.io/chargeback": "bar",
+				},
+			},
+			IngressClasses: v1alpha1.IngressClassesSpec{},
+			StorageClasses: v1alpha1.StorageClassesSpec{},
+			LimitRanges:    []corev1.LimitRangeSpec{},
+			NamespaceQuota: 3,
+			NodeSelector:   map[string]string{},
+			ResourceQuota: []corev1.ResourceQuotaSpec{
+				{
+					Hard: corev1.ResourceList{
+						corev1.ResourcePods: resource.MustParse("10"),
+					},
+				},
+			},
+		},
+	}
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should apply and remove the additional metadata", func() {
+		ns := NewNamespace("resources-metadata")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		rq := &corev1.ResourceQuota{}
+		By("checking the additional metadata", func() {
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: "capsule-resourcesmetadata-0", Namespace: ns.GetName()}, rq); err != nil {
+					return nil
+				}
+				return rq.GetLabels()
+			}, defaultTimeoutInterval, defaultPollInterval).Should(And(
+				HaveKeyWithValue("clastix.io/cost-center", "foo"),
+				HaveKeyWithValue("capsule.clastix.io/tenant", tnt.GetName()),
+			))
+			Expect(rq.GetAnnotations()).Should(HaveKeyWithValue("clastix.io/chargeback", "bar"))
+		})
+		By("removing the additional metadata from the Tenant", func() {
+			Eventually(func() error {
+				t := &v1alpha1.Tenant{}
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+				t.Spec.AdditionalMetadata = v1alpha1.AdditionalMetadata{}
+				return k8sClient.Update(context.TODO(), t)
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		})
+		By("checking the additional metadata has been removed", func() {
+			Eventually(func() map[string]string {
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "capsule-resourcesmetadata-0", Namespace: ns.GetName()}, rq)).Should(Succeed())
+				return rq.GetLabels()
+			}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(HaveKey("clastix.io/cost-center"))
+			Expect(rq.GetAnnotations()).ShouldNot(HaveKey("clastix.io/chargeback"))
+		})
+	})
+})
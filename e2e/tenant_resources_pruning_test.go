@@ -0,0 +1,147 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("removing Tenant managed Kubernetes resources", func() {
+	quota := func(resourceName corev1.ResourceName, value string) corev1.ResourceQuotaSpec {
+		return corev1.ResourceQuotaSpec{
+			Hard: map[corev1.ResourceName]resource.Quantity{
+				resourceName: resource.MustParse(value),
+			},
+		}
+	}
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenantresourcespruning",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "nora",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges: []corev1.LimitRangeSpec{
+				{
+					Limits: []corev1.LimitRangeItem{
+						{
+							Type: corev1.LimitTypeContainer,
+							Max: map[corev1.ResourceName]resource.Quantity{
+								corev1.ResourceCPU: resource.MustParse("1"),
+							},
+						},
+					},
+				},
+			},
+			NamespaceQuota: 1,
+			NodeSelector:   map[string]string{},
+			ResourceQuota: []corev1.ResourceQuotaSpec{
+				quota(corev1.ResourcePods, "10"),
+				quota(corev1.ResourceServices, "5"),
+				quota(corev1.ResourceConfigMaps, "20"),
+			},
+		},
+	}
+	ns := NewNamespace("nora-pruning")
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	updateTenant := func(fn func(t *v1alpha1.Tenant)) {
+		Eventually(func() error {
+			t := &v1alpha1.Tenant{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+			fn(t)
+			return k8sClient.Update(context.TODO(), t)
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+	}
+	resourceQuota := func(i int) *corev1.ResourceQuota {
+		rq := &corev1.ResourceQuota{}
+		Eventually(func() error {
+			return k8sClient.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("capsule-%s-%d", tnt.GetName(), i), Namespace: ns.GetName()}, rq)
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+		return rq
+	}
+	It("should update the reordered ones in place and prune the removed ones", func() {
+		uids := make([]types.UID, 0, len(tnt.Spec.ResourceQuota))
+		for i := range tnt.Spec.ResourceQuota {
+			uids = append(uids, resourceQuota(i).GetUID())
+		}
+
+		By("reordering the Resource Quotas", func() {
+			updateTenant(func(t *v1alpha1.Tenant) {
+				t.Spec.ResourceQuota[0], t.Spec.ResourceQuota[1] = t.Spec.ResourceQuota[1], t.Spec.ResourceQuota[0]
+			})
+			Eventually(func() corev1.ResourceList {
+				return resourceQuota(0).Spec.Hard
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Equal(quota(corev1.ResourceServices, "5").Hard))
+			for i, uid := range uids {
+				Expect(resourceQuota(i).GetUID()).Should(Equal(uid))
+			}
+		})
+
+		By("removing a Resource Quota", func() {
+			updateTenant(func(t *v1alpha1.Tenant) {
+				t.Spec.ResourceQuota = append(t.Spec.ResourceQuota[:1], t.Spec.ResourceQuota[2:]...)
+			})
+			Eventually(func() error {
+				return k8sClient.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("capsule-%s-2", tnt.GetName()), Namespace: ns.GetName()}, &corev1.ResourceQuota{})
+			}, defaultTimeoutInterval, defaultPollInterval).ShouldNot(Succeed())
+			Eventually(func() corev1.ResourceList {
+				return resourceQuota(1).Spec.Hard
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Equal(quota(corev1.ResourceConfigMaps, "20").Hard))
+			for i, uid := range uids[:2] {
+				Expect(resourceQuota(i).GetUID()).Should(Equal(uid))
+			}
+		})
+
+		By("removing the Limit Ranges", func() {
+			updateTenant(func(t *v1alpha1.Tenant) {
+				t.Spec.LimitRanges = []corev1.LimitRangeSpec{}
+			})
+			Eventually(func() []corev1.LimitRange {
+				ll := &corev1.LimitRangeList{}
+				Expect(k8sClient.List(context.TODO(), ll, client.InNamespace(ns.GetName()))).Should(Succeed())
+				return ll.Items
+			}, defaultTimeoutInterval, defaultPollInterval).Should(BeEmpty())
+		})
+	})
+})
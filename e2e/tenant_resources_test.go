@@ -28,6 +28,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -165,6 +166,18 @@ var _ = Describe("creating namespaces within a Tenant with resources", func() {
 					},
 				},
 			},
+			AdditionalRoleBindings: []v1alpha1.AdditionalRoleBindings{
+				{
+					ClusterRoleName: "view",
+					Subjects: []rbacv1.Subject{
+						{
+							APIGroup: "rbac.authorization.k8s.io",
+							Kind:     "Group",
+							Name:     "auditors",
+						},
+					},
+				},
+			},
 		},
 	}
 	nsl := []string{"bim", "bum", "bam"}
@@ -226,6 +239,17 @@ var _ = Describe("creating namespaces within a Tenant with resources", func() {
 					Expect(rq.Spec).Should(Equal(s))
 				}
 			})
+			By("checking the additional Role Binding resources", func() {
+				for i, s := range tnt.Spec.AdditionalRoleBindings {
+					n := fmt.Sprintf("capsule-%s-%d", tnt.GetName(), i)
+					rb := &rbacv1.RoleBinding{}
+					Eventually(func() error {
+						return k8sClient.Get(context.TODO(), types.NamespacedName{Name: n, Namespace: name}, rb)
+					}, 10*time.Second, time.Second).Should(Succeed())
+					Expect(rb.RoleRef.Name).Should(Equal(s.ClusterRoleName))
+					Expect(rb.Subjects).Should(Equal(s.Subjects))
+				}
+			})
 		}
 	})
 })
@@ -0,0 +1,110 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("validating the Tenant spec", func() {
+	newTenant := func(name string) *v1alpha1.Tenant {
+		return &v1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: v1alpha1.TenantSpec{
+				Owner: v1alpha1.OwnerSpec{
+					Name: "john",
+					Kind: "User",
+				},
+				NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+				ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+				IngressClasses:     v1alpha1.IngressClassesSpec{},
+				StorageClasses:     v1alpha1.StorageClassesSpec{},
+				LimitRanges:        []corev1.LimitRangeSpec{},
+				NamespaceQuota:     10,
+				NodeSelector:       map[string]string{},
+				ResourceQuota:      []corev1.ResourceQuotaSpec{},
+			},
+		}
+	}
+	It("should fail with an empty additional owner name", func() {
+		tnt := newTenant("emptyowner")
+		tnt.Spec.Owners = []v1alpha1.OwnerSpec{{Name: "", Kind: "User"}}
+		Expect(k8sClient.Create(context.TODO(), tnt)).ShouldNot(Succeed())
+	})
+	It("should fail with an invalid allowed regex", func() {
+		tnt := newTenant("invalidregex")
+		tnt.Spec.IngressClasses.AllowedRegex = "^oil-(.*"
+		Expect(k8sClient.Create(context.TODO(), tnt)).ShouldNot(Succeed())
+	})
+	It("should fail with a resource limited twice with the same scopes", func() {
+		tnt := newTenant("duplicatequota")
+		tnt.Spec.ResourceQuota = []corev1.ResourceQuotaSpec{
+			{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}},
+			{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("5")}},
+		}
+		Expect(k8sClient.Create(context.TODO(), tnt)).ShouldNot(Succeed())
+	})
+	It("should succeed with a resource limited with different scopes", func() {
+		tnt := newTenant("scopedquota")
+		tnt.Spec.ResourceQuota = []corev1.ResourceQuotaSpec{
+			{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}, Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}},
+			{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("5")}, Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeNotBestEffort}},
+		}
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should fail with an extended resource limited without the requests prefix", func() {
+		for name, rn := range map[string]corev1.ResourceName{"unprefixedquota": "example.com/dongle", "limitsquota": "limits.example.com/dongle", "unqualifiedquota": "requests.example.com/"} {
+			tnt := newTenant(name)
+			tnt.Spec.ResourceQuota = []corev1.ResourceQuotaSpec{
+				{Hard: corev1.ResourceList{rn: resource.MustParse("2")}},
+			}
+			Expect(k8sClient.Create(context.TODO(), tnt)).ShouldNot(Succeed())
+		}
+	})
+	It("should require the acknowledgment to change the owner kind", func() {
+		tnt := newTenant("ownerkindchange")
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+		}()
+		By("changing the kind without the annotation", func() {
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, tnt)).Should(Succeed())
+			tnt.Spec.Owner.Kind = "Group"
+			Expect(k8sClient.Update(context.TODO(), tnt)).ShouldNot(Succeed())
+		})
+		By("changing the kind with the annotation", func() {
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, tnt)).Should(Succeed())
+			tnt.SetAnnotations(map[string]string{v1alpha1.OwnerKindChangeAnnotation: "Group"})
+			tnt.Spec.Owner.Kind = "Group"
+			Expect(k8sClient.Update(context.TODO(), tnt)).Should(Succeed())
+		})
+	})
+})
@@ -0,0 +1,93 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+var _ = Describe("checking the Tenant status", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenantstatus",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "zoe",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     3,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+		},
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		TenantDeletionShouldSucceed(tnt, defaultTimeoutInterval)
+	})
+	It("should list the sorted Namespaces and their count", func() {
+		for _, name := range []string{"status-zulu", "status-alpha"} {
+			ns := NewNamespace(name)
+			NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+			NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+		}
+
+		t := &v1alpha1.Tenant{}
+		Eventually(func() v1alpha1.NamespaceList {
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+			return t.Status.Namespaces
+		}, defaultTimeoutInterval, defaultPollInterval).Should(Equal(v1alpha1.NamespaceList{"status-alpha", "status-zulu"}))
+		Expect(t.Status.Size).Should(Equal(uint(2)))
+		Expect(t.Status.State).Should(Equal(v1alpha1.TenantStateActive))
+	})
+	It("should report the reconciliation conditions", func() {
+		ns := NewNamespace("status-conditions")
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+
+		for _, conditionType := range []v1alpha1.TenantConditionType{v1alpha1.TenantConditionReady, v1alpha1.TenantConditionQuotaSynced, v1alpha1.TenantConditionRBACSynced} {
+			Eventually(func() corev1.ConditionStatus {
+				t := &v1alpha1.Tenant{}
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: tnt.GetName()}, t)).Should(Succeed())
+				for _, c := range t.Status.Conditions {
+					if c.Type == conditionType {
+						return c.Status
+					}
+				}
+				return corev1.ConditionUnknown
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Equal(corev1.ConditionTrue))
+		}
+	})
+})
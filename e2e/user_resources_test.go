@@ -0,0 +1,170 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+var _ = Describe("creating ResourceQuotas, LimitRanges, and RoleBindings as Tenant user", func() {
+	tnt := &v1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "userresources",
+		},
+		Spec: v1alpha1.TenantSpec{
+			Owner: v1alpha1.OwnerSpec{
+				Name: "ulric",
+				Kind: "User",
+			},
+			NamespacesMetadata: v1alpha1.AdditionalMetadata{},
+			ServicesMetadata:   v1alpha1.AdditionalMetadata{},
+			IngressClasses:     v1alpha1.IngressClassesSpec{},
+			StorageClasses:     v1alpha1.StorageClassesSpec{},
+			LimitRanges:        []corev1.LimitRangeSpec{},
+			NamespaceQuota:     3,
+			NodeSelector:       map[string]string{},
+			ResourceQuota:      []corev1.ResourceQuotaSpec{},
+			UserResources: &v1alpha1.UserResourcesSpec{
+				RestrictRoleBindings: true,
+				ResourceQuotas:       v1alpha1.UserResourcePolicyDeny,
+				LimitRanges:          v1alpha1.UserResourcePolicyLabel,
+			},
+		},
+	}
+	ns := NewNamespace("ulric-resources")
+	// the admin ClusterRole bound to the Tenant owner grants the read-only access to ResourceQuotas and LimitRanges
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "quota-editor",
+			Namespace: ns.GetName(),
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"resourcequotas", "limitranges"},
+				Verbs:     []string{"create"},
+			},
+		},
+	}
+	rb := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "quota-editor",
+			Namespace: ns.GetName(),
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     role.GetName(),
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "User",
+				Name:     "ulric",
+			},
+		},
+	}
+	JustBeforeEach(func() {
+		tnt.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), tnt)).Should(Succeed())
+		NamespaceCreationShouldSucceed(ns, tnt, defaultTimeoutInterval)
+		NamespaceShouldBeManagedByTenant(ns, tnt, defaultTimeoutInterval)
+		role.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), role)).Should(Succeed())
+		rb.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), rb)).Should(Succeed())
+	})
+	JustAfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), tnt)).Should(Succeed())
+	})
+	It("should enforce each restriction of the Tenant", func() {
+		cs := ownerClient(tnt)
+
+		By("denying the ResourceQuotas", func() {
+			rq := &corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "tighter",
+				},
+				Spec: corev1.ResourceQuotaSpec{
+					Hard: corev1.ResourceList{
+						corev1.ResourcePods: resource.MustParse("1"),
+					},
+				},
+			}
+			Eventually(func() (err error) {
+				_, err = cs.CoreV1().ResourceQuotas(ns.GetName()).Create(context.TODO(), rq, metav1.CreateOptions{})
+				return
+			}, defaultTimeoutInterval, defaultPollInterval).Should(HaveDenialReason(capsulewebhook.ReasonUserResourceForbidden))
+		})
+		By("labelling the LimitRanges", func() {
+			lr := &corev1.LimitRange{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "tighter",
+				},
+				Spec: corev1.LimitRangeSpec{
+					Limits: []corev1.LimitRangeItem{
+						{
+							Type: corev1.LimitTypeContainer,
+							Max: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("100m"),
+							},
+						},
+					},
+				},
+			}
+			var err error
+			Eventually(func() error {
+				lr, err = cs.CoreV1().LimitRanges(ns.GetName()).Create(context.TODO(), lr, metav1.CreateOptions{})
+				return err
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Succeed())
+			Expect(lr.GetLabels()).Should(HaveKeyWithValue(v1alpha1.UserResourceLabel, tnt.GetName()))
+		})
+		By("denying the RoleBindings to Cluster Roles, even if none is listed", func() {
+			crb := &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "viewer",
+				},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: "rbac.authorization.k8s.io",
+					Kind:     "ClusterRole",
+					Name:     "view",
+				},
+				Subjects: []rbacv1.Subject{
+					{
+						APIGroup: "rbac.authorization.k8s.io",
+						Kind:     "User",
+						Name:     "joe",
+					},
+				},
+			}
+			_, err := cs.RbacV1().RoleBindings(ns.GetName()).Create(context.TODO(), crb, metav1.CreateOptions{})
+			Expect(err).Should(HaveDenialReason(capsulewebhook.ReasonClusterRoleForbidden))
+		})
+	})
+})
@@ -23,15 +23,18 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
+	gomegatypes "github.com/onsi/gomega/types"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/clastix/capsule/api/v1alpha1"
 	"github.com/clastix/capsule/controllers/rbac"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
 )
 
 const (
@@ -40,6 +43,11 @@ const (
 	defaultPollInterval          = time.Second
 )
 
+// HaveDenialReason matches the errors of the requests denied by Capsule with the given reason.
+func HaveDenialReason(reason capsulewebhook.Reason) gomegatypes.GomegaMatcher {
+	return WithTransform(errors.ReasonForError, Equal(metav1.StatusReason(reason)))
+}
+
 func NewNamespace(name string) *corev1.Namespace {
 	return &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -71,6 +79,14 @@ func NamespaceShouldBeManagedByTenant(ns *corev1.Namespace, t *v1alpha1.Tenant,
 	}, timeout, defaultPollInterval).Should(ContainElement(ns.GetName()))
 }
 
+// TenantDeletionShouldSucceed waits for the Tenant removal, deferred by the finalizer handling its Namespaces.
+func TenantDeletionShouldSucceed(t *v1alpha1.Tenant, timeout time.Duration) {
+	Expect(k8sClient.Delete(context.TODO(), t)).Should(Succeed())
+	Eventually(func() bool {
+		return errors.IsNotFound(k8sClient.Get(context.TODO(), types.NamespacedName{Name: t.GetName()}, &v1alpha1.Tenant{}))
+	}, timeout, defaultPollInterval).Should(BeTrue())
+}
+
 func CapsuleClusterGroupParamShouldBeUpdated(capsuleClusterGroup string, timeout time.Duration) {
 	capsuleCRB := &rbacv1.ClusterRoleBinding{}
 
@@ -127,3 +143,13 @@ func GroupShouldBeUsedInTenantRoleBinding(ns *corev1.Namespace, t *v1alpha1.Tena
 
 	}
 }
+
+func ServiceAccountShouldBeUsedInTenantRoleBinding(ns *corev1.Namespace, namespace, name string, timeout time.Duration) {
+	for _, roleBindingName := range tenantRoleBindingNames {
+		tenantRoleBinding := &rbacv1.RoleBinding{}
+		Eventually(func() rbacv1.Subject {
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: roleBindingName, Namespace: ns.GetName()}, tenantRoleBinding)).Should(Succeed())
+			return tenantRoleBinding.Subjects[0]
+		}, timeout, defaultPollInterval).Should(Equal(rbacv1.Subject{Kind: "ServiceAccount", Namespace: namespace, Name: name}))
+	}
+}
@@ -0,0 +1,88 @@
+//+build e2e
+
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/controllers"
+)
+
+var _ = Describe("changing the webhooks failure policy with the CapsuleConfiguration", func() {
+	const webhookName = "cordoning.tenant.capsule.clastix.io"
+
+	cfg := &v1alpha1.CapsuleConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: v1alpha1.CapsuleConfigurationName,
+		},
+		Spec: v1alpha1.CapsuleConfigurationSpec{
+			WebhookFailurePolicies: map[string]v1alpha1.FailurePolicy{
+				webhookName: v1alpha1.FailurePolicyFail,
+			},
+		},
+	}
+	failurePolicy := func() (admissionregistrationv1.FailurePolicyType, string) {
+		vw := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "capsule-validating-webhook-configuration"}, vw)).Should(Succeed())
+		for _, w := range vw.Webhooks {
+			if w.Name == webhookName && w.FailurePolicy != nil {
+				return *w.FailurePolicy, vw.GetAnnotations()[controllers.InstalledFailurePoliciesAnnotation]
+			}
+		}
+		return "", ""
+	}
+	JustAfterEach(func() {
+		if err := k8sClient.Delete(context.TODO(), cfg); err != nil && !errors.IsNotFound(err) {
+			Expect(err).ToNot(HaveOccurred())
+		}
+	})
+	It("should override the installed one until removed", func() {
+		installed, _ := failurePolicy()
+		Expect(installed).Should(Equal(admissionregistrationv1.Ignore))
+
+		cfg.ResourceVersion = ""
+		Expect(k8sClient.Create(context.TODO(), cfg)).Should(Succeed())
+
+		By("overriding the installed policy", func() {
+			Eventually(func() admissionregistrationv1.FailurePolicyType {
+				policy, _ := failurePolicy()
+				return policy
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Equal(admissionregistrationv1.Fail))
+			_, annotation := failurePolicy()
+			Expect(annotation).Should(ContainSubstring(webhookName))
+		})
+		By("restoring the installed policy upon deletion", func() {
+			Expect(k8sClient.Delete(context.TODO(), cfg)).Should(Succeed())
+			Eventually(func() admissionregistrationv1.FailurePolicyType {
+				policy, _ := failurePolicy()
+				return policy
+			}, defaultTimeoutInterval, defaultPollInterval).Should(Equal(admissionregistrationv1.Ignore))
+			_, annotation := failurePolicy()
+			Expect(annotation).Should(BeEmpty())
+		})
+	})
+})
@@ -13,6 +13,7 @@ require (
 	github.com/onsi/ginkgo v1.12.1
 	github.com/onsi/gomega v1.10.1
 	github.com/pkg/errors v0.9.1 // indirect
+	github.com/prometheus/client_golang v1.0.0
 	github.com/prometheus/procfs v0.0.11 // indirect
 	github.com/stretchr/testify v1.4.0
 	golang.org/x/net v0.0.0-20200707034311-ab3426394381 // indirect
@@ -21,7 +22,7 @@ require (
 	gomodules.xyz/jsonpatch/v2 v2.0.1
 	google.golang.org/protobuf v1.24.0 // indirect
 	k8s.io/api v0.19.0-beta.2
-	k8s.io/apiextensions-apiserver v0.18.6 // indirect
+	k8s.io/apiextensions-apiserver v0.18.6
 	k8s.io/apimachinery v0.19.0-beta.2
 	k8s.io/client-go v0.19.0-beta.2
 	k8s.io/kube-openapi v0.0.0-20200805222855-6aeccd4b50c6 // indirect
@@ -22,7 +22,10 @@ import (
 	"os"
 	"regexp"
 	goRuntime "runtime"
+	"strings"
+	"time"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -36,18 +39,42 @@ import (
 	"github.com/clastix/capsule/controllers"
 	"github.com/clastix/capsule/controllers/rbac"
 	"github.com/clastix/capsule/controllers/secret"
+	"github.com/clastix/capsule/pkg/cert"
+	"github.com/clastix/capsule/pkg/configuration"
 	"github.com/clastix/capsule/pkg/indexer"
 	"github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/cordoning"
+	"github.com/clastix/capsule/pkg/webhook/default_ingress_class"
+	"github.com/clastix/capsule/pkg/webhook/default_registry"
+	"github.com/clastix/capsule/pkg/webhook/default_storage_class"
+	"github.com/clastix/capsule/pkg/webhook/default_tenant"
+	"github.com/clastix/capsule/pkg/webhook/image_pull_policy"
+	"github.com/clastix/capsule/pkg/webhook/image_pull_policy_rewrite"
 	"github.com/clastix/capsule/pkg/webhook/ingress"
+	"github.com/clastix/capsule/pkg/webhook/managed_resources"
+	"github.com/clastix/capsule/pkg/webhook/namespace_labels"
+	"github.com/clastix/capsule/pkg/webhook/namespace_metadata"
+	"github.com/clastix/capsule/pkg/webhook/namespace_protection"
 	"github.com/clastix/capsule/pkg/webhook/namespace_quota"
 	"github.com/clastix/capsule/pkg/webhook/network_policies"
+	"github.com/clastix/capsule/pkg/webhook/node_selector"
+	"github.com/clastix/capsule/pkg/webhook/object_quota"
 	"github.com/clastix/capsule/pkg/webhook/owner_reference"
+	"github.com/clastix/capsule/pkg/webhook/pod_security"
+	"github.com/clastix/capsule/pkg/webhook/priority_class"
 	"github.com/clastix/capsule/pkg/webhook/pvc"
 	"github.com/clastix/capsule/pkg/webhook/registry"
+	"github.com/clastix/capsule/pkg/webhook/rolebinding"
+	secretwebhook "github.com/clastix/capsule/pkg/webhook/secret"
 	"github.com/clastix/capsule/pkg/webhook/service_labels"
+	"github.com/clastix/capsule/pkg/webhook/services"
 	"github.com/clastix/capsule/pkg/webhook/tenant"
 	"github.com/clastix/capsule/pkg/webhook/tenant_prefix"
+	"github.com/clastix/capsule/pkg/webhook/tolerations"
+	"github.com/clastix/capsule/pkg/webhook/user_resources"
+	"github.com/clastix/capsule/pkg/webhook/user_resources_labels"
 	"github.com/clastix/capsule/pkg/webhook/utils"
+	"github.com/clastix/capsule/pkg/webhook/volume_claim_templates"
 	"github.com/clastix/capsule/version"
 	// +kubebuilder:scaffold:imports
 )
@@ -59,11 +86,21 @@ var (
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 
 	utilruntime.Must(capsulev1alpha1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
+func splitList(value string) (items []string) {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+	return
+}
+
 func printVersion() {
 	setupLog.Info(fmt.Sprintf("Operator Version: %s", version.Version))
 	setupLog.Info(fmt.Sprintf("Go Version: %s", goRuntime.Version()))
@@ -76,12 +113,35 @@ func main() {
 	var forceTenantPrefix bool
 	var v bool
 	var capsuleGroup string
+	var capsuleGroups []string
 	var protectedNamespaceRegexpString string
 	var protectedNamespaceRegexp *regexp.Regexp
 	var namespace string
+	var caKeyType string
+	var keyEncoding string
+	var rsaKeySize int
+	var tlsExtraSans string
+	var deploymentName string
+	var minRequeue time.Duration
+	var denyIngressHostnameCollision bool
+	var defaultNamespaceQuota uint
+	var objectQuotaFailurePolicy string
+	var serviceAccount string
+	var caValidity time.Duration
+	var tlsValidity time.Duration
+	var renewBefore uint
+	var enableCertManagement bool
+	var caSecretName string
+	var tlsSecretName string
+	var validatingWebhookConfigurationName string
+	var mutatingWebhookConfigurationName string
+	var excludedNamespaces string
+	var tolerateExistingServiceViolations bool
+	var validateAllTenantPVCs bool
+	var breakGlassGroup string
 
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
-	flag.StringVar(&capsuleGroup, "capsule-user-group", capsulev1alpha1.GroupVersion.Group, "Name of the group for capsule users")
+	flag.StringVar(&capsuleGroup, "capsule-user-group", capsulev1alpha1.GroupVersion.Group, "Comma separated list of the groups for capsule users")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -90,6 +150,37 @@ func main() {
 		"during Namespace creation, to name it using the selected Tenant name as prefix, separated by a dash. "+
 		"This is useful to avoid Namespace name collision in a public CaaS environment.")
 	flag.StringVar(&protectedNamespaceRegexpString, "protected-namespace-regex", "", "Disallow creation of namespaces, whose name matches this regexp")
+	flag.DurationVar(&caValidity, "ca-validity", cert.DefaultCaValidity, "The validity of the generated Capsule CA")
+	flag.DurationVar(&tlsValidity, "tls-validity", cert.DefaultTlsValidity, "The validity of the generated webhook TLS certificate, cannot be longer than the CA one")
+	flag.UintVar(&renewBefore, "renew-before-percentage", cert.DefaultRenewBefore, "The percentage of the remaining certificate lifetime triggering the CA and TLS certificate renewal")
+	flag.BoolVar(&enableCertManagement, "enable-cert-management", true, "Let Capsule manage its CA and webhook TLS certificate: "+
+		"disable it when certificates and CA bundles are handled by an external tool, as cert-manager.")
+	flag.StringVar(&caKeyType, "ca-key-type", cert.RSAKeyType.String(), "The private key algorithm used to generate the Capsule CA and TLS certificates, one of rsa or ecdsa")
+	flag.StringVar(&caSecretName, "ca-secret-name", "capsule-ca", "Name of the Secret holding the CA: when a different one is provided, "+
+		"it must contain the ca.crt and ca.key keys and Capsule will use it to sign the webhook TLS certificate, without generating nor rotating it.")
+	flag.StringVar(&tlsSecretName, "tls-secret-name", "capsule-tls", "Name of the Secret holding the webhook TLS certificate, "+
+		"it must be the one mounted by the Capsule Deployment")
+	flag.StringVar(&validatingWebhookConfigurationName, "validating-webhook-configuration-name", "capsule-validating-webhook-configuration", "Name of the ValidatingWebhookConfiguration the CA bundle is injected to")
+	flag.StringVar(&mutatingWebhookConfigurationName, "mutating-webhook-configuration-name", "capsule-mutating-webhook-configuration", "Name of the MutatingWebhookConfiguration the CA bundle is injected to")
+	flag.StringVar(&keyEncoding, "key-encoding", cert.PKCS1KeyEncoding.String(), "The PEM format of the Capsule CA and TLS certificates private keys, one of pkcs1 or pkcs8")
+	flag.StringVar(&tlsExtraSans, "tls-extra-sans", "", "Comma separated list of additional DNS names and IP addresses of the webhook TLS certificate")
+	flag.StringVar(&deploymentName, "deployment-name", "capsule-controller-manager", "Name of the Capsule Deployment, set as owner of the generated CA and TLS Secrets")
+	flag.IntVar(&rsaKeySize, "rsa-key-size", cert.DefaultRsaKeySize, "The size in bits of the generated RSA keys, one of 2048, 3072 or 4096: changing it forces the CA generation")
+	flag.DurationVar(&minRequeue, "min-requeue-interval", time.Minute, "The minimum interval between two checks of the Capsule CA")
+	flag.BoolVar(&denyIngressHostnameCollision, "deny-ingress-hostname-collision", false, "Deny the Tenant Ingresses claiming a hostname "+
+		"already used by an Ingress living in a Namespace outside of the Tenant")
+	flag.UintVar(&defaultNamespaceQuota, "default-namespace-quota", 10, "The Namespace quota assigned to the Tenants not specifying one")
+	flag.StringVar(&objectQuotaFailurePolicy, "object-quota-failure-policy", "Fail", "How the Tenant object count limits are enforced when "+
+		"the count cannot be computed, one of Fail, denying the creation, or Ignore, allowing it")
+	flag.StringVar(&excludedNamespaces, "excluded-namespaces", "kube-system,kube-public,kube-node-lease", "Comma separated list of the Namespaces "+
+		"labelled with "+capsulev1alpha1.ExcludedNamespaceLabel+", skipped by the webhooks along with the Capsule one")
+	flag.BoolVar(&tolerateExistingServiceViolations, "tolerate-existing-service-violations", true, "Allow the updates of the Services "+
+		"violating the Tenant policies, as the ones created before a policy change, as long as no new violation is introduced")
+	flag.BoolVar(&validateAllTenantPVCs, "validate-all-tenant-pvcs", false, "Validate the Storage Class of all the PVCs created "+
+		"in the Tenant Namespaces, as the ones generated by the StatefulSet controller, rather than only the Tenant users ones")
+	flag.StringVar(&breakGlassGroup, "break-glass-group", "", "Comma separated list of the break-glass groups, whose requests are allowed "+
+		"by the validating webhooks even when denied by the Tenant policies or the Capsule protections, and who can assign the Namespaces "+
+		"they create to any Tenant with the "+capsulev1alpha1.TenantAssignmentAnnotation+" annotation, for emergency operations: leave it empty to disable")
 	opts := zap.Options{}
 
 	opts.BindFlags(flag.CommandLine)
@@ -102,11 +193,60 @@ func main() {
 		os.Exit(0)
 	}
 
+	if !cert.KeyType(caKeyType).IsValid() {
+		setupLog.Error(fmt.Errorf("unsupported key type %s", caKeyType), "unable to start manager")
+		os.Exit(1)
+	}
+
+	if !cert.IsValidRsaKeySize(rsaKeySize) {
+		setupLog.Error(fmt.Errorf("unsupported RSA key size %d", rsaKeySize), "unable to start manager")
+		os.Exit(1)
+	}
+
+	if !cert.KeyEncoding(keyEncoding).IsValid() {
+		setupLog.Error(fmt.Errorf("unsupported key encoding %s", keyEncoding), "unable to start manager")
+		os.Exit(1)
+	}
+
+	if caValidity <= 0 || tlsValidity <= 0 {
+		setupLog.Error(fmt.Errorf("certificate validity must be a positive duration"), "unable to start manager")
+		os.Exit(1)
+	}
+
+	if tlsValidity > caValidity {
+		setupLog.Error(fmt.Errorf("the TLS validity (%s) cannot be longer than the CA one (%s)", tlsValidity, caValidity), "unable to start manager")
+		os.Exit(1)
+	}
+
+	if renewBefore >= 100 {
+		setupLog.Error(fmt.Errorf("the renew-before percentage must be lower than 100"), "unable to start manager")
+		os.Exit(1)
+	}
+
+	if defaultNamespaceQuota == 0 {
+		setupLog.Error(fmt.Errorf("the default Namespace quota must be greater than zero"), "unable to start manager")
+		os.Exit(1)
+	}
+
+	if capsuleGroups = splitList(capsuleGroup); len(capsuleGroups) == 0 {
+		setupLog.Error(fmt.Errorf("at least a capsule user group must be provided"), "unable to start manager")
+		os.Exit(1)
+	}
+
+	if objectQuotaFailurePolicy != "Fail" && objectQuotaFailurePolicy != "Ignore" {
+		setupLog.Error(fmt.Errorf("unsupported object quota failure policy %s", objectQuotaFailurePolicy), "unable to start manager")
+		os.Exit(1)
+	}
+
 	if namespace = os.Getenv("NAMESPACE"); len(namespace) == 0 {
 		setupLog.Error(fmt.Errorf("unable to determinate the Namespace Capsule is running on"), "unable to start manager")
 		os.Exit(1)
 	}
 
+	if serviceAccount = os.Getenv("SERVICE_ACCOUNT"); len(serviceAccount) == 0 {
+		serviceAccount = "default"
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
@@ -127,33 +267,143 @@ func main() {
 		}
 	}
 
+	// the command line options are the defaults, overridden at runtime by the default CapsuleConfiguration
+	cfg := configuration.NewStore(configuration.Configuration{
+		UserGroups:               capsuleGroups,
+		ForceTenantPrefix:        forceTenantPrefix,
+		ProtectedNamespaceRegexp: protectedNamespaceRegexp,
+		CaValidity:               caValidity,
+		TlsValidity:              tlsValidity,
+		RenewBefore:              renewBefore,
+		BreakGlassGroups:         splitList(breakGlassGroup),
+	})
+
 	_ = mgr.AddReadyzCheck("ping", healthz.Ping)
+	_ = mgr.AddReadyzCheck("ca-bundle", webhook.CaBundleCheck(mgr.GetClient(), validatingWebhookConfigurationName))
+	_ = mgr.AddReadyzCheck("ca-bundle-injection", secret.CaBundleInjectionCheck)
 	_ = mgr.AddHealthzCheck("ping", healthz.Ping)
 
 	setupLog.Info("starting with following options:", "metricsAddr", metricsAddr, "enableLeaderElection", enableLeaderElection, "forceTenantPrefix", forceTenantPrefix)
 
+	if err = (&controllers.CapsuleConfigurationReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("CapsuleConfiguration"),
+		Scheme:   mgr.GetScheme(),
+		Store:    cfg,
+		Recorder: mgr.GetEventRecorderFor("capsule-configuration"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CapsuleConfiguration")
+		os.Exit(1)
+	}
+	if err = (&controllers.WebhookFailurePolicyReconciler{
+		Client:                             mgr.GetClient(),
+		Log:                                ctrl.Log.WithName("controllers").WithName("WebhookFailurePolicy"),
+		Scheme:                             mgr.GetScheme(),
+		Configuration:                      cfg,
+		Recorder:                           mgr.GetEventRecorderFor("capsule-webhook-failure-policy"),
+		ValidatingWebhookConfigurationName: validatingWebhookConfigurationName,
+		MutatingWebhookConfigurationName:   mutatingWebhookConfigurationName,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "WebhookFailurePolicy")
+		os.Exit(1)
+	}
 	if err = (&controllers.TenantReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("Tenant"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("capsule-tenant"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Tenant")
+		os.Exit(1)
+	}
+	if err = (&controllers.ServicesMetadataReconciler{
 		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Tenant"),
+		Log:    ctrl.Log.WithName("controllers").WithName("ServicesMetadata"),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Tenant")
+		setupLog.Error(err, "unable to create controller", "controller", "ServicesMetadata")
+		os.Exit(1)
+	}
+	if err = (&controllers.PodDisruptionBudgetReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("PodDisruptionBudget"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PodDisruptionBudget")
+		os.Exit(1)
+	}
+	if err = (&controllers.AdditionalResourcesReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("AdditionalResources"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AdditionalResources")
+		os.Exit(1)
+	}
+	if err = (&controllers.ImagePullSecretsReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("ImagePullSecrets"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ImagePullSecrets")
+		os.Exit(1)
+	}
+	if err = (&controllers.ExcludedNamespacesReconciler{
+		Client:     mgr.GetClient(),
+		Log:        ctrl.Log.WithName("controllers").WithName("ExcludedNamespaces"),
+		Scheme:     mgr.GetScheme(),
+		Namespaces: append(splitList(excludedNamespaces), namespace),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ExcludedNamespaces")
 		os.Exit(1)
 	}
 	// +kubebuilder:scaffold:builder
 
 	// webhooks
+	servingCertificateMounted := webhook.IsServingCertificateMounted()
+	// the validating webhooks enforcing the Tenant policies and the Capsule protections allow the requests of the
+	// break-glass groups, the only bypass of the denials
+	breakGlassRecorder := mgr.GetEventRecorderFor("capsule-break-glass")
+	breakGlass := func(h webhook.Handler) webhook.Handler {
+		return utils.BreakGlass(cfg, breakGlassRecorder, ctrl.Log.WithName("webhooks").WithName("BreakGlass"), h)
+	}
+	pvcHandler := utils.InCapsuleGroup(cfg, pvc.Handler())
+	if validateAllTenantPVCs {
+		pvcHandler = pvc.Handler()
+	}
 	wl := append(
 		make([]webhook.Webhook, 0),
-		ingress.Webhook(utils.InCapsuleGroup(capsuleGroup, ingress.Handler())),
-		pvc.Webhook(utils.InCapsuleGroup(capsuleGroup, pvc.Handler())),
-		registry.Webhook(utils.InCapsuleGroup(capsuleGroup, registry.Handler())),
-		owner_reference.Webhook(utils.InCapsuleGroup(capsuleGroup, owner_reference.Handler(forceTenantPrefix))),
-		namespace_quota.Webhook(utils.InCapsuleGroup(capsuleGroup, namespace_quota.Handler())),
-		network_policies.Webhook(utils.InCapsuleGroup(capsuleGroup, network_policies.Handler())),
-		service_labels.Webhook(utils.InCapsuleGroup(capsuleGroup, service_labels.Handler())),
-		tenant_prefix.Webhook(utils.InCapsuleGroup(capsuleGroup, tenant_prefix.Handler(forceTenantPrefix, protectedNamespaceRegexp))),
+		ingress.Webhook(breakGlass(utils.InCapsuleGroup(cfg, ingress.Handler(denyIngressHostnameCollision)))),
+		pvc.Webhook(breakGlass(pvcHandler)),
+		volume_claim_templates.Webhook(breakGlass(utils.InCapsuleGroup(cfg, volume_claim_templates.Handler()))),
+		registry.Webhook(breakGlass(registry.Handler())),
+		default_ingress_class.Webhook(default_ingress_class.Handler()),
+		default_registry.Webhook(default_registry.Handler()),
+		image_pull_policy.Webhook(breakGlass(image_pull_policy.Handler())),
+		image_pull_policy_rewrite.Webhook(image_pull_policy_rewrite.Handler()),
+		default_storage_class.Webhook(default_storage_class.Handler()),
+		default_tenant.Webhook(default_tenant.Handler(defaultNamespaceQuota)),
+		priority_class.Webhook(breakGlass(priority_class.Handler())),
+		pod_security.Webhook(breakGlass(pod_security.Handler())),
+		node_selector.Webhook(node_selector.Handler()),
+		tolerations.Webhook(tolerations.Handler()),
+		owner_reference.Webhook(utils.InCapsuleGroupOrAssigning(cfg, owner_reference.Handler(cfg))),
+		managed_resources.Webhook(breakGlass(utils.InCapsuleGroup(cfg, managed_resources.Handler(mgr.GetEventRecorderFor("capsule-managed-resources"))))),
+		namespace_labels.Webhook(breakGlass(namespace_labels.Handler(namespace, serviceAccount))),
+		namespace_metadata.Webhook(breakGlass(utils.InCapsuleGroup(cfg, namespace_metadata.Handler(namespace, serviceAccount)))),
+		namespace_protection.Webhook(breakGlass(namespace_protection.Handler())),
+		namespace_quota.Webhook(breakGlass(utils.InCapsuleGroupOrAssigning(cfg, namespace_quota.Handler(mgr.GetEventRecorderFor("capsule-namespace-quota"), mgr.GetAPIReader())))),
+		object_quota.Webhook(breakGlass(object_quota.Handler(objectQuotaFailurePolicy == "Ignore"))),
+		cordoning.Webhook(breakGlass(utils.InCapsuleGroup(cfg, cordoning.Handler()))),
+		network_policies.Webhook(breakGlass(utils.InCapsuleGroup(cfg, network_policies.Handler()))),
+		service_labels.Webhook(utils.InCapsuleGroup(cfg, service_labels.Handler())),
+		services.Webhook(breakGlass(services.Handler(tolerateExistingServiceViolations))),
+		rolebinding.Webhook(breakGlass(utils.InCapsuleGroup(cfg, rolebinding.Handler()))),
+		user_resources.Webhook(breakGlass(utils.InCapsuleGroup(cfg, user_resources.Handler()))),
+		user_resources_labels.Webhook(utils.InCapsuleGroup(cfg, user_resources_labels.Handler())),
+		tenant_prefix.Webhook(breakGlass(utils.InCapsuleGroup(cfg, tenant_prefix.Handler(cfg)))),
 		tenant.Webhook(tenant.Handler()),
+		secretwebhook.Webhook(breakGlass(secretwebhook.Handler(namespace, serviceAccount, caSecretName, tlsSecretName))),
 	)
 	if err = webhook.Register(mgr, wl...); err != nil {
 		setupLog.Error(err, "unable to setup webhooks")
@@ -161,8 +411,8 @@ func main() {
 	}
 
 	rbacManager := &rbac.Manager{
-		Log:          ctrl.Log.WithName("controllers").WithName("Rbac"),
-		CapsuleGroup: capsuleGroup,
+		Log:           ctrl.Log.WithName("controllers").WithName("Rbac"),
+		Configuration: cfg,
 	}
 	if err = mgr.Add(rbacManager); err != nil {
 		setupLog.Error(err, "unable to create cluster roles")
@@ -173,23 +423,47 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&secret.CaReconciler{
-		Client:    mgr.GetClient(),
-		Log:       ctrl.Log.WithName("controllers").WithName("CA"),
-		Scheme:    mgr.GetScheme(),
-		Namespace: namespace,
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Namespace")
-		os.Exit(1)
-	}
-	if err = (&secret.TlsReconciler{
-		Client:    mgr.GetClient(),
-		Log:       ctrl.Log.WithName("controllers").WithName("Tls"),
-		Scheme:    mgr.GetScheme(),
-		Namespace: namespace,
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Namespace")
-		os.Exit(1)
+	if enableCertManagement {
+		if err = (&secret.CaReconciler{
+			Client:                             mgr.GetClient(),
+			Log:                                ctrl.Log.WithName("controllers").WithName("CA"),
+			Scheme:                             mgr.GetScheme(),
+			Namespace:                          namespace,
+			KeyType:                            cert.KeyType(caKeyType),
+			RsaKeySize:                         rsaKeySize,
+			KeyEncoding:                        cert.KeyEncoding(keyEncoding),
+			Configuration:                      cfg,
+			DeploymentName:                     deploymentName,
+			MinRequeue:                         minRequeue,
+			TlsExtraSans:                       splitList(tlsExtraSans),
+			CaSecretName:                       caSecretName,
+			TlsSecretName:                      tlsSecretName,
+			ValidatingWebhookConfigurationName: validatingWebhookConfigurationName,
+			MutatingWebhookConfigurationName:   mutatingWebhookConfigurationName,
+			Recorder:                           mgr.GetEventRecorderFor("capsule-ca"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Namespace")
+			os.Exit(1)
+		}
+		if err = (&secret.TlsReconciler{
+			Client:          mgr.GetClient(),
+			Log:             ctrl.Log.WithName("controllers").WithName("Tls"),
+			Scheme:          mgr.GetScheme(),
+			Namespace:       namespace,
+			KeyEncoding:     cert.KeyEncoding(keyEncoding),
+			ExtraSans:       splitList(tlsExtraSans),
+			Configuration:   cfg,
+			CaSecretName:    caSecretName,
+			TlsSecretName:   tlsSecretName,
+			RestartOnUpdate: !servingCertificateMounted,
+			DeploymentName:  deploymentName,
+			Recorder:        mgr.GetEventRecorderFor("capsule-tls"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Namespace")
+			os.Exit(1)
+		}
+	} else {
+		setupLog.Info("certificate management is disabled, CA and TLS certificate must be provided externally")
 	}
 
 	if err = indexer.AddToManager(mgr); err != nil {
@@ -18,8 +18,8 @@ package cert
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
@@ -32,25 +32,58 @@ type Ca interface {
 	CaCertificatePem() (b *bytes.Buffer, err error)
 	CaPrivateKeyPem() (b *bytes.Buffer, err error)
 	ExpiresIn(now time.Time) (time.Duration, error)
+	RenewIn(now time.Time, renewBefore uint) time.Duration
 	ValidateCert(certificate *x509.Certificate) error
+	CheckSignature(certificate *x509.Certificate) error
+	SetKeyEncoding(encoding KeyEncoding)
+	SetClock(clock Clock)
 }
 
 type CapsuleCa struct {
-	ca         *x509.Certificate
-	privateKey *rsa.PrivateKey
+	ca *x509.Certificate
+	// chain holds the certificates the CA has been issued by, ordered from the CA issuer up to the root
+	chain       []*x509.Certificate
+	privateKey  crypto.Signer
+	keyEncoding KeyEncoding
+	clock       Clock
+}
+
+func (c CapsuleCa) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+// SetKeyEncoding sets the format of both the CA and the issued certificates private keys.
+func (c *CapsuleCa) SetKeyEncoding(encoding KeyEncoding) {
+	c.keyEncoding = encoding
+}
+
+// SetClock sets the clock used to validate the certificates and to issue the new ones.
+func (c *CapsuleCa) SetClock(clock Clock) {
+	c.clock = clock
 }
 
 func (c CapsuleCa) ValidateCert(certificate *x509.Certificate) (err error) {
 	pool := x509.NewCertPool()
 	pool.AddCert(c.ca)
+	for _, i := range c.chain {
+		pool.AddCert(i)
+	}
 
 	_, err = certificate.Verify(x509.VerifyOptions{
 		Roots:       pool,
-		CurrentTime: time.Time{},
+		CurrentTime: c.now(),
 	})
 	return
 }
 
+// CheckSignature verifies the certificate has been signed by the CA, regardless of its validity.
+func (c CapsuleCa) CheckSignature(certificate *x509.Certificate) error {
+	return certificate.CheckSignatureFrom(c.ca)
+}
+
 func (c CapsuleCa) isAlreadyValid(now time.Time) bool {
 	return now.After(c.ca.NotBefore)
 }
@@ -69,88 +102,217 @@ func (c CapsuleCa) ExpiresIn(now time.Time) (time.Duration, error) {
 	return time.Duration(c.ca.NotAfter.Unix()-now.Unix()) * time.Second, nil
 }
 
-func (c CapsuleCa) CaCertificatePem() (b *bytes.Buffer, err error) {
-	var crtBytes []byte
-	crtBytes, err = x509.CreateCertificate(rand.Reader, c.ca, c.ca, &c.privateKey.PublicKey, c.privateKey)
-	if err != nil {
-		return
+func (c CapsuleCa) RenewIn(now time.Time, renewBefore uint) time.Duration {
+	return RenewIn(c.ca, now, renewBefore)
+}
+
+// HasSans returns true if the certificate Subject Alternative Names are exactly the requested ones.
+func HasSans(certificate *x509.Certificate, opts CertificateOptions) bool {
+	dnsNames := make(map[string]struct{})
+	for _, n := range opts.DnsNames() {
+		dnsNames[n] = struct{}{}
+	}
+	ipAddresses := make(map[string]struct{})
+	for _, ip := range opts.IpAddresses() {
+		ipAddresses[ip.String()] = struct{}{}
+	}
+
+	current := make(map[string]struct{})
+	for _, n := range certificate.DNSNames {
+		if _, ok := dnsNames[n]; !ok {
+			return false
+		}
+		current[n] = struct{}{}
+	}
+	if len(current) != len(dnsNames) {
+		return false
 	}
+
+	current = make(map[string]struct{})
+	for _, ip := range certificate.IPAddresses {
+		if _, ok := ipAddresses[ip.String()]; !ok {
+			return false
+		}
+		current[ip.String()] = struct{}{}
+	}
+	return len(current) == len(ipAddresses)
+}
+
+// RenewIn returns the duration left before the certificate enters its renewal window, that is the last renewBefore
+// percentage of its lifetime: a zero or negative duration means the certificate must be renewed.
+func RenewIn(certificate *x509.Certificate, now time.Time, renewBefore uint) time.Duration {
+	lifetime := certificate.NotAfter.Sub(certificate.NotBefore)
+	renewAt := certificate.NotAfter.Add(-lifetime / 100 * time.Duration(renewBefore))
+	return renewAt.Sub(now)
+}
+
+// CaCertificatePem returns the CA certificate followed by the whole chain, if any, to be used as CA bundle.
+func (c CapsuleCa) CaCertificatePem() (b *bytes.Buffer, err error) {
 	b = new(bytes.Buffer)
-	err = pem.Encode(b, &pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: crtBytes,
-	})
+	for _, crt := range append([]*x509.Certificate{c.ca}, c.chain...) {
+		err = pem.Encode(b, &pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: crt.Raw,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
 	return b, err
 }
 
 func (c CapsuleCa) CaPrivateKeyPem() (b *bytes.Buffer, err error) {
-	b = new(bytes.Buffer)
-	return b, pem.Encode(b, &pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(c.privateKey),
-	})
+	return encodePrivateKeyPem(c.privateKey, c.keyEncoding)
+}
+
+// serialNumberLimit bounds the random serial numbers of the generated certificates to 128 bits.
+var serialNumberLimit = new(big.Int).Lsh(big.NewInt(1), 128)
+
+// generateSerialNumber returns a random serial number, so the rotated certificates can be told apart.
+func generateSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, serialNumberLimit)
 }
 
 func GenerateCertificateAuthority() (s *CapsuleCa, err error) {
-	s = &CapsuleCa{
-		ca: &x509.Certificate{
-			SerialNumber: big.NewInt(2019),
-			Subject: pkix.Name{
-				Organization:  []string{"Clastix"},
-				Country:       []string{"UK"},
-				Province:      []string{""},
-				Locality:      []string{"London"},
-				StreetAddress: []string{"27, Old Gloucester Street"},
-				PostalCode:    []string{"WC1N 3AX"},
-			},
-			NotBefore:             time.Now(),
-			NotAfter:              time.Now().AddDate(10, 0, 0),
-			IsCA:                  true,
-			ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
-			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
-			BasicConstraintsValid: true,
+	return GenerateCertificateAuthorityWithOptions(NewCaOpts(RSAKeyType, DefaultRsaKeySize, DefaultCaValidity))
+}
+
+// GenerateCertificateAuthorityWithOptions creates a new self-signed CA: the certificate is signed once upon
+// generation, so the PEM representation is stable across calls also with non-deterministic signature algorithms
+// as ECDSA.
+func GenerateCertificateAuthorityWithOptions(opts CaOptions) (s *CapsuleCa, err error) {
+	if opts.Validity() <= 0 {
+		return nil, InvalidValidityError{validity: opts.Validity()}
+	}
+
+	var key crypto.Signer
+	if key, err = generatePrivateKey(opts.KeyType(), opts.RsaKeySize()); err != nil {
+		return nil, err
+	}
+
+	clock := opts.Clock()
+	if clock == nil {
+		clock = RealClock{}
+	}
+	now := clock.Now()
+
+	var serialNumber *big.Int
+	if serialNumber, err = generateSerialNumber(); err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization:  []string{"Clastix"},
+			Country:       []string{"UK"},
+			Province:      []string{""},
+			Locality:      []string{"London"},
+			StreetAddress: []string{"27, Old Gloucester Street"},
+			PostalCode:    []string{"WC1N 3AX"},
 		},
+		NotBefore:             now,
+		NotAfter:              now.Add(opts.Validity()),
+		IsCA:                  true,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
 	}
 
-	s.privateKey, err = rsa.GenerateKey(rand.Reader, 4096)
+	var crtBytes []byte
+	crtBytes, err = x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
 	if err != nil {
 		return nil, err
 	}
 
+	s = &CapsuleCa{privateKey: key, clock: clock}
+	if s.ca, err = x509.ParseCertificate(crtBytes); err != nil {
+		return nil, err
+	}
+
 	return
 }
 
 func NewCertificateAuthorityFromBytes(certBytes, keyBytes []byte) (s *CapsuleCa, err error) {
 	var b *pem.Block
 
-	b, _ = pem.Decode(certBytes)
-	var cert *x509.Certificate
-	if cert, err = x509.ParseCertificate(b.Bytes); err != nil {
-		return
+	// The PEM could contain a chain, the first certificate being the signing CA followed by its issuers
+	var certs []*x509.Certificate
+	for rest := certBytes; ; {
+		if b, rest = pem.Decode(rest); b == nil {
+			break
+		}
+		var c *x509.Certificate
+		if c, err = x509.ParseCertificate(b.Bytes); err != nil {
+			return nil, InvalidCaError{reason: err.Error()}
+		}
+		if !c.IsCA {
+			return nil, InvalidCaError{reason: "the certificate is not a CA"}
+		}
+		if n := len(certs); n > 0 {
+			if err = certs[n-1].CheckSignatureFrom(c); err != nil {
+				return nil, InvalidCaError{reason: "the chain must be ordered from the signing CA up to the root"}
+			}
+		}
+		certs = append(certs, c)
 	}
+	if len(certs) == 0 {
+		return nil, InvalidCaError{reason: "cannot decode the certificate PEM"}
+	}
+	cert := certs[0]
 
-	b, _ = pem.Decode(keyBytes)
-	var key *rsa.PrivateKey
-	if key, err = x509.ParsePKCS1PrivateKey(b.Bytes); err != nil {
-		return
+	if b, _ = pem.Decode(keyBytes); b == nil {
+		return nil, InvalidCaError{reason: "cannot decode the private key PEM"}
+	}
+	var key crypto.Signer
+	if key, err = decodePrivateKeyPem(b); err != nil {
+		return nil, InvalidCaError{reason: err.Error()}
+	}
+	if !isKeyPair(cert, key) {
+		return nil, InvalidCaError{reason: "the private key doesn't match the certificate public key"}
 	}
 
 	s = &CapsuleCa{
-		ca:         cert,
-		privateKey: key,
+		ca:          cert,
+		chain:       certs[1:],
+		privateKey:  key,
+		keyEncoding: keyEncodingOf(b),
 	}
 
 	return
 }
 
+func isKeyPair(cert *x509.Certificate, key crypto.Signer) bool {
+	c, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return false
+	}
+	k, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(c, k)
+}
+
 func (c *CapsuleCa) GenerateCertificate(opts CertificateOptions) (certificatePem *bytes.Buffer, certificateKey *bytes.Buffer, err error) {
-	certPrivKey, err := rsa.GenerateKey(rand.Reader, 4096)
+	keyType, err := keyTypeOf(c.privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The issued certificate key has the same algorithm and size of the CA one
+	certPrivKey, err := generatePrivateKey(keyType, rsaKeySizeOf(c.privateKey))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serialNumber, err := generateSerialNumber()
 	if err != nil {
 		return nil, nil, err
 	}
 
 	cert := &x509.Certificate{
-		SerialNumber: big.NewInt(1658),
+		SerialNumber: serialNumber,
 		Subject: pkix.Name{
 			Organization:  []string{"Clastix"},
 			Country:       []string{"UK"},
@@ -160,14 +322,15 @@ func (c *CapsuleCa) GenerateCertificate(opts CertificateOptions) (certificatePem
 			PostalCode:    []string{"WC1N 3AX"},
 		},
 		DNSNames:     opts.DnsNames(),
-		NotBefore:    time.Now().AddDate(0, 0, -1),
+		IPAddresses:  opts.IpAddresses(),
+		NotBefore:    c.now().AddDate(0, 0, -1),
 		NotAfter:     opts.ExpirationDate(),
 		SubjectKeyId: []byte{1, 2, 3, 4, 6},
 		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
 		KeyUsage:     x509.KeyUsageDigitalSignature,
 	}
 
-	certBytes, err := x509.CreateCertificate(rand.Reader, cert, c.ca, &certPrivKey.PublicKey, c.privateKey)
+	certBytes, err := x509.CreateCertificate(rand.Reader, cert, c.ca, certPrivKey.Public(), c.privateKey)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -181,11 +344,7 @@ func (c *CapsuleCa) GenerateCertificate(opts CertificateOptions) (certificatePem
 		return
 	}
 
-	certificateKey = new(bytes.Buffer)
-	err = pem.Encode(certificateKey, &pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(certPrivKey),
-	})
+	certificateKey, err = encodePrivateKeyPem(certPrivKey, c.keyEncoding)
 	if err != nil {
 		return
 	}
@@ -2,9 +2,13 @@ package cert
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
+	"math/big"
 	"testing"
 	"time"
 
@@ -30,6 +34,136 @@ func TestNewCertificateAuthorityFromBytes(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestNewCertificateAuthorityFromBytes_Corrupted(t *testing.T) {
+	ca, err := GenerateCertificateAuthority()
+	assert.Nil(t, err)
+
+	var crt, key *bytes.Buffer
+	crt, err = ca.CaCertificatePem()
+	assert.Nil(t, err)
+	key, err = ca.CaPrivateKeyPem()
+	assert.Nil(t, err)
+
+	var other *CapsuleCa
+	other, err = GenerateCertificateAuthority()
+	assert.Nil(t, err)
+	var otherKey *bytes.Buffer
+	otherKey, err = other.CaPrivateKeyPem()
+	assert.Nil(t, err)
+
+	var leafCrt, leafKey *bytes.Buffer
+	leafCrt, leafKey, err = ca.GenerateCertificate(NewCertOpts(time.Now().AddDate(1, 0, 0), "foo.tld"))
+	assert.Nil(t, err)
+
+	type testCase struct {
+		crt []byte
+		key []byte
+	}
+	for name, c := range map[string]testCase{
+		"empty":             {nil, nil},
+		"truncated cert":    {crt.Bytes()[:crt.Len()/2], key.Bytes()},
+		"garbage cert":      {pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")}), key.Bytes()},
+		"truncated key":     {crt.Bytes(), key.Bytes()[:key.Len()/2]},
+		"mismatched key":    {crt.Bytes(), otherKey.Bytes()},
+		"not a CA":          {leafCrt.Bytes(), leafKey.Bytes()},
+		"unsupported block": {crt.Bytes(), pem.EncodeToMemory(&pem.Block{Type: "DSA PRIVATE KEY", Bytes: []byte("key")})},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewCertificateAuthorityFromBytes(c.crt, c.key)
+			assert.IsType(t, InvalidCaError{}, err)
+		})
+	}
+}
+
+func TestGenerateCertificateAuthorityWithOptions(t *testing.T) {
+	type testCase struct {
+		keyType   KeyType
+		blockType string
+	}
+	for name, c := range map[string]testCase{
+		"rsa":   {RSAKeyType, "RSA PRIVATE KEY"},
+		"ecdsa": {ECDSAKeyType, "EC PRIVATE KEY"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ca, err := GenerateCertificateAuthorityWithOptions(NewCaOpts(c.keyType, DefaultRsaKeySize, DefaultCaValidity))
+			assert.Nil(t, err)
+
+			var crt *bytes.Buffer
+			crt, err = ca.CaCertificatePem()
+			assert.Nil(t, err)
+
+			var key *bytes.Buffer
+			key, err = ca.CaPrivateKeyPem()
+			assert.Nil(t, err)
+
+			b, _ := pem.Decode(key.Bytes())
+			assert.Equal(t, c.blockType, b.Type)
+
+			// the CA PEM must be stable across calls, otherwise the CA Secret would be updated upon each reconciliation
+			var again *bytes.Buffer
+			again, err = ca.CaCertificatePem()
+			assert.Nil(t, err)
+			assert.Equal(t, crt.Bytes(), again.Bytes())
+
+			var loaded *CapsuleCa
+			loaded, err = NewCertificateAuthorityFromBytes(crt.Bytes(), key.Bytes())
+			assert.Nil(t, err)
+
+			var leafCrt, leafKey *bytes.Buffer
+			leafCrt, leafKey, err = loaded.GenerateCertificate(NewCertOpts(time.Now().AddDate(1, 0, 0), "foo.tld"))
+			assert.Nil(t, err)
+
+			b, _ = pem.Decode(leafKey.Bytes())
+			assert.Equal(t, c.blockType, b.Type)
+
+			_, err = tls.X509KeyPair(leafCrt.Bytes(), leafKey.Bytes())
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestCapsuleCa_SetKeyEncoding(t *testing.T) {
+	for name, keyType := range map[string]KeyType{
+		"rsa":   RSAKeyType,
+		"ecdsa": ECDSAKeyType,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ca, err := GenerateCertificateAuthorityWithOptions(NewCaOpts(keyType, DefaultRsaKeySize, DefaultCaValidity))
+			assert.Nil(t, err)
+			ca.SetKeyEncoding(PKCS8KeyEncoding)
+
+			var crt, key *bytes.Buffer
+			crt, err = ca.CaCertificatePem()
+			assert.Nil(t, err)
+			key, err = ca.CaPrivateKeyPem()
+			assert.Nil(t, err)
+
+			b, _ := pem.Decode(key.Bytes())
+			assert.Equal(t, "PRIVATE KEY", b.Type)
+
+			// the encoding of the loaded CA is preserved for the issued certificates
+			var loaded *CapsuleCa
+			loaded, err = NewCertificateAuthorityFromBytes(crt.Bytes(), key.Bytes())
+			assert.Nil(t, err)
+
+			var leafCrt, leafKey *bytes.Buffer
+			leafCrt, leafKey, err = loaded.GenerateCertificate(NewCertOpts(time.Now().AddDate(1, 0, 0), "foo.tld"))
+			assert.Nil(t, err)
+
+			b, _ = pem.Decode(leafKey.Bytes())
+			assert.Equal(t, "PRIVATE KEY", b.Type)
+
+			_, err = tls.X509KeyPair(leafCrt.Bytes(), leafKey.Bytes())
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestGenerateCertificateAuthorityWithOptions_UnsupportedKeyType(t *testing.T) {
+	_, err := GenerateCertificateAuthorityWithOptions(NewCaOpts("dsa", DefaultRsaKeySize, DefaultCaValidity))
+	assert.Error(t, err)
+}
+
 func TestCapsuleCa_GenerateCertificate(t *testing.T) {
 	type testCase struct {
 		dnsNames []string
@@ -103,3 +237,286 @@ func TestCapsuleCa_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateCertificateAuthorityWithOptions_Validity(t *testing.T) {
+	type testCase struct {
+		validity    time.Duration
+		returnError bool
+	}
+	for name, c := range map[string]testCase{
+		"short":    {30 * 24 * time.Hour, false},
+		"default":  {DefaultCaValidity, false},
+		"zero":     {0, true},
+		"negative": {-time.Hour, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ca, err := GenerateCertificateAuthorityWithOptions(NewCaOpts(ECDSAKeyType, DefaultRsaKeySize, c.validity))
+			if c.returnError {
+				assert.Error(t, err)
+				return
+			}
+			assert.Nil(t, err)
+
+			var w time.Duration
+			w, err = ca.ExpiresIn(time.Now())
+			assert.Nil(t, err)
+			assert.WithinDuration(t, time.Now().Add(c.validity), time.Now().Add(w), time.Minute)
+		})
+	}
+}
+
+func TestCapsuleCa_RenewIn(t *testing.T) {
+	type testCase struct {
+		notBefore   time.Time
+		notAfter    time.Time
+		renewBefore uint
+		shouldRenew bool
+	}
+	now := time.Now()
+	tc := map[string]testCase{
+		"fresh":             {now.AddDate(0, 0, -1), now.AddDate(0, 0, 99), 20, false},
+		"renewal window":    {now.AddDate(0, 0, -90), now.AddDate(0, 0, 10), 20, true},
+		"expired":           {now.AddDate(0, 0, -100), now.AddDate(0, 0, -1), 20, true},
+		"renew upon expiry": {now.AddDate(0, 0, -90), now.AddDate(0, 0, 10), 0, false},
+	}
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ca, err := GenerateCertificateAuthority()
+			assert.Nil(t, err)
+
+			ca.ca.NotBefore = c.notBefore
+			ca.ca.NotAfter = c.notAfter
+
+			w := ca.RenewIn(now, c.renewBefore)
+			assert.Equal(t, c.shouldRenew, w <= 0)
+
+			lifetime := c.notAfter.Sub(c.notBefore)
+			assert.WithinDuration(t, c.notAfter.Add(-lifetime*time.Duration(c.renewBefore)/100), now.Add(w), time.Second)
+		})
+	}
+}
+
+func TestHasSans(t *testing.T) {
+	ca, err := GenerateCertificateAuthority()
+	assert.Nil(t, err)
+
+	var crt *bytes.Buffer
+	crt, _, err = ca.GenerateCertificate(NewCertOpts(time.Now().AddDate(1, 0, 0), "foo.tld", "bar.tld", "10.0.0.1"))
+	assert.Nil(t, err)
+
+	b, _ := pem.Decode(crt.Bytes())
+	var c *x509.Certificate
+	c, err = x509.ParseCertificate(b.Bytes)
+	assert.Nil(t, err)
+
+	type testCase struct {
+		sans     []string
+		expected bool
+	}
+	for name, tc := range map[string]testCase{
+		"same":        {[]string{"bar.tld", "10.0.0.1", "foo.tld"}, true},
+		"missing dns": {[]string{"foo.tld", "10.0.0.1"}, false},
+		"extra dns":   {[]string{"foo.tld", "bar.tld", "baz.tld", "10.0.0.1"}, false},
+		"missing ip":  {[]string{"foo.tld", "bar.tld"}, false},
+		"extra ip":    {[]string{"foo.tld", "bar.tld", "10.0.0.1", "10.0.0.2"}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, HasSans(c, NewCertOpts(time.Now(), tc.sans...)))
+		})
+	}
+}
+
+func TestCapsuleCa_CheckSignature(t *testing.T) {
+	ca, err := GenerateCertificateAuthority()
+	assert.Nil(t, err)
+
+	var other *CapsuleCa
+	other, err = GenerateCertificateAuthorityWithOptions(NewCaOpts(ECDSAKeyType, DefaultRsaKeySize, DefaultCaValidity))
+	assert.Nil(t, err)
+
+	var crt *bytes.Buffer
+	crt, _, err = ca.GenerateCertificate(NewCertOpts(time.Now().AddDate(1, 0, 0), "foo.tld"))
+	assert.Nil(t, err)
+
+	b, _ := pem.Decode(crt.Bytes())
+	var c *x509.Certificate
+	c, err = x509.ParseCertificate(b.Bytes)
+	assert.Nil(t, err)
+
+	assert.Nil(t, ca.CheckSignature(c))
+	assert.Error(t, other.CheckSignature(c))
+}
+
+func TestNewCertificateAuthorityFromBytes_Chain(t *testing.T) {
+	root, err := GenerateCertificateAuthority()
+	assert.Nil(t, err)
+
+	var key *bytes.Buffer
+	var intermediate *CapsuleCa
+	intermediate, err = GenerateCertificateAuthorityWithOptions(NewCaOpts(ECDSAKeyType, DefaultRsaKeySize, DefaultCaValidity))
+	assert.Nil(t, err)
+	key, err = intermediate.CaPrivateKeyPem()
+	assert.Nil(t, err)
+
+	// re-signing the intermediate CA with the root one
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(2020),
+		Subject:               pkix.Name{CommonName: "intermediate"},
+		NotBefore:             intermediate.ca.NotBefore,
+		NotAfter:              intermediate.ca.NotAfter,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	var der []byte
+	der, err = x509.CreateCertificate(rand.Reader, template, root.ca, intermediate.privateKey.Public(), root.privateKey)
+	assert.Nil(t, err)
+
+	intermediatePem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	rootPem, err := root.CaCertificatePem()
+	assert.Nil(t, err)
+
+	chain := append(append([]byte{}, intermediatePem...), rootPem.Bytes()...)
+
+	var ca *CapsuleCa
+	ca, err = NewCertificateAuthorityFromBytes(chain, key.Bytes())
+	assert.Nil(t, err)
+
+	var bundle *bytes.Buffer
+	bundle, err = ca.CaCertificatePem()
+	assert.Nil(t, err)
+	assert.Equal(t, chain, bundle.Bytes())
+
+	var leafCrt *bytes.Buffer
+	leafCrt, _, err = ca.GenerateCertificate(NewCertOpts(time.Now().AddDate(1, 0, 0), "foo.tld"))
+	assert.Nil(t, err)
+
+	b, _ := pem.Decode(leafCrt.Bytes())
+	var c *x509.Certificate
+	c, err = x509.ParseCertificate(b.Bytes)
+	assert.Nil(t, err)
+	assert.Nil(t, ca.ValidateCert(c))
+
+	// the signing CA must come first
+	reversed := append(append([]byte{}, rootPem.Bytes()...), intermediatePem...)
+	_, err = NewCertificateAuthorityFromBytes(reversed, key.Bytes())
+	assert.IsType(t, InvalidCaError{}, err)
+}
+
+func TestGenerateCertificateAuthorityWithOptions_RsaKeySize(t *testing.T) {
+	for name, size := range map[string]int{
+		"2048": 2048,
+		"3072": 3072,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ca, err := GenerateCertificateAuthorityWithOptions(NewCaOpts(RSAKeyType, size, DefaultCaValidity))
+			assert.Nil(t, err)
+			assert.Equal(t, size, rsaKeySizeOf(ca.privateKey))
+
+			var leafKey *bytes.Buffer
+			_, leafKey, err = ca.GenerateCertificate(NewCertOpts(time.Now().AddDate(1, 0, 0), "foo.tld"))
+			assert.Nil(t, err)
+
+			b, _ := pem.Decode(leafKey.Bytes())
+			var k crypto.Signer
+			k, err = decodePrivateKeyPem(b)
+			assert.Nil(t, err)
+			assert.Equal(t, size, rsaKeySizeOf(k))
+		})
+	}
+
+	_, err := GenerateCertificateAuthorityWithOptions(NewCaOpts(RSAKeyType, 1024, DefaultCaValidity))
+	assert.IsType(t, UnsupportedKeySizeError{}, err)
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func TestGenerateCertificateAuthorityWithOptions_Clock(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	type testCase struct {
+		elapsed     time.Duration
+		expired     bool
+		shouldRenew bool
+		requeue     time.Duration
+	}
+	for name, c := range map[string]testCase{
+		"fresh":           {day, false, false, 79 * day},
+		"about to expire": {90 * day, false, true, -10 * day},
+		"expired":         {101 * day, true, true, -21 * day},
+	} {
+		t.Run(name, func(t *testing.T) {
+			clock := &fakeClock{now: t0}
+
+			ca, err := GenerateCertificateAuthorityWithOptions(NewCaOpts(ECDSAKeyType, DefaultRsaKeySize, 100*day).WithClock(clock))
+			assert.Nil(t, err)
+			assert.Equal(t, t0, ca.ca.NotBefore)
+			assert.Equal(t, t0.Add(100*day), ca.ca.NotAfter)
+
+			clock.now = t0.Add(c.elapsed)
+
+			var expiresIn time.Duration
+			expiresIn, err = ca.ExpiresIn(clock.Now())
+			if c.expired {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, 100*day-c.elapsed, expiresIn)
+			}
+
+			rq := ca.RenewIn(clock.Now(), 20)
+			assert.Equal(t, c.shouldRenew, rq <= 0)
+			assert.Equal(t, c.requeue, rq)
+		})
+	}
+}
+
+func TestCapsuleCa_ValidateCert_Clock(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: t0}
+
+	ca, err := GenerateCertificateAuthorityWithOptions(NewCaOpts(ECDSAKeyType, DefaultRsaKeySize, DefaultCaValidity).WithClock(clock))
+	assert.Nil(t, err)
+
+	var crt *bytes.Buffer
+	crt, _, err = ca.GenerateCertificate(NewCertOpts(t0.AddDate(0, 0, 30), "foo.tld"))
+	assert.Nil(t, err)
+
+	b, _ := pem.Decode(crt.Bytes())
+	var c *x509.Certificate
+	c, err = x509.ParseCertificate(b.Bytes)
+	assert.Nil(t, err)
+	assert.Equal(t, t0.AddDate(0, 0, -1), c.NotBefore)
+
+	clock.now = t0.AddDate(0, 0, 10)
+	assert.Nil(t, ca.ValidateCert(c))
+
+	clock.now = t0.AddDate(0, 0, 40)
+	assert.Error(t, ca.ValidateCert(c))
+}
+
+func TestGenerateCertificateAuthorityWithOptions_SerialNumber(t *testing.T) {
+	serials := make(map[string]struct{})
+	for i := 0; i < 2; i++ {
+		ca, err := GenerateCertificateAuthorityWithOptions(NewCaOpts(ECDSAKeyType, 0, DefaultCaValidity))
+		assert.Nil(t, err)
+		assert.True(t, ca.ca.SerialNumber.Sign() > 0)
+		assert.True(t, ca.ca.SerialNumber.Cmp(serialNumberLimit) < 0)
+		serials[ca.ca.SerialNumber.String()] = struct{}{}
+
+		crt, _, err := ca.GenerateCertificate(NewCertOpts(time.Now().AddDate(1, 0, 0), "capsule-webhook-service.capsule-system.svc"))
+		assert.Nil(t, err)
+		b, _ := pem.Decode(crt.Bytes())
+		c, err := x509.ParseCertificate(b.Bytes)
+		assert.Nil(t, err)
+		serials[c.SerialNumber.String()] = struct{}{}
+	}
+	assert.Len(t, serials, 4)
+}
@@ -16,6 +16,11 @@ limitations under the License.
 
 package cert
 
+import (
+	"fmt"
+	"time"
+)
+
 type CaNotYetValidError struct{}
 
 func (CaNotYetValidError) Error() string {
@@ -27,3 +32,35 @@ type CaExpiredError struct{}
 func (CaExpiredError) Error() string {
 	return "The current CA is expired"
 }
+
+type UnsupportedKeyTypeError struct {
+	keyType string
+}
+
+func (u UnsupportedKeyTypeError) Error() string {
+	return fmt.Sprintf("The key type %s is not supported", u.keyType)
+}
+
+type UnsupportedKeySizeError struct {
+	size int
+}
+
+func (u UnsupportedKeySizeError) Error() string {
+	return fmt.Sprintf("The RSA key size %d is not supported, must be one of 2048, 3072 or 4096", u.size)
+}
+
+type InvalidValidityError struct {
+	validity time.Duration
+}
+
+func (i InvalidValidityError) Error() string {
+	return fmt.Sprintf("The certificate validity must be a positive duration, %s provided", i.validity.String())
+}
+
+type InvalidCaError struct {
+	reason string
+}
+
+func (i InvalidCaError) Error() string {
+	return fmt.Sprintf("The CA is invalid: %s", i.reason)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cert
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+type KeyType string
+
+const (
+	RSAKeyType   KeyType = "rsa"
+	ECDSAKeyType KeyType = "ecdsa"
+
+	rsaPrivateKeyBlockType   = "RSA PRIVATE KEY"
+	ecPrivateKeyBlockType    = "EC PRIVATE KEY"
+	pkcs8PrivateKeyBlockType = "PRIVATE KEY"
+)
+
+// KeyEncoding is the format of the PEM encoded private keys: PKCS#1 stands for the algorithm specific one, that is
+// PKCS#1 for RSA and SEC 1 for ECDSA keys.
+type KeyEncoding string
+
+const (
+	PKCS1KeyEncoding KeyEncoding = "pkcs1"
+	PKCS8KeyEncoding KeyEncoding = "pkcs8"
+)
+
+func (k KeyEncoding) String() string {
+	return string(k)
+}
+
+func (k KeyEncoding) IsValid() bool {
+	return k == PKCS1KeyEncoding || k == PKCS8KeyEncoding
+}
+
+func (k KeyType) String() string {
+	return string(k)
+}
+
+func (k KeyType) IsValid() bool {
+	return k == RSAKeyType || k == ECDSAKeyType
+}
+
+// DefaultRsaKeySize is the size in bits of the generated RSA keys.
+const DefaultRsaKeySize = 4096
+
+func IsValidRsaKeySize(size int) bool {
+	return size == 2048 || size == 3072 || size == 4096
+}
+
+func generatePrivateKey(keyType KeyType, rsaKeySize int) (crypto.Signer, error) {
+	switch keyType {
+	case RSAKeyType:
+		if !IsValidRsaKeySize(rsaKeySize) {
+			return nil, UnsupportedKeySizeError{size: rsaKeySize}
+		}
+		return rsa.GenerateKey(rand.Reader, rsaKeySize)
+	case ECDSAKeyType:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return nil, UnsupportedKeyTypeError{keyType: keyType.String()}
+	}
+}
+
+// keyTypeOf returns the KeyType of an already generated private key, used to
+// issue certificates with the same algorithm of the signing CA.
+func keyTypeOf(key crypto.Signer) (KeyType, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return RSAKeyType, nil
+	case *ecdsa.PrivateKey:
+		return ECDSAKeyType, nil
+	default:
+		return "", UnsupportedKeyTypeError{keyType: fmt.Sprintf("%T", k)}
+	}
+}
+
+// rsaKeySizeOf returns the size of the RSA private key, or the default one for other algorithms.
+func rsaKeySizeOf(key crypto.Signer) int {
+	if k, ok := key.(*rsa.PrivateKey); ok {
+		return k.N.BitLen()
+	}
+	return DefaultRsaKeySize
+}
+
+func encodePrivateKeyPem(key crypto.Signer, encoding KeyEncoding) (b *bytes.Buffer, err error) {
+	var block *pem.Block
+
+	if encoding == PKCS8KeyEncoding {
+		var der []byte
+		if der, err = x509.MarshalPKCS8PrivateKey(key); err != nil {
+			return nil, err
+		}
+		b = new(bytes.Buffer)
+		return b, pem.Encode(b, &pem.Block{Type: pkcs8PrivateKeyBlockType, Bytes: der})
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		block = &pem.Block{
+			Type:  rsaPrivateKeyBlockType,
+			Bytes: x509.MarshalPKCS1PrivateKey(k),
+		}
+	case *ecdsa.PrivateKey:
+		var der []byte
+		if der, err = x509.MarshalECPrivateKey(k); err != nil {
+			return nil, err
+		}
+		block = &pem.Block{
+			Type:  ecPrivateKeyBlockType,
+			Bytes: der,
+		}
+	default:
+		return nil, UnsupportedKeyTypeError{keyType: fmt.Sprintf("%T", k)}
+	}
+
+	b = new(bytes.Buffer)
+	return b, pem.Encode(b, block)
+}
+
+func decodePrivateKeyPem(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case rsaPrivateKeyBlockType:
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case ecPrivateKeyBlockType:
+		return x509.ParseECPrivateKey(block.Bytes)
+	case pkcs8PrivateKeyBlockType:
+		k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := k.(crypto.Signer)
+		if !ok {
+			return nil, UnsupportedKeyTypeError{keyType: fmt.Sprintf("%T", k)}
+		}
+		if _, err = keyTypeOf(signer); err != nil {
+			return nil, err
+		}
+		return signer, nil
+	default:
+		return nil, UnsupportedKeyTypeError{keyType: block.Type}
+	}
+}
+
+// keyEncodingOf returns the KeyEncoding of a PEM block, used to preserve the format of an already existing key.
+func keyEncodingOf(block *pem.Block) KeyEncoding {
+	if block.Type == pkcs8PrivateKeyBlockType {
+		return PKCS8KeyEncoding
+	}
+	return PKCS1KeyEncoding
+}
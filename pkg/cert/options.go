@@ -16,15 +16,20 @@ limitations under the License.
 
 package cert
 
-import "time"
+import (
+	"net"
+	"time"
+)
 
 type CertificateOptions interface {
 	DnsNames() []string
+	IpAddresses() []net.IP
 	ExpirationDate() time.Time
 }
 
 type certOpts struct {
 	dnsNames       []string
+	ipAddresses    []net.IP
 	expirationDate time.Time
 }
 
@@ -32,10 +37,71 @@ func (c certOpts) DnsNames() []string {
 	return c.dnsNames
 }
 
+func (c certOpts) IpAddresses() []net.IP {
+	return c.ipAddresses
+}
+
 func (c certOpts) ExpirationDate() time.Time {
 	return c.expirationDate
 }
 
-func NewCertOpts(expirationDate time.Time, dnsNames ...string) *certOpts {
-	return &certOpts{dnsNames: dnsNames, expirationDate: expirationDate}
+// NewCertOpts returns the options of a certificate with the given Subject Alternative Names: the ones that can be
+// parsed as IP are set as IP SANs, the others as DNS ones.
+func NewCertOpts(expirationDate time.Time, sans ...string) *certOpts {
+	c := &certOpts{expirationDate: expirationDate}
+	for _, san := range sans {
+		if ip := net.ParseIP(san); ip != nil {
+			c.ipAddresses = append(c.ipAddresses, ip)
+			continue
+		}
+		c.dnsNames = append(c.dnsNames, san)
+	}
+	return c
+}
+
+const (
+	DefaultCaValidity  = 10 * 365 * 24 * time.Hour
+	DefaultTlsValidity = 6 * 30 * 24 * time.Hour
+	// DefaultRenewBefore is the percentage of the remaining lifetime triggering the certificate renewal.
+	DefaultRenewBefore = 20
+)
+
+type CaOptions interface {
+	KeyType() KeyType
+	RsaKeySize() int
+	Validity() time.Duration
+	Clock() Clock
+}
+
+type caOpts struct {
+	keyType    KeyType
+	rsaKeySize int
+	validity   time.Duration
+	clock      Clock
+}
+
+func (c caOpts) KeyType() KeyType {
+	return c.keyType
+}
+
+func (c caOpts) RsaKeySize() int {
+	return c.rsaKeySize
+}
+
+func (c caOpts) Validity() time.Duration {
+	return c.validity
+}
+
+func (c caOpts) Clock() Clock {
+	return c.clock
+}
+
+// WithClock sets the clock used to compute the certificates validity, mostly useful for testing.
+func (c *caOpts) WithClock(clock Clock) *caOpts {
+	c.clock = clock
+	return c
+}
+
+func NewCaOpts(keyType KeyType, rsaKeySize int, validity time.Duration) *caOpts {
+	return &caOpts{keyType: keyType, rsaKeySize: rsaKeySize, validity: validity, clock: RealClock{}}
 }
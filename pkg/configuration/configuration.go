@@ -0,0 +1,164 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+type contextKey struct{}
+
+// Configuration is a snapshot of the Capsule settings: it must be treated as read-only, since it's shared by all the
+// webhook handlers and controllers loading it.
+type Configuration struct {
+	UserGroups               []string
+	ForceTenantPrefix        bool
+	ProtectedNamespaceRegexp *regexp.Regexp
+	CaValidity               time.Duration
+	TlsValidity              time.Duration
+	RenewBefore              uint
+	BreakGlassGroups         []string
+	WebhookFailurePolicies   map[string]v1alpha1.FailurePolicy
+}
+
+// Store holds the Capsule configuration, computed from the command line options overridden by the default
+// CapsuleConfiguration: the snapshot is swapped atomically, so a webhook request or a reconciliation loading it once
+// is never affected by concurrent changes.
+type Store struct {
+	defaults Configuration
+	current  atomic.Value
+
+	mutex       sync.Mutex
+	subscribers []chan event.GenericEvent
+}
+
+func NewStore(defaults Configuration) *Store {
+	s := &Store{defaults: defaults}
+	s.current.Store(&defaults)
+	return s
+}
+
+// Load returns the current configuration snapshot.
+func (s *Store) Load() *Configuration {
+	return s.current.Load().(*Configuration)
+}
+
+// FromContext returns the snapshot bound to the context by NewContext, loading the current one otherwise: the chained
+// webhook handlers of a request share the same settings.
+func (s *Store) FromContext(ctx context.Context) *Configuration {
+	if c, ok := ctx.Value(contextKey{}).(*Configuration); ok {
+		return c
+	}
+	return s.Load()
+}
+
+// NewContext binds the configuration snapshot to the context.
+func NewContext(ctx context.Context, c *Configuration) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// Apply overrides the command line options with the CapsuleConfiguration spec, swapping the snapshot: an invalid spec
+// is returned as error, keeping the current snapshot.
+func (s *Store) Apply(spec v1alpha1.CapsuleConfigurationSpec) (err error) {
+	c := s.defaults
+	if len(spec.UserGroups) > 0 {
+		c.UserGroups = append([]string{}, spec.UserGroups...)
+	}
+	if spec.ForceTenantPrefix != nil {
+		c.ForceTenantPrefix = *spec.ForceTenantPrefix
+	}
+	if len(spec.ProtectedNamespaceRegexpString) > 0 {
+		if c.ProtectedNamespaceRegexp, err = regexp.Compile(spec.ProtectedNamespaceRegexpString); err != nil {
+			return fmt.Errorf("unable to compile the protected Namespace regex: %w", err)
+		}
+	}
+	if spec.CaValidity != nil {
+		c.CaValidity = spec.CaValidity.Duration
+	}
+	if spec.TlsValidity != nil {
+		c.TlsValidity = spec.TlsValidity.Duration
+	}
+	if spec.RenewBeforePercentage != nil {
+		c.RenewBefore = *spec.RenewBeforePercentage
+	}
+	if len(spec.BreakGlassGroups) > 0 {
+		c.BreakGlassGroups = append([]string{}, spec.BreakGlassGroups...)
+	}
+	if len(spec.WebhookFailurePolicies) > 0 {
+		c.WebhookFailurePolicies = make(map[string]v1alpha1.FailurePolicy, len(spec.WebhookFailurePolicies))
+		for name, policy := range spec.WebhookFailurePolicies {
+			if policy != v1alpha1.FailurePolicyFail && policy != v1alpha1.FailurePolicyIgnore {
+				return fmt.Errorf("unsupported failure policy %s of the webhook %s", policy, name)
+			}
+			c.WebhookFailurePolicies[name] = policy
+		}
+	}
+	if c.CaValidity <= 0 || c.TlsValidity <= 0 {
+		return fmt.Errorf("certificate validity must be a positive duration")
+	}
+	if c.TlsValidity > c.CaValidity {
+		return fmt.Errorf("the TLS validity (%s) cannot be longer than the CA one (%s)", c.TlsValidity, c.CaValidity)
+	}
+	if c.RenewBefore >= 100 {
+		return fmt.Errorf("the renew-before percentage must be lower than 100")
+	}
+	s.swap(&c)
+	return
+}
+
+// Reset restores the command line options, as when the CapsuleConfiguration has been deleted.
+func (s *Store) Reset() {
+	c := s.defaults
+	s.swap(&c)
+}
+
+// Subscribe returns a channel notified upon every configuration change, to be watched by the controllers relying on
+// the settings, as the one binding the Capsule user groups.
+func (s *Store) Subscribe() <-chan event.GenericEvent {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ch := make(chan event.GenericEvent, 1)
+	s.subscribers = append(s.subscribers, ch)
+	return ch
+}
+
+func (s *Store) swap(c *Configuration) {
+	s.current.Store(c)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cfg := &v1alpha1.CapsuleConfiguration{}
+	cfg.SetName(v1alpha1.CapsuleConfigurationName)
+	for _, ch := range s.subscribers {
+		// a pending notification is already triggering the reconciliation with the latest snapshot
+		select {
+		case ch <- event.GenericEvent{Meta: cfg, Object: cfg}:
+		default:
+		}
+	}
+}
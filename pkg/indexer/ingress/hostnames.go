@@ -0,0 +1,59 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Hostname struct {
+}
+
+func (h Hostname) Object() runtime.Object {
+	return &networkingv1beta1.Ingress{}
+}
+
+func (h Hostname) Field() string {
+	return ".spec.hostnames"
+}
+
+func (h Hostname) Func() client.IndexerFunc {
+	return func(object runtime.Object) []string {
+		var res []string
+		i := object.(*networkingv1beta1.Ingress)
+		seen := make(map[string]struct{})
+		add := func(host string) {
+			if _, ok := seen[host]; ok || len(host) == 0 {
+				return
+			}
+			seen[host] = struct{}{}
+			res = append(res, host)
+		}
+		for _, r := range i.Spec.Rules {
+			add(r.Host)
+		}
+		// the TLS hosts are claimed as well, even if not routed
+		for _, t := range i.Spec.TLS {
+			for _, host := range t.Hosts {
+				add(host)
+			}
+		}
+		return res
+	}
+}
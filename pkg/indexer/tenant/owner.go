@@ -38,6 +38,6 @@ func (o OwnerReference) Field() string {
 func (o OwnerReference) Func() client.IndexerFunc {
 	return func(object runtime.Object) []string {
 		tenant := object.(*v1alpha1.Tenant)
-		return []string{utils.GetOwnerWithKind(tenant)}
+		return utils.GetOwnersWithKinds(tenant)
 	}
 }
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"strings"
+)
+
+const (
+	defaultRegistry   = "docker.io"
+	officialNamespace = "library"
+)
+
+// ImageReference is a container image reference split in its registry and its remainder, as repository, tag and
+// digest.
+type ImageReference struct {
+	Registry   string
+	Repository string
+}
+
+// NewImageReference normalizes the image reference: the first path component is the registry only if it looks like
+// a hostname, as done by the container runtimes, otherwise the implicit Docker Hub is assumed.
+func NewImageReference(image string) ImageReference {
+	if HasRegistry(image) {
+		parts := strings.SplitN(image, "/", 2)
+		return ImageReference{Registry: parts[0], Repository: parts[1]}
+	}
+	if !strings.Contains(image, "/") {
+		image = officialNamespace + "/" + image
+	}
+	return ImageReference{Registry: defaultRegistry, Repository: image}
+}
+
+// HasRegistry returns true if the image reference explicitly states its registry.
+func HasRegistry(image string) bool {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 {
+		return false
+	}
+	return strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost"
+}
+
+func (i ImageReference) String() string {
+	return i.Registry + "/" + i.Repository
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+// SyncObjectMetadata applies the additional labels and annotations to the object, tracking the applied keys in its
+// annotations to remove the ones no more requested: returns true if the object metadata has been changed.
+func SyncObjectMetadata(object metav1.Object, metadata v1alpha1.AdditionalMetadata) bool {
+	annotations := copyMap(object.GetAnnotations())
+	labels, appliedLabels := SyncAppliedMetadata(copyMap(object.GetLabels()), metadata.AdditionalLabels, annotations[v1alpha1.AppliedLabelsAnnotation])
+	annotations, appliedAnnotations := SyncAppliedMetadata(annotations, metadata.AdditionalAnnotations, annotations[v1alpha1.AppliedAnnotationsAnnotation])
+	setAppliedKeys(annotations, v1alpha1.AppliedLabelsAnnotation, appliedLabels)
+	setAppliedKeys(annotations, v1alpha1.AppliedAnnotationsAnnotation, appliedAnnotations)
+
+	changed := !reflect.DeepEqual(labels, copyMap(object.GetLabels())) || !reflect.DeepEqual(annotations, copyMap(object.GetAnnotations()))
+	if changed {
+		object.SetLabels(labels)
+		object.SetAnnotations(annotations)
+	}
+	return changed
+}
+
+// SyncAppliedMetadata sets the desired entries into the current labels or annotations, removing the ones previously
+// applied by Capsule and no longer desired: the applied keys are tracked as a comma separated list, so the entries
+// added by the users are left untouched. The updated metadata and the new applied keys are returned.
+func SyncAppliedMetadata(current, desired map[string]string, applied string) (map[string]string, string) {
+	if current == nil {
+		current = make(map[string]string)
+	}
+
+	for _, k := range strings.Split(applied, ",") {
+		if _, ok := desired[k]; !ok && len(k) > 0 {
+			delete(current, k)
+		}
+	}
+
+	keys := make([]string, 0, len(desired))
+	for k, v := range desired {
+		current[k] = v
+		keys = append(keys, k)
+	}
+	// sorting the keys to avoid a different value at each reconciliation
+	sort.Strings(keys)
+
+	return current, strings.Join(keys, ",")
+}
+
+// setAppliedKeys tracks the metadata keys applied by Capsule, dropping the annotation when there's none.
+func setAppliedKeys(annotations map[string]string, annotation, keys string) {
+	if len(keys) == 0 {
+		delete(annotations, annotation)
+		return
+	}
+	annotations[annotation] = keys
+}
+
+func copyMap(in map[string]string) map[string]string {
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
@@ -18,6 +18,9 @@ package utils
 
 import "github.com/clastix/capsule/api/v1alpha1"
 
-func GetOwnerWithKind(tenant *v1alpha1.Tenant) string {
-	return tenant.Spec.Owner.Kind.String() + ":" + tenant.Spec.Owner.Name
+func GetOwnersWithKinds(tenant *v1alpha1.Tenant) (owners []string) {
+	for _, o := range tenant.GetOwners() {
+		owners = append(owners, o.Kind.String()+":"+o.Name)
+	}
+	return
 }
@@ -41,3 +41,14 @@ func (u UserGroupList) IsInCapsuleGroup(capsuleGroup string) (ok bool) {
 	ok = i < u.Len() && u[i] == capsuleGroup
 	return
 }
+
+// IsInCapsuleGroups reports whether the user belongs to at least one of the given groups, as when the Capsule users
+// are mapped by different identity providers.
+func (u UserGroupList) IsInCapsuleGroups(capsuleGroups []string) bool {
+	for _, group := range capsuleGroups {
+		if u.IsInCapsuleGroup(group) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+)
+
+// The audit annotations attached to the admission responses: the API server prefixes them with the webhook name, as
+// ingress.capsule.clastix.io/tenant, so they're not prefixed here.
+const (
+	AuditTenantAnnotation       = "tenant"
+	AuditDenialReasonAnnotation = "denial-reason"
+	AuditMutationAnnotation     = "mutation"
+	AuditBreakGlassAnnotation   = "break-glass"
+)
+
+// requestTenant returns the name of the Tenant the request refers to, if any: the Tenant itself, or the one owning
+// the Namespace of the object.
+func requestTenant(ctx context.Context, c client.Client, req admission.Request) string {
+	var namespace string
+	switch {
+	case req.Kind.Group == v1alpha1.GroupVersion.Group && req.Kind.Kind == "Tenant":
+		return req.Name
+	case req.Kind.Group == "" && req.Kind.Kind == "Namespace":
+		namespace = req.Name
+	default:
+		namespace = req.Namespace
+	}
+	if len(namespace) == 0 || c == nil {
+		return ""
+	}
+
+	tl := &v1alpha1.TenantList{}
+	if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", namespace),
+	}); err != nil || len(tl.Items) == 0 {
+		return ""
+	}
+	return tl.Items[0].GetName()
+}
+
+// withAuditAnnotations records in the API server audit log the Tenant the request refers to, the reason of the denial
+// or the summary of the mutation.
+func withAuditAnnotations(res admission.Response, tenant string) admission.Response {
+	annotations := make(map[string]string)
+	if len(tenant) > 0 {
+		annotations[AuditTenantAnnotation] = tenant
+	}
+	if !res.Allowed {
+		annotations[AuditDenialReasonAnnotation] = denialReason(res)
+	}
+	if len(res.Patches) > 0 {
+		var ops []string
+		for _, p := range res.Patches {
+			ops = append(ops, fmt.Sprintf("%s %s", p.Operation, p.Path))
+		}
+		annotations[AuditMutationAnnotation] = strings.Join(ops, ", ")
+	}
+	if len(annotations) == 0 {
+		return res
+	}
+
+	if res.AuditAnnotations == nil {
+		res.AuditAnnotations = make(map[string]string, len(annotations))
+	}
+	for k, v := range annotations {
+		res.AuditAnnotations[k] = v
+	}
+	return res
+}
+
+// denialReason returns the reason of the denial: the responses built by Denied and Errored carry it along with the
+// message, while the plain denied ones carry their message as reason, falling back to the one matching the status code.
+func denialReason(res admission.Response) string {
+	if res.Result == nil {
+		return string(ReasonForbidden)
+	}
+	if len(res.Result.Message) > 0 && len(res.Result.Reason) > 0 {
+		return string(res.Result.Reason)
+	}
+	return string(reasonForCode(res.Result.Code))
+}
+
+// BreakGlass allows the denied request, recording the reason of the overridden denial in the audit annotations.
+func BreakGlass(res admission.Response) admission.Response {
+	allowed := admission.Allowed("")
+	allowed.AuditAnnotations = map[string]string{
+		AuditBreakGlassAnnotation: denialReason(res),
+	}
+	return allowed
+}
+
+// BreakGlassPatch records in the audit annotations of the allowed patch response the reason of the denial it overrides,
+// as for the break-glass groups acting on behalf of the Tenant owners.
+func BreakGlassPatch(res admission.Response, reason Reason) admission.Response {
+	if !res.Allowed {
+		return res
+	}
+	if res.AuditAnnotations == nil {
+		res.AuditAnnotations = make(map[string]string, 1)
+	}
+	res.AuditAnnotations[AuditBreakGlassAnnotation] = string(reason)
+	return res
+}
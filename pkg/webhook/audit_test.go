@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gomodules.xyz/jsonpatch/v2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestWithAuditAnnotations_Allowed(t *testing.T) {
+	res := withAuditAnnotations(admission.Allowed(""), "oil")
+	assert.Equal(t, map[string]string{AuditTenantAnnotation: "oil"}, res.AuditAnnotations)
+
+	res = withAuditAnnotations(admission.Allowed(""), "")
+	assert.Nil(t, res.AuditAnnotations)
+}
+
+func TestWithAuditAnnotations_Denied(t *testing.T) {
+	type testCase struct {
+		res    admission.Response
+		reason string
+	}
+
+	for _, tc := range []testCase{
+		{admission.Denied("nope"), "Forbidden"},
+		{admission.Errored(http.StatusBadRequest, errors.New("nope")), "BadRequest"},
+		{admission.Errored(http.StatusInternalServerError, errors.New("nope")), "InternalError"},
+	} {
+		res := withAuditAnnotations(tc.res, "oil")
+		assert.Equal(t, "oil", res.AuditAnnotations[AuditTenantAnnotation])
+		assert.Equal(t, tc.reason, res.AuditAnnotations[AuditDenialReasonAnnotation])
+		assert.NotContains(t, res.AuditAnnotations, AuditMutationAnnotation)
+	}
+}
+
+func TestWithAuditAnnotations_Patched(t *testing.T) {
+	res := withAuditAnnotations(admission.Patched("",
+		jsonpatch.JsonPatchOperation{Operation: "add", Path: "/spec/ingressClassName", Value: "nginx"},
+		jsonpatch.JsonPatchOperation{Operation: "replace", Path: "/spec/priorityClassName", Value: "gold"},
+	), "oil")
+	assert.Equal(t, "oil", res.AuditAnnotations[AuditTenantAnnotation])
+	assert.Equal(t, "add /spec/ingressClassName, replace /spec/priorityClassName", res.AuditAnnotations[AuditMutationAnnotation])
+	assert.NotContains(t, res.AuditAnnotations, AuditDenialReasonAnnotation)
+}
+
+func TestBreakGlass(t *testing.T) {
+	res := withAuditAnnotations(BreakGlass(Denied(NewError(ReasonNamespaceQuotaExceeded, "quota exceeded"))), "oil")
+	assert.True(t, res.Allowed)
+	assert.Equal(t, "NamespaceQuotaExceeded", res.AuditAnnotations[AuditBreakGlassAnnotation])
+	assert.Equal(t, "oil", res.AuditAnnotations[AuditTenantAnnotation])
+	assert.NotContains(t, res.AuditAnnotations, AuditDenialReasonAnnotation)
+}
+
+func TestBreakGlassPatch(t *testing.T) {
+	res := withAuditAnnotations(BreakGlassPatch(admission.Patched("",
+		jsonpatch.JsonPatchOperation{Operation: "add", Path: "/metadata/ownerReferences", Value: []string{}},
+	), ReasonTenantNotOwned), "oil")
+	assert.True(t, res.Allowed)
+	assert.Equal(t, "TenantNotOwned", res.AuditAnnotations[AuditBreakGlassAnnotation])
+	assert.Equal(t, "add /metadata/ownerReferences", res.AuditAnnotations[AuditMutationAnnotation])
+
+	res = BreakGlassPatch(Denied(NewError(ReasonTenantTerminating, "terminating")), ReasonTenantNotOwned)
+	assert.False(t, res.Allowed)
+	assert.Nil(t, res.AuditAnnotations)
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cordoning
+
+import (
+	"fmt"
+	"strings"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type tenantCordonedError struct {
+	tenant    string
+	operation string
+	kind      string
+}
+
+func NewTenantCordonedError(tenant, operation, kind string) error {
+	return &tenantCordonedError{tenant: tenant, operation: operation, kind: kind}
+}
+
+func (t tenantCordonedError) Error() string {
+	return fmt.Sprintf("The Tenant %s is cordoned, the %s of %s resources is forbidden: please, reach out the system administrators", t.tenant, strings.ToLower(t.operation), t.kind)
+}
+
+func (tenantCordonedError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonTenantCordoned
+}
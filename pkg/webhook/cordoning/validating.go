@@ -0,0 +1,99 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cordoning
+
+import (
+	"context"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/validating-v1-cordoning,mutating=false,failurePolicy=ignore,sideEffects=None,groups=*,resources=*;*/*,verbs=create;update;delete,versions=*,name=cordoning.tenant.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetName() string {
+	return "Cordoning"
+}
+
+func (w *webhook) GetPath() string {
+	return "/validating-v1-cordoning"
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+type handler struct {
+}
+
+// Handler freezes the namespaced resources of the cordoned Tenants: it must be restricted to the Capsule users, so
+// the cluster administrators and the Capsule ServiceAccount are not affected.
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return h.validate(ctx, c, req)
+	}
+}
+
+func (h *handler) OnDelete(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return h.validate(ctx, c, req)
+	}
+}
+
+func (h *handler) OnUpdate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return h.validate(ctx, c, req)
+	}
+}
+
+func (h *handler) validate(ctx context.Context, c client.Client, req admission.Request) admission.Response {
+	// cluster scoped resources, the Namespace creation is handled by the Namespace quota webhook
+	if len(req.Namespace) == 0 {
+		return admission.Allowed("")
+	}
+
+	tl := &v1alpha1.TenantList{}
+	if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+	}); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
+	}
+
+	for _, tnt := range tl.Items {
+		if tnt.Spec.Cordoned {
+			return capsulewebhook.Denied(NewTenantCordonedError(tnt.GetName(), string(req.Operation), req.Kind.Kind))
+		}
+	}
+	return admission.Allowed("")
+}
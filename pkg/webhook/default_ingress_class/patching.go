@@ -0,0 +1,129 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package default_ingress_class
+
+import (
+	"context"
+	"net/http"
+
+	"gomodules.xyz/jsonpatch/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+const (
+	annotationName = "kubernetes.io/ingress.class"
+)
+
+// +kubebuilder:webhook:path=/mutate-ingress-default-class,mutating=true,failurePolicy=ignore,sideEffects=None,groups=networking.k8s.io;extensions,resources=ingresses,verbs=create,versions=v1beta1;v1,name=default-class.ingress.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+func (w *webhook) GetName() string {
+	return "DefaultIngressClass"
+}
+
+func (w *webhook) GetPath() string {
+	return "/mutate-ingress-default-class"
+}
+
+type handler struct {
+}
+
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+// OnCreate assigns the Tenant default Ingress Class to the Ingresses not specifying one, both as field and legacy
+// annotation: the explicit ones are left untouched, validated afterwards by the Ingress validating webhook.
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		// the Ingress is decoded as unstructured, since the class is set the same way in all the API versions
+		ingress := &unstructured.Unstructured{}
+		if err := decoder.Decode(req, ingress); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		if _, ok := ingress.GetAnnotations()[annotationName]; ok {
+			return admission.Allowed("")
+		}
+		if _, ok, _ := unstructured.NestedString(ingress.Object, "spec", "ingressClassName"); ok {
+			return admission.Allowed("")
+		}
+
+		tl := &v1alpha1.TenantList{}
+		if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+		}); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		if len(tl.Items) == 0 || len(tl.Items[0].Spec.IngressClasses.Default) == 0 {
+			return admission.Allowed("")
+		}
+
+		class := tl.Items[0].Spec.IngressClasses.Default
+		patch := []jsonpatch.JsonPatchOperation{
+			{
+				Operation: "add",
+				Path:      "/spec/ingressClassName",
+				Value:     class,
+			},
+		}
+		if ingress.GetAnnotations() == nil {
+			patch = append(patch, jsonpatch.JsonPatchOperation{
+				Operation: "add",
+				Path:      "/metadata/annotations",
+				Value:     map[string]string{annotationName: class},
+			})
+		} else {
+			patch = append(patch, jsonpatch.JsonPatchOperation{
+				Operation: "add",
+				Path:      "/metadata/annotations/kubernetes.io~1ingress.class", // http://jsonpatch.com/#json-pointer
+				Value:     class,
+			})
+		}
+		return admission.Patched("Assigning the Tenant default Ingress Class", patch...)
+	}
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package default_registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gomodules.xyz/jsonpatch/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/pkg/utils"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/mutate-v1-pod-default-registry,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create;update,versions=v1,name=default-registry.pod.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+func (w *webhook) GetName() string {
+	return "DefaultRegistry"
+}
+
+func (w *webhook) GetPath() string {
+	return "/mutate-v1-pod-default-registry"
+}
+
+type handler struct {
+}
+
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+func (h *handler) OnCreate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return h.prefixImages(ctx, client, decoder, req)
+	}
+}
+
+func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return h.prefixImages(ctx, client, decoder, req)
+	}
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) prefixImages(ctx context.Context, c client.Client, decoder *admission.Decoder, req admission.Request) admission.Response {
+	pod := &corev1.Pod{}
+	if err := decoder.Decode(req, pod); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
+	}
+
+	tl := &v1alpha1.TenantList{}
+	if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+	}); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
+	}
+
+	if len(tl.Items) == 0 || len(tl.Items[0].Spec.ContainerRegistries.DefaultRegistry) == 0 {
+		return admission.Allowed("")
+	}
+
+	spec := tl.Items[0].GetContainerRegistries()
+
+	var patch []jsonpatch.JsonPatchOperation
+	for path, containers := range map[string][]corev1.Container{
+		"/spec/initContainers": pod.Spec.InitContainers,
+		"/spec/containers":     pod.Spec.Containers,
+	} {
+		for i, container := range containers {
+			image, ok := prefixedImage(spec, container.Image)
+			if !ok {
+				continue
+			}
+			patch = append(patch, jsonpatch.JsonPatchOperation{
+				Operation: "replace",
+				Path:      fmt.Sprintf("%s/%d/image", path, i),
+				Value:     image,
+			})
+		}
+	}
+
+	if len(patch) > 0 {
+		return admission.Patched("Prefixing images with the Tenant default registry", patch...)
+	}
+	return admission.Allowed("")
+}
+
+// prefixedImage returns the image prefixed with the default registry, only if it doesn't state a registry and it
+// isn't already allowed by the Tenant as it is.
+func prefixedImage(spec v1alpha1.ContainerRegistriesSpec, image string) (string, bool) {
+	if len(image) == 0 || utils.HasRegistry(image) {
+		return "", false
+	}
+
+	ref := utils.NewImageReference(image)
+	if (len(spec.Allowed) > 0 || len(spec.AllowedRegex) > 0) && spec.IsImageAllowed(ref.Registry, ref.String()) {
+		return "", false
+	}
+
+	return strings.TrimSuffix(spec.DefaultRegistry, "/") + "/" + image, true
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package default_storage_class
+
+import (
+	"context"
+	"net/http"
+
+	"gomodules.xyz/jsonpatch/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/mutate-v1-pvc-default-class,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=persistentvolumeclaims,verbs=create,versions=v1,name=default-class.pvc.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+func (w *webhook) GetName() string {
+	return "DefaultStorageClass"
+}
+
+func (w *webhook) GetPath() string {
+	return "/mutate-v1-pvc-default-class"
+}
+
+type handler struct {
+}
+
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+// OnCreate assigns the Tenant default Storage Class to the PVCs not specifying one: the empty class, used to select the
+// pre-bound PersistentVolumes, is replaced only if requested by the Tenant, while the explicit ones are left untouched.
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := decoder.Decode(req, pvc); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		if sc := pvc.Spec.StorageClassName; sc != nil && len(*sc) > 0 {
+			return admission.Allowed("")
+		}
+
+		tl := &v1alpha1.TenantList{}
+		if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+		}); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		if len(tl.Items) == 0 || len(tl.Items[0].Spec.StorageClasses.Default) == 0 {
+			return admission.Allowed("")
+		}
+
+		spec := tl.Items[0].Spec.StorageClasses
+		operation := "add"
+		if pvc.Spec.StorageClassName != nil {
+			if !spec.DefaultOnEmpty {
+				return admission.Allowed("")
+			}
+			operation = "replace"
+		}
+		return admission.Patched("Assigning the Tenant default Storage Class", jsonpatch.JsonPatchOperation{
+			Operation: operation,
+			Path:      "/spec/storageClassName",
+			Value:     spec.Default,
+		})
+	}
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package default_tenant
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+const (
+	managedByValue = "capsule"
+)
+
+// +kubebuilder:webhook:path=/mutate-v1alpha1-tenant-defaults,mutating=true,failurePolicy=fail,sideEffects=None,groups="capsule.clastix.io",resources=tenants,verbs=create;update,versions=v1alpha1,name=defaults.tenant.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+func (w *webhook) GetName() string {
+	return "DefaultTenant"
+}
+
+func (w *webhook) GetPath() string {
+	return "/mutate-v1alpha1-tenant-defaults"
+}
+
+type handler struct {
+	namespaceQuota uint
+}
+
+func Handler(namespaceQuota uint) capsulewebhook.Handler {
+	return &handler{
+		namespaceQuota: namespaceQuota,
+	}
+}
+
+func (h *handler) OnCreate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return h.patch(decoder, req)
+	}
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return h.patch(decoder, req)
+	}
+}
+
+// patch fills the missing Tenant fields with their defaults before the CRD schema validation: the Tenant is decoded
+// as unstructured, since the typed one cannot tell the missing fields from the zero values.
+func (h *handler) patch(decoder *admission.Decoder, req admission.Request) admission.Response {
+	tnt := &unstructured.Unstructured{}
+	if err := decoder.Decode(req, tnt); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
+	}
+
+	// the owners without a kind are Users
+	if kind, _, _ := unstructured.NestedString(tnt.Object, "spec", "owner", "kind"); len(kind) == 0 {
+		_ = unstructured.SetNestedField(tnt.Object, v1alpha1.Kind("User").String(), "spec", "owner", "kind")
+	}
+	if owners, ok, _ := unstructured.NestedSlice(tnt.Object, "spec", "owners"); ok {
+		for _, o := range owners {
+			if owner, ok := o.(map[string]interface{}); ok {
+				if kind, _ := owner["kind"].(string); len(kind) == 0 {
+					owner["kind"] = v1alpha1.Kind("User").String()
+				}
+			}
+		}
+		_ = unstructured.SetNestedSlice(tnt.Object, owners, "spec", "owners")
+	}
+	// the Namespace quota cannot be zero, the missing one is the cluster-wide default
+	if quota, _, _ := unstructured.NestedInt64(tnt.Object, "spec", "namespaceQuota"); quota == 0 {
+		_ = unstructured.SetNestedField(tnt.Object, int64(h.namespaceQuota), "spec", "namespaceQuota")
+	}
+	// the Namespaces are released upon the Tenant deletion, unless requested otherwise
+	if policy, _, _ := unstructured.NestedString(tnt.Object, "spec", "namespaceDeletionPolicy"); len(policy) == 0 {
+		_ = unstructured.SetNestedField(tnt.Object, string(v1alpha1.NamespaceDeletionPolicyOrphan), "spec", "namespaceDeletionPolicy")
+	}
+	// the hostPath volumes are denied to the new Tenants, while the ones created before their restriction keep them
+	// allowed, unless set otherwise
+	if _, ok, _ := unstructured.NestedBool(tnt.Object, "spec", "podSecurity", "allowHostPath"); !ok {
+		allowHostPath := false
+		if req.Operation == admissionv1beta1.Update {
+			old := &unstructured.Unstructured{}
+			if err := decoder.DecodeRaw(req.OldObject, old); err != nil {
+				return capsulewebhook.Errored(http.StatusBadRequest, err)
+			}
+			var found bool
+			if allowHostPath, found, _ = unstructured.NestedBool(old.Object, "spec", "podSecurity", "allowHostPath"); !found {
+				allowHostPath = true
+			}
+		}
+		_ = unstructured.SetNestedField(tnt.Object, allowHostPath, "spec", "podSecurity", "allowHostPath")
+	}
+	// the deprecated registryClasses are moved to the containerRegistries, unless these are already set
+	if rc, ok, _ := unstructured.NestedMap(tnt.Object, "spec", "registryClasses"); ok {
+		cr, _, _ := unstructured.NestedMap(tnt.Object, "spec", "containerRegistries")
+		if cr == nil {
+			cr = make(map[string]interface{})
+		}
+		allowed, _, _ := unstructured.NestedSlice(cr, "allowed")
+		regex, _, _ := unstructured.NestedString(cr, "allowedRegex")
+		if len(allowed) == 0 && len(regex) == 0 {
+			cr["allowed"], cr["allowedRegex"] = rc["allowed"], rc["allowedRegex"]
+			_ = unstructured.SetNestedMap(tnt.Object, cr, "spec", "containerRegistries")
+		}
+		unstructured.RemoveNestedField(tnt.Object, "spec", "registryClasses")
+	}
+	// the missing sub-specs are normalized to empty ones, with their required fields set to null
+	for field, required := range map[string][]string{
+		"ingressClasses":     {"allowed", "allowedRegex"},
+		"storageClasses":     {"allowed", "allowedRegex"},
+		"namespacesMetadata": {"additionalLabels", "additionalAnnotations"},
+		"additionalMetadata": {"additionalLabels", "additionalAnnotations"},
+		"clusterRoles":       {"allowed", "allowedRegex"},
+	} {
+		spec, _, _ := unstructured.NestedMap(tnt.Object, "spec", field)
+		if spec == nil {
+			spec = make(map[string]interface{})
+		}
+		for _, k := range required {
+			if _, ok := spec[k]; !ok {
+				spec[k] = nil
+			}
+		}
+		_ = unstructured.SetNestedMap(tnt.Object, spec, "spec", field)
+	}
+	if _, ok, _ := unstructured.NestedSlice(tnt.Object, "spec", "limitRanges"); !ok {
+		_ = unstructured.SetNestedSlice(tnt.Object, []interface{}{}, "spec", "limitRanges")
+	}
+
+	labels := tnt.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[v1alpha1.ManagedByLabel] = managedByValue
+	tnt.SetLabels(labels)
+	// the API version the Tenant has been last written with, required by the conversion
+	annotations := tnt.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[v1alpha1.APIVersionAnnotation] = tnt.GetAPIVersion()
+	tnt.SetAnnotations(annotations)
+
+	raw, err := json.Marshal(tnt.Object)
+	if err != nil {
+		return capsulewebhook.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, raw)
+}
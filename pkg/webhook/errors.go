@@ -0,0 +1,153 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Reason is the machine-readable code of a Capsule denial, returned as reason of the admission response status along
+// with the human readable message, so the clients can match and translate it.
+type Reason string
+
+const (
+	ReasonBadRequest    Reason = "BadRequest"
+	ReasonForbidden     Reason = "Forbidden"
+	ReasonInternalError Reason = "InternalError"
+
+	ReasonClusterRoleForbidden        Reason = "ClusterRoleForbidden"
+	ReasonContainerImageNotValid      Reason = "ContainerImageNotValid"
+	ReasonContainerRegistryForbidden  Reason = "ContainerRegistryForbidden"
+	ReasonCSIDriverForbidden          Reason = "CSIDriverForbidden"
+	ReasonExternalIPForbidden         Reason = "ExternalIPForbidden"
+	ReasonHostNamespaceForbidden      Reason = "HostNamespaceForbidden"
+	ReasonHostPathForbidden           Reason = "HostPathForbidden"
+	ReasonHostPortForbidden           Reason = "HostPortForbidden"
+	ReasonImagePullPolicyForbidden    Reason = "ImagePullPolicyForbidden"
+	ReasonIngressClassForbidden       Reason = "IngressClassForbidden"
+	ReasonIngressClassNotValid        Reason = "IngressClassNotValid"
+	ReasonIngressHostnameCollision    Reason = "IngressHostnameCollision"
+	ReasonIngressHostnameForbidden    Reason = "IngressHostnameForbidden"
+	ReasonLoadBalancerDisabled        Reason = "LoadBalancerDisabled"
+	ReasonManagedResourceProtected    Reason = "ManagedResourceProtected"
+	ReasonNamespaceDeletionProtected  Reason = "NamespaceDeletionProtected"
+	ReasonNamespaceLabelProtected     Reason = "NamespaceLabelProtected"
+	ReasonNamespaceMetadataForbidden  Reason = "NamespaceMetadataForbidden"
+	ReasonNamespaceMetadataNotAllowed Reason = "NamespaceMetadataNotAllowed"
+	ReasonNamespaceMetadataProtected  Reason = "NamespaceMetadataProtected"
+	ReasonNamespaceNameProtected      Reason = "NamespaceNameProtected"
+	ReasonNamespacePrefixRequired     Reason = "NamespacePrefixRequired"
+	ReasonNamespaceQuotaExceeded      Reason = "NamespaceQuotaExceeded"
+	ReasonNamespaceQuotaOverride      Reason = "NamespaceQuotaOverride"
+	ReasonNamespaceUpdateForbidden    Reason = "NamespaceUpdateForbidden"
+	ReasonNetworkPolicyProtected      Reason = "NetworkPolicyProtected"
+	ReasonNodePortDisabled            Reason = "NodePortDisabled"
+	ReasonNodeSelectorConflict        Reason = "NodeSelectorConflict"
+	ReasonObjectQuotaExceeded         Reason = "ObjectQuotaExceeded"
+	ReasonPriorityClassForbidden      Reason = "PriorityClassForbidden"
+	ReasonPrivilegeForbidden          Reason = "PrivilegeForbidden"
+	ReasonRoleBindingProtected        Reason = "RoleBindingProtected"
+	ReasonSecretProtected             Reason = "SecretProtected"
+	ReasonServiceTypeForbidden        Reason = "ServiceTypeForbidden"
+	ReasonStorageClassForbidden       Reason = "StorageClassForbidden"
+	ReasonStorageClassNotValid        Reason = "StorageClassNotValid"
+	ReasonStorageClassRequired        Reason = "StorageClassRequired"
+	ReasonTenantCordoned              Reason = "TenantCordoned"
+	ReasonTenantNotAssigned           Reason = "TenantNotAssigned"
+	ReasonTenantNotFound              Reason = "TenantNotFound"
+	ReasonTenantNotOwned              Reason = "TenantNotOwned"
+	ReasonTenantNotValid              Reason = "TenantNotValid"
+	ReasonTenantSelectionRequired     Reason = "TenantSelectionRequired"
+	ReasonTenantTerminating           Reason = "TenantTerminating"
+	ReasonTolerationForbidden         Reason = "TolerationForbidden"
+	ReasonUserResourceForbidden       Reason = "UserResourceForbidden"
+)
+
+// ReasonedError is implemented by the errors carrying their own denial Reason.
+type ReasonedError interface {
+	error
+	Reason() Reason
+}
+
+type reasonedError struct {
+	reason  Reason
+	message string
+}
+
+// NewError returns an error with the given Reason, for the denials not worth a dedicated type.
+func NewError(reason Reason, format string, args ...interface{}) error {
+	return &reasonedError{reason: reason, message: fmt.Sprintf(format, args...)}
+}
+
+func (r reasonedError) Error() string {
+	return r.message
+}
+
+func (r reasonedError) Reason() Reason {
+	return r.reason
+}
+
+// Denied returns the response denying the request because of the given error.
+func Denied(err error) admission.Response {
+	return Errored(http.StatusForbidden, err)
+}
+
+// Errored returns the response rejecting the request with the given status code: the error Reason, or the generic one
+// matching the code, is set as the status reason and cause type, while the error text is the status message.
+func Errored(code int32, err error) admission.Response {
+	reason := reasonForCode(code)
+	var re ReasonedError
+	if errors.As(err, &re) {
+		reason = re.Reason()
+	}
+
+	return admission.Response{
+		AdmissionResponse: admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Code:    code,
+				Reason:  metav1.StatusReason(reason),
+				Message: err.Error(),
+				Details: &metav1.StatusDetails{
+					Causes: []metav1.StatusCause{
+						{
+							Type:    metav1.CauseType(reason),
+							Message: err.Error(),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func reasonForCode(code int32) Reason {
+	switch code {
+	case http.StatusBadRequest:
+		return ReasonBadRequest
+	case http.StatusInternalServerError:
+		return ReasonInternalError
+	default:
+		return ReasonForbidden
+	}
+}
@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestErrored(t *testing.T) {
+	type testCase struct {
+		code   int32
+		err    error
+		reason Reason
+	}
+
+	for _, tc := range []testCase{
+		{http.StatusForbidden, NewError(ReasonNamespaceQuotaExceeded, "Cannot exceed Namespace quota: please, reach out the system administrators"), ReasonNamespaceQuotaExceeded},
+		{http.StatusBadRequest, fmt.Errorf("wrapped: %w", NewError(ReasonStorageClassForbidden, "Storage Class %s is forbidden", "gold")), ReasonStorageClassForbidden},
+		{http.StatusBadRequest, errors.New("cannot decode"), ReasonBadRequest},
+		{http.StatusInternalServerError, errors.New("cannot list"), ReasonInternalError},
+		{http.StatusForbidden, errors.New("denied"), ReasonForbidden},
+	} {
+		res := Errored(tc.code, tc.err)
+		assert.False(t, res.Allowed)
+		assert.Equal(t, tc.code, res.Result.Code)
+		assert.Equal(t, metav1.StatusReason(tc.reason), res.Result.Reason)
+		assert.Equal(t, tc.err.Error(), res.Result.Message)
+		assert.Equal(t, []metav1.StatusCause{{Type: metav1.CauseType(tc.reason), Message: tc.err.Error()}}, res.Result.Details.Causes)
+	}
+}
+
+func TestDenied(t *testing.T) {
+	res := Denied(NewError(ReasonTenantCordoned, "The Tenant %s is cordoned", "oil"))
+	assert.Equal(t, int32(http.StatusForbidden), res.Result.Code)
+	assert.Equal(t, metav1.StatusReason(ReasonTenantCordoned), res.Result.Reason)
+	assert.Equal(t, "The Tenant oil is cordoned", res.Result.Message)
+	assert.Equal(t, string(ReasonTenantCordoned), withAuditAnnotations(res, "oil").AuditAnnotations[AuditDenialReasonAnnotation])
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_pull_policy
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type imagePullPolicyForbidden struct {
+	container string
+	policy    corev1.PullPolicy
+	allowed   []v1alpha1.ImagePullPolicySpec
+}
+
+func NewImagePullPolicyForbidden(container string, policy corev1.PullPolicy, allowed []v1alpha1.ImagePullPolicySpec) error {
+	return &imagePullPolicyForbidden{container: container, policy: policy, allowed: allowed}
+}
+
+func (i imagePullPolicyForbidden) Error() string {
+	var allowed []string
+	for _, p := range i.allowed {
+		allowed = append(allowed, string(p))
+	}
+	return fmt.Sprintf("Container %s image pull policy %s is forbidden for the current Tenant: allowed image pull policies are [%s]", i.container, i.policy, strings.Join(allowed, ", "))
+}
+
+func (imagePullPolicyForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonImagePullPolicyForbidden
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image_pull_policy_rewrite
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"gomodules.xyz/jsonpatch/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/mutate-v1-pod-image-pull-policy,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=image-pull-policy-rewrite.pod.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+func (w *webhook) GetName() string {
+	return "ImagePullPolicyRewrite"
+}
+
+func (w *webhook) GetPath() string {
+	return "/mutate-v1-pod-image-pull-policy"
+}
+
+type handler struct {
+}
+
+// Handler rewrites the image pull policy of the Tenant Pods containers to the only one allowed by the Tenant, when
+// enabled: the Pods not rewritten, as when the webhook is failing, are still denied by the validating one.
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		pod := &corev1.Pod{}
+		if err := decoder.Decode(req, pod); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		tl := &v1alpha1.TenantList{}
+		if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+		}); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		if len(tl.Items) == 0 || !tl.Items[0].Spec.RewriteImagePullPolicy || len(tl.Items[0].Spec.ImagePullPolicies) != 1 {
+			return admission.Allowed("")
+		}
+
+		policy := corev1.PullPolicy(tl.Items[0].Spec.ImagePullPolicies[0])
+
+		var patch []jsonpatch.JsonPatchOperation
+		for path, containers := range map[string][]corev1.Container{
+			"/spec/initContainers": pod.Spec.InitContainers,
+			"/spec/containers":     pod.Spec.Containers,
+		} {
+			for i, container := range containers {
+				if container.ImagePullPolicy == policy {
+					continue
+				}
+				patch = append(patch, jsonpatch.JsonPatchOperation{
+					Operation: "add",
+					Path:      fmt.Sprintf("%s/%d/imagePullPolicy", path, i),
+					Value:     string(policy),
+				})
+			}
+		}
+
+		if len(patch) > 0 {
+			return admission.Patched("Rewriting the image pull policy to the Tenant allowed one", patch...)
+		}
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
@@ -18,18 +18,27 @@ package ingress
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
 )
 
 type ingressClassForbidden struct {
 	ingressClass string
+	spec         v1alpha1.IngressClassesSpec
 }
 
-func NewIngressClassForbidden(ingressClass string) error {
-	return &ingressClassForbidden{ingressClass: ingressClass}
+func NewIngressClassForbidden(ingressClass string, spec v1alpha1.IngressClassesSpec) error {
+	return &ingressClassForbidden{ingressClass: ingressClass, spec: spec}
 }
 
 func (i ingressClassForbidden) Error() string {
-	return fmt.Sprintf("Ingress Class %s is forbidden for the current Tenant", i.ingressClass)
+	return fmt.Sprintf("Ingress Class %s is forbidden for the current Tenant: allowed ones are [%s] or matching the pattern %q", i.ingressClass, strings.Join(i.spec.Allowed, ", "), i.spec.AllowedRegex)
+}
+
+func (ingressClassForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonIngressClassForbidden
 }
 
 type ingressClassNotValid struct{}
@@ -39,5 +48,42 @@ func NewIngressClassNotValid() error {
 }
 
 func (ingressClassNotValid) Error() string {
-	return "A valid Ingress Class must be used"
+	return "A valid Ingress Class must be used, since no default one is available in the cluster"
+}
+
+func (ingressClassNotValid) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonIngressClassNotValid
+}
+
+type ingressHostnameForbidden struct {
+	hostname string
+	spec     v1alpha1.IngressHostnamesSpec
+}
+
+func NewIngressHostnameForbidden(hostname string, spec v1alpha1.IngressHostnamesSpec) error {
+	return &ingressHostnameForbidden{hostname: hostname, spec: spec}
+}
+
+func (i ingressHostnameForbidden) Error() string {
+	return fmt.Sprintf("Hostname %s is forbidden for the current Tenant: allowed ones are [%s] or matching the pattern %q", i.hostname, strings.Join(i.spec.Allowed, ", "), i.spec.AllowedRegex)
+}
+
+func (ingressHostnameForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonIngressHostnameForbidden
+}
+
+type ingressHostnameCollision struct {
+	hostname string
+}
+
+func NewIngressHostnameCollision(hostname string) error {
+	return &ingressHostnameCollision{hostname: hostname}
+}
+
+func (i ingressHostnameCollision) Error() string {
+	return fmt.Sprintf("Hostname %s is already used by an Ingress outside of the current Tenant", i.hostname)
+}
+
+func (ingressHostnameCollision) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonIngressHostnameCollision
 }
@@ -27,7 +27,9 @@ const (
 
 type Ingress interface {
 	IngressClass() *string
+	Name() string
 	Namespace() string
+	Hostnames() []string
 }
 
 type Networking struct {
@@ -46,10 +48,24 @@ func (n Networking) IngressClass() (res *string) {
 	return
 }
 
+func (n Networking) Name() string {
+	return n.GetName()
+}
+
 func (n Networking) Namespace() string {
 	return n.GetNamespace()
 }
 
+func (n Networking) Hostnames() (res []string) {
+	for _, r := range n.Spec.Rules {
+		res = append(res, r.Host)
+	}
+	for _, t := range n.Spec.TLS {
+		res = append(res, t.Hosts...)
+	}
+	return
+}
+
 type Extension struct {
 	*extensionsv1beta1.Ingress
 }
@@ -66,6 +82,20 @@ func (e Extension) IngressClass() (res *string) {
 	return
 }
 
+func (e Extension) Name() string {
+	return e.GetName()
+}
+
 func (e Extension) Namespace() string {
 	return e.GetNamespace()
 }
+
+func (e Extension) Hostnames() (res []string) {
+	for _, r := range e.Spec.Rules {
+		res = append(res, r.Host)
+	}
+	for _, t := range e.Spec.TLS {
+		res = append(res, t.Hosts...)
+	}
+	return
+}
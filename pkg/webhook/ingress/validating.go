@@ -21,10 +21,13 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strings"
 
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -32,7 +35,9 @@ import (
 	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
 )
 
-// +kubebuilder:webhook:path=/validating-ingress,mutating=false,failurePolicy=fail,groups=networking.k8s.io;extensions,resources=ingresses,verbs=create;update,versions=v1beta1,name=ingress.capsule.clastix.io
+const defaultIngressClassAnnotation = "ingressclass.kubernetes.io/is-default-class"
+
+// +kubebuilder:webhook:path=/validating-ingress,mutating=false,failurePolicy=fail,sideEffects=None,groups=networking.k8s.io;extensions,resources=ingresses,verbs=create;update,versions=v1beta1,name=ingress.capsule.clastix.io
 
 type webhook struct {
 	handler capsulewebhook.Handler
@@ -54,31 +59,42 @@ func (w *webhook) GetPath() string {
 	return "/validating-ingress"
 }
 
-type handler struct{}
+type handler struct {
+	denyHostnameCollision bool
+}
 
-func Handler() capsulewebhook.Handler {
-	return &handler{}
+// Handler validates the Tenant Ingresses, including their TLS hosts: when denyHostnameCollision is set, the hostnames
+// already used by Ingresses outside of the Tenant cannot be claimed. Upon update, only the violations introduced by
+// the change are denied, so the Ingresses predating a stricter policy can still be updated.
+func Handler(denyHostnameCollision bool) capsulewebhook.Handler {
+	return &handler{
+		denyHostnameCollision: denyHostnameCollision,
+	}
 }
 
 func (r *handler) OnCreate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) admission.Response {
-		i, err := r.ingressFromRequest(req, decoder)
+		i, err := r.ingressFromRequest(req.Kind, req.Object, decoder)
 		if err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
 		}
 
-		return r.validateIngress(ctx, client, i)
+		return r.validateIngress(ctx, client, i, nil)
 	}
 }
 
 func (r *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) admission.Response {
-		i, err := r.ingressFromRequest(req, decoder)
+		i, err := r.ingressFromRequest(req.Kind, req.Object, decoder)
 		if err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+		old, err := r.ingressFromRequest(req.Kind, req.OldObject, decoder)
+		if err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
 		}
 
-		return r.validateIngress(ctx, client, i)
+		return r.validateIngress(ctx, client, i, old)
 	}
 }
 
@@ -88,53 +104,187 @@ func (r *handler) OnDelete(client client.Client, decoder *admission.Decoder) cap
 	}
 }
 
-func (r *handler) ingressFromRequest(req admission.Request, decoder *admission.Decoder) (ingress Ingress, err error) {
-	switch req.Kind.Group {
-	case "networking":
+// ingressFromRequest decodes the Ingress according to its API version, adapting it to the Ingress interface shared by
+// the validation.
+func (r *handler) ingressFromRequest(kind metav1.GroupVersionKind, raw runtime.RawExtension, decoder *admission.Decoder) (ingress Ingress, err error) {
+	switch {
+	case kind.Group == "networking.k8s.io":
 		n := &networkingv1beta1.Ingress{}
-		if err := decoder.Decode(req, n); err != nil {
+		if err := decoder.DecodeRaw(raw, n); err != nil {
 			return nil, err
 		}
 		ingress = Networking{n}
-	case "extensions":
+	case kind.Group == "extensions":
 		e := &extensionsv1beta1.Ingress{}
-		if err := decoder.Decode(req, e); err != nil {
+		if err := decoder.DecodeRaw(raw, e); err != nil {
 			return nil, err
 		}
 		ingress = Extension{e}
 	default:
-		err = fmt.Errorf("cannot recognize type %s", req.Kind.Group)
+		err = fmt.Errorf("cannot recognize type %s/%s", kind.Group, kind.Version)
 	}
 	return
 }
 
-func (r *handler) validateIngress(ctx context.Context, c client.Client, object Ingress) admission.Response {
+// addedHostnames returns the hostnames of the Ingress missing in the old one, all of them upon creation.
+func addedHostnames(object, old Ingress) (added []string) {
+	if old == nil {
+		return object.Hostnames()
+	}
+	existing := make(map[string]struct{})
+	for _, hostname := range old.Hostnames() {
+		existing[hostname] = struct{}{}
+	}
+	for _, hostname := range object.Hostnames() {
+		if _, ok := existing[hostname]; !ok {
+			added = append(added, hostname)
+		}
+	}
+	return
+}
+
+func sameIngressClass(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// validateIngress checks the Ingress against the Tenant policies: upon update, the Ingress Class is checked only if
+// changed, as the hostnames not already claimed by the old Ingress.
+func (r *handler) validateIngress(ctx context.Context, c client.Client, object, old Ingress) admission.Response {
 	var valid, matched bool
 	ingressClass := object.IngressClass()
+	classChanged := old == nil || !sameIngressClass(old.IngressClass(), ingressClass)
 
-	if ingressClass == nil {
-		return admission.Errored(http.StatusBadRequest, NewIngressClassNotValid())
+	// an Ingress with no class is going to be served by the cluster default Ingress Class, if any
+	if ingressClass == nil && classChanged {
+		class, err := r.defaultIngressClass(ctx, c)
+		if err != nil {
+			return capsulewebhook.Errored(http.StatusInternalServerError, err)
+		}
+		if len(class) == 0 {
+			return capsulewebhook.Errored(http.StatusBadRequest, NewIngressClassNotValid())
+		}
+		ingressClass = &class
 	}
 
 	tl := &v1alpha1.TenantList{}
 	if err := c.List(ctx, tl, client.MatchingFieldsSelector{
 		Selector: fields.OneTermEqualSelector(".status.namespaces", object.Namespace()),
 	}); err != nil {
-		return admission.Errored(http.StatusBadRequest, err)
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
 	}
 
-	if len(tl.Items[0].Spec.IngressClasses.Allowed) > 0 {
-		valid = tl.Items[0].Spec.IngressClasses.Allowed.IsStringInList(*ingressClass)
+	// the Namespace doesn't belong to any Tenant
+	if len(tl.Items) == 0 {
+		return admission.Allowed("")
 	}
 
-	if len(tl.Items[0].Spec.IngressClasses.AllowedRegex) > 0 {
-		matched, _ = regexp.MatchString(tl.Items[0].Spec.IngressClasses.AllowedRegex, *ingressClass)
+	// the Ingress Class is allowed if part of the list or matching the pattern, validated upon the Tenant admission
+	if classChanged {
+		spec := tl.Items[0].Spec.IngressClasses
+		if len(spec.Allowed) > 0 {
+			valid = spec.Allowed.IsStringInList(*ingressClass)
+		}
+
+		if len(spec.AllowedRegex) > 0 {
+			matched, _ = regexp.MatchString(spec.AllowedRegex, *ingressClass)
+		}
+
+		if !valid && !matched {
+			return capsulewebhook.Errored(http.StatusBadRequest, NewIngressClassForbidden(*ingressClass, spec))
+		}
 	}
 
-	if !valid && !matched {
-		return admission.Errored(http.StatusBadRequest, NewIngressClassForbidden(*ingressClass))
+	hostnames := addedHostnames(object, old)
+	if hostname, ok := r.validateHostnames(tl.Items[0].Spec.IngressHostnames, hostnames); !ok {
+		return capsulewebhook.Errored(http.StatusBadRequest, NewIngressHostnameForbidden(hostname, tl.Items[0].Spec.IngressHostnames))
+	}
+
+	if r.denyHostnameCollision {
+		hostname, err := r.collidingHostname(ctx, c, tl.Items[0], object, hostnames)
+		if err != nil {
+			return capsulewebhook.Errored(http.StatusInternalServerError, err)
+		}
+		if len(hostname) > 0 {
+			return capsulewebhook.Errored(http.StatusBadRequest, NewIngressHostnameCollision(hostname))
+		}
 	}
 
 	return admission.Allowed("")
 
 }
+
+// defaultIngressClass returns the cluster default Ingress Class, none if missing or ambiguous, as the API server does
+// when more than one is marked as default.
+func (r *handler) defaultIngressClass(ctx context.Context, c client.Client) (class string, err error) {
+	icl := &networkingv1beta1.IngressClassList{}
+	if err = c.List(ctx, icl); err != nil {
+		return "", err
+	}
+	for _, ic := range icl.Items {
+		if ic.GetAnnotations()[defaultIngressClassAnnotation] != "true" {
+			continue
+		}
+		if len(class) > 0 {
+			return "", nil
+		}
+		class = ic.GetName()
+	}
+	return
+}
+
+// validateHostnames returns the first hostname not allowed for the Tenant, if any: wildcard hostnames must be listed
+// as they are, while the regular ones can match the pattern too.
+func (r *handler) validateHostnames(spec v1alpha1.IngressHostnamesSpec, hostnames []string) (string, bool) {
+	// no restriction on the Tenant hostnames
+	if len(spec.Allowed) == 0 && len(spec.AllowedRegex) == 0 {
+		return "", true
+	}
+
+	for _, hostname := range hostnames {
+		if len(hostname) == 0 {
+			continue
+		}
+		if len(spec.Allowed) > 0 && spec.Allowed.IsStringInList(hostname) {
+			continue
+		}
+		if strings.HasPrefix(hostname, "*") {
+			return hostname, false
+		}
+		if len(spec.AllowedRegex) > 0 {
+			if matched, _ := regexp.MatchString(spec.AllowedRegex, hostname); matched {
+				continue
+			}
+		}
+		return hostname, false
+	}
+	return "", true
+}
+
+// collidingHostname returns the first of the given hostnames already used by another Ingress living in a Namespace
+// outside of the Tenant, if any.
+func (r *handler) collidingHostname(ctx context.Context, c client.Client, tenant v1alpha1.Tenant, object Ingress, hostnames []string) (string, error) {
+	for _, hostname := range hostnames {
+		if len(hostname) == 0 {
+			continue
+		}
+		il := &networkingv1beta1.IngressList{}
+		if err := c.List(ctx, il, client.MatchingFields{".spec.hostnames": hostname}); err != nil {
+			return "", err
+		}
+		for _, i := range il.Items {
+			// the Ingress is being updated keeping the same hostname
+			if i.GetNamespace() == object.Namespace() && i.GetName() == object.Name() {
+				continue
+			}
+			// the hostname can be shared among the Tenant Namespaces
+			if tenant.Status.Namespaces.IsStringInList(i.GetNamespace()) {
+				continue
+			}
+			return hostname, nil
+		}
+	}
+	return "", nil
+}
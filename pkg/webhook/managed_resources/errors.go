@@ -0,0 +1,49 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed_resources
+
+import (
+	"fmt"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type managedResourceError struct {
+	kind      string
+	namespace string
+	name      string
+	tenant    string
+	operation string
+}
+
+func NewManagedResourceError(kind, namespace, name, tenant, operation string) error {
+	return &managedResourceError{
+		kind:      kind,
+		namespace: namespace,
+		name:      name,
+		tenant:    tenant,
+		operation: operation,
+	}
+}
+
+func (m managedResourceError) Error() string {
+	return fmt.Sprintf("The %s %s/%s is managed by the Tenant %s and cannot be %s: please, reach out the system administrators", m.kind, m.namespace, m.name, m.tenant, m.operation)
+}
+
+func (managedResourceError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonManagedResourceProtected
+}
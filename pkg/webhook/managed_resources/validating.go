@@ -0,0 +1,137 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed_resources
+
+import (
+	"context"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/validating-v1-managed-resources,mutating=false,failurePolicy=fail,sideEffects=NoneOnDryRun,groups="",resources=resourcequotas;limitranges,verbs=update;delete,versions=v1,name=managed-resources.capsule.clastix.io
+// The protected additional resources can be of any kind, so the webhook selects them by the protection label: the
+// objectSelector is not supported by the marker, thus set in the selector_patch.yaml kustomize patch.
+// +kubebuilder:webhook:path=/validating-v1-managed-resources,mutating=false,failurePolicy=fail,sideEffects=NoneOnDryRun,groups=*,resources=*,verbs=update;delete,versions=*,name=additional-resources.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+func (w *webhook) GetName() string {
+	return "ManagedResources"
+}
+
+func (w *webhook) GetPath() string {
+	return "/validating-v1-managed-resources"
+}
+
+type handler struct {
+	recorder record.EventRecorder
+}
+
+// Handler denies the changes to the ResourceQuotas and LimitRanges managed by Capsule, and to the protected Tenant
+// additional resources, recording the attempt as an Event on the Tenant.
+func Handler(recorder record.EventRecorder) capsulewebhook.Handler {
+	return &handler{
+		recorder: recorder,
+	}
+}
+
+func (h *handler) OnCreate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return h.generic(ctx, req, client, decoder, "deleted")
+	}
+}
+
+func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return h.generic(ctx, req, client, decoder, "updated")
+	}
+}
+
+func (h *handler) generic(ctx context.Context, req admission.Request, c client.Client, decoder *admission.Decoder, operation string) admission.Response {
+	obj := &unstructured.Unstructured{}
+	// the previous object is provided by the API server for the deletion since Kubernetes v1.15, otherwise it's
+	// retrieved
+	if len(req.OldObject.Raw) > 0 {
+		if err := decoder.DecodeRaw(req.OldObject, obj); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+	} else {
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: req.Kind.Group, Version: req.Kind.Version, Kind: req.Kind.Kind})
+		if err := c.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: req.Name}, obj); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	tenantName, ok := h.managedBy(req.Kind.Kind, obj.GetLabels())
+	if !ok {
+		return admission.Allowed("")
+	}
+
+	// the dry-run requests are denied as well, without recording them
+	if req.DryRun != nil && *req.DryRun {
+		return capsulewebhook.Denied(NewManagedResourceError(req.Kind.Kind, req.Namespace, req.Name, tenantName, operation))
+	}
+	t := &v1alpha1.Tenant{}
+	if err := c.Get(ctx, types.NamespacedName{Name: tenantName}, t); err == nil {
+		h.recorder.Eventf(t, corev1.EventTypeWarning, "ManagedResourceChangeDenied", "The %s %s/%s cannot be %s by %s", req.Kind.Kind, req.Namespace, req.Name, operation, req.UserInfo.Username)
+	}
+	return capsulewebhook.Denied(NewManagedResourceError(req.Kind.Kind, req.Namespace, req.Name, tenantName, operation))
+}
+
+// managedBy returns the Tenant managing the object, if labelled by Capsule with both the Tenant and the type labels,
+// or with the protection label for the additional resources.
+func (h *handler) managedBy(kind string, labels map[string]string) (tenant string, ok bool) {
+	tl, _ := v1alpha1.GetTypeLabel(&v1alpha1.Tenant{})
+	var typeLabel string
+	switch kind {
+	case "ResourceQuota":
+		typeLabel, _ = v1alpha1.GetTypeLabel(&corev1.ResourceQuota{})
+	case "LimitRange":
+		typeLabel, _ = v1alpha1.GetTypeLabel(&corev1.LimitRange{})
+	}
+	if _, ok = labels[typeLabel]; !ok && labels[v1alpha1.ProtectedResourceLabel] != "true" {
+		return
+	}
+	tenant, ok = labels[tl]
+	return
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_labels
+
+import (
+	"fmt"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type protectedLabelError struct {
+	label string
+}
+
+func NewProtectedLabelError(label string) error {
+	return &protectedLabelError{label: label}
+}
+
+func (p protectedLabelError) Error() string {
+	return fmt.Sprintf("The Namespace label %s is managed by Capsule and cannot be changed", p.label)
+}
+
+func (protectedLabelError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonNamespaceLabelProtected
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_labels
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+const capsuleLabelPrefix = "capsule.clastix.io/"
+
+// +kubebuilder:webhook:path=/validating-v1-namespace-labels,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=namespaces,verbs=update,versions=v1,name=labels.namespace.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+func (w *webhook) GetName() string {
+	return "NamespaceLabels"
+}
+
+func (w *webhook) GetPath() string {
+	return "/validating-v1-namespace-labels"
+}
+
+type handler struct {
+	serviceAccount string
+}
+
+// Handler denies any change to the Capsule labels of the Namespaces, as the Tenant one the whole Tenant handling is
+// relying on, regardless of the user: only the Capsule ServiceAccount is allowed, besides the break-glass groups.
+func Handler(namespace, serviceAccount string) capsulewebhook.Handler {
+	return &handler{
+		serviceAccount: fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount),
+	}
+}
+
+func (h *handler) OnCreate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		if req.UserInfo.Username == h.serviceAccount {
+			return admission.Allowed("")
+		}
+
+		ns := &corev1.Namespace{}
+		if err := decoder.Decode(req, ns); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+		old := &corev1.Namespace{}
+		if err := decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		if key, changed := changedCapsuleLabel(old.GetLabels(), ns.GetLabels()); changed {
+			return capsulewebhook.Denied(NewProtectedLabelError(key))
+		}
+		return admission.Allowed("")
+	}
+}
+
+// changedCapsuleLabel returns the first Capsule label added, removed, or changed between the two label sets.
+func changedCapsuleLabel(old, new map[string]string) (string, bool) {
+	var keys []string
+	for _, labels := range []map[string]string{old, new} {
+		for k := range labels {
+			if strings.HasPrefix(k, capsuleLabelPrefix) {
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		ov, ook := old[k]
+		nv, nok := new[k]
+		if ook != nok || ov != nv {
+			return k, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_metadata
+
+import (
+	"fmt"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type protectedMetadataError struct {
+	kind string
+	key  string
+}
+
+func NewProtectedMetadataError(kind, key string) error {
+	return &protectedMetadataError{kind: kind, key: key}
+}
+
+func (p protectedMetadataError) Error() string {
+	return fmt.Sprintf("The Namespace %s %s is managed by Capsule and cannot be changed", p.kind, p.key)
+}
+
+func (protectedMetadataError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonNamespaceMetadataProtected
+}
+
+type forbiddenMetadataError struct {
+	kind   string
+	key    string
+	tenant string
+	rule   string
+}
+
+func NewForbiddenMetadataError(kind, key, tenant, rule string) error {
+	return &forbiddenMetadataError{kind: kind, key: key, tenant: tenant, rule: rule}
+}
+
+func (f forbiddenMetadataError) Error() string {
+	return fmt.Sprintf("The Namespace %s %s is forbidden by the Tenant %s, since %s", f.kind, f.key, f.tenant, f.rule)
+}
+
+func (forbiddenMetadataError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonNamespaceMetadataForbidden
+}
+
+type quotaOverrideError struct {
+	key    string
+	tenant string
+}
+
+func NewQuotaOverrideError(key, tenant string) error {
+	return &quotaOverrideError{key: key, tenant: tenant}
+}
+
+func (q quotaOverrideError) Error() string {
+	return fmt.Sprintf("The Namespace annotation %s can be changed only by the owners of the Tenant %s", q.key, q.tenant)
+}
+
+func (quotaOverrideError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonNamespaceQuotaOverride
+}
+
+type notAllowedMetadataError struct {
+	kind   string
+	key    string
+	tenant string
+}
+
+func NewNotAllowedMetadataError(kind, key, tenant string) error {
+	return &notAllowedMetadataError{kind: kind, key: key, tenant: tenant}
+}
+
+func (n notAllowedMetadataError) Error() string {
+	return fmt.Sprintf("The Namespace %s %s cannot be changed, since it's not allowed by the Tenant %s", n.kind, n.key, n.tenant)
+}
+
+func (notAllowedMetadataError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonNamespaceMetadataNotAllowed
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_metadata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+const (
+	nodeSelectorAnnotation = "scheduler.alpha.kubernetes.io/node-selector"
+	capsuleLabelPrefix     = "capsule.clastix.io/"
+	lastAppliedAnnotation  = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// +kubebuilder:webhook:path=/validating-v1-namespace-metadata,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=namespaces,verbs=create;update,versions=v1,name=metadata.namespace.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+func (w *webhook) GetName() string {
+	return "NamespaceMetadata"
+}
+
+func (w *webhook) GetPath() string {
+	return "/validating-v1-namespace-metadata"
+}
+
+type handler struct {
+	serviceAccount string
+}
+
+// Handler protects the Namespace node selector annotation, the ones tracking the applied metadata, the Tenant
+// assignment one, and the owner references, allowing their changes only to the Capsule ServiceAccount: the Capsule
+// labels are protected by the namespace_labels webhook, although the exclusion one cannot be set upon creation either.
+// The metadata forbidden by the Tenant is denied both upon creation and update, as the quota override annotations to
+// the users other than the Tenant owners. When the Tenant allows some labels or annotations, the updates are restricted
+// to them.
+func Handler(namespace, serviceAccount string) capsulewebhook.Handler {
+	return &handler{
+		serviceAccount: fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount),
+	}
+}
+
+func (h *handler) OnCreate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		ns := &corev1.Namespace{}
+		if err := decoder.Decode(req, ns); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+		// the excluded Namespaces are skipped by the webhooks, so the Capsule users cannot opt-out
+		if _, ok := ns.GetLabels()[v1alpha1.ExcludedNamespaceLabel]; ok {
+			return capsulewebhook.Denied(NewProtectedMetadataError("label", v1alpha1.ExcludedNamespaceLabel))
+		}
+
+		return h.validateForbidden(ctx, client, req, ns, &corev1.Namespace{})
+	}
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		if req.UserInfo.Username == h.serviceAccount {
+			return admission.Allowed("")
+		}
+
+		ns := &corev1.Namespace{}
+		if err := decoder.Decode(req, ns); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+		old := &corev1.Namespace{}
+		if err := decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		for _, annotation := range []string{nodeSelectorAnnotation, v1alpha1.AppliedLabelsAnnotation, v1alpha1.AppliedAnnotationsAnnotation, v1alpha1.AppliedResourcesAnnotation, v1alpha1.TenantAssignmentAnnotation} {
+			if ns.GetAnnotations()[annotation] != old.GetAnnotations()[annotation] {
+				return capsulewebhook.Denied(NewProtectedMetadataError("annotation", annotation))
+			}
+		}
+		if !equality.Semantic.DeepEqual(ns.GetOwnerReferences(), old.GetOwnerReferences()) {
+			return capsulewebhook.Denied(NewProtectedMetadataError("field", "ownerReferences"))
+		}
+
+		return h.validateForbidden(ctx, client, req, ns, old)
+	}
+}
+
+// validateForbidden denies the labels and annotations forbidden by the Tenant namespaceOptions, if added or changed:
+// the ones already set, as by the cluster administrators, are not preventing other changes. The quota override
+// annotations are denied to the users other than the Tenant owners. Upon update, if the Tenant allows some labels or
+// annotations, any other key added, removed, or changed is denied.
+func (h *handler) validateForbidden(ctx context.Context, c client.Client, req admission.Request, ns, old *corev1.Namespace) admission.Response {
+	var tenant string
+	for _, or := range ns.GetOwnerReferences() {
+		if or.Kind == "Tenant" {
+			tenant = or.Name
+		}
+	}
+	if len(tenant) == 0 {
+		return admission.Allowed("")
+	}
+
+	tnt := &v1alpha1.Tenant{}
+	if err := c.Get(ctx, types.NamespacedName{Name: tenant}, tnt); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
+	}
+	if key, changed := changedQuotaOverride(old.GetAnnotations(), ns.GetAnnotations()); changed && !tnt.IsOwner(req.UserInfo) {
+		return capsulewebhook.Denied(NewQuotaOverrideError(key, tenant))
+	}
+	options := tnt.Spec.NamespaceOptions
+	if options == nil {
+		return admission.Allowed("")
+	}
+
+	if req.Operation == admissionv1beta1.Update && tnt.IsNamespaceMetadataEditable() {
+		if err := checkAllowed("label", tenant, options.AllowedLabels, old.GetLabels(), ns.GetLabels(), isIgnoredLabel); err != nil {
+			return capsulewebhook.Denied(err)
+		}
+		if err := checkAllowed("annotation", tenant, options.AllowedAnnotations, old.GetAnnotations(), ns.GetAnnotations(), isIgnoredAnnotation); err != nil {
+			return capsulewebhook.Denied(err)
+		}
+	}
+
+	if err := checkForbidden("label", tenant, options.ForbiddenLabels, old.GetLabels(), ns.GetLabels()); err != nil {
+		return capsulewebhook.Denied(err)
+	}
+	if err := checkForbidden("annotation", tenant, options.ForbiddenAnnotations, old.GetAnnotations(), ns.GetAnnotations()); err != nil {
+		return capsulewebhook.Denied(err)
+	}
+	return admission.Allowed("")
+}
+
+// checkForbidden returns the error for the first forbidden key added, removed, or changed between the two sets.
+func checkForbidden(kind, tenant string, spec v1alpha1.ForbiddenListSpec, old, new map[string]string) error {
+	keys := make([]string, 0, len(old)+len(new))
+	for _, metadata := range []map[string]string{old, new} {
+		for k := range metadata {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		ov, ook := old[k]
+		nv, nok := new[k]
+		if ook == nok && ov == nv {
+			continue
+		}
+		if spec.ExactMatch(k) {
+			return NewForbiddenMetadataError(kind, k, tenant, "it's listed in the denied ones")
+		}
+		if spec.RegexMatch(k) {
+			return NewForbiddenMetadataError(kind, k, tenant, fmt.Sprintf("it matches the denied regex %s", spec.DeniedRegex))
+		}
+	}
+	return nil
+}
+
+// checkAllowed returns the error for the first key added, removed, or changed between the two sets that is not allowed,
+// skipping the ignored ones since they're validated on their own.
+func checkAllowed(kind, tenant string, spec v1alpha1.AllowedListSpec, old, new map[string]string, ignored func(string) bool) error {
+	keys := make([]string, 0, len(old)+len(new))
+	for _, metadata := range []map[string]string{old, new} {
+		for k := range metadata {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		ov, ook := old[k]
+		nv, nok := new[k]
+		if ook == nok && ov == nv {
+			continue
+		}
+		if ignored(k) || spec.IsAllowed(k) {
+			continue
+		}
+		return NewNotAllowedMetadataError(kind, k, tenant)
+	}
+	return nil
+}
+
+// isIgnoredLabel returns true for the Capsule labels, protected regardless of the Tenant allowed ones.
+func isIgnoredLabel(key string) bool {
+	return strings.HasPrefix(key, capsuleLabelPrefix)
+}
+
+// isIgnoredAnnotation returns true for the annotations handled by Capsule or the quota override, and the one set by
+// kubectl upon apply.
+func isIgnoredAnnotation(key string) bool {
+	switch key {
+	case nodeSelectorAnnotation, v1alpha1.AppliedLabelsAnnotation, v1alpha1.AppliedAnnotationsAnnotation, v1alpha1.AppliedResourcesAnnotation, v1alpha1.TenantAssignmentAnnotation, lastAppliedAnnotation:
+		return true
+	}
+	return strings.HasPrefix(key, v1alpha1.QuotaOverrideAnnotationPrefix) && strings.HasSuffix(key, v1alpha1.QuotaOverrideAnnotationSuffix)
+}
+
+// changedQuotaOverride returns the first quota override annotation added, removed, or changed between the two sets.
+func changedQuotaOverride(old, new map[string]string) (string, bool) {
+	var keys []string
+	for _, annotations := range []map[string]string{old, new} {
+		for k := range annotations {
+			if strings.HasPrefix(k, v1alpha1.QuotaOverrideAnnotationPrefix) && strings.HasSuffix(k, v1alpha1.QuotaOverrideAnnotationSuffix) {
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		ov, ook := old[k]
+		nv, nok := new[k]
+		if ook != nok || ov != nv {
+			return k, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_protection
+
+import (
+	"fmt"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type deletionProtectedError struct {
+	namespace string
+}
+
+func NewDeletionProtectedError(namespace string) error {
+	return &deletionProtectedError{namespace: namespace}
+}
+
+func (d deletionProtectedError) Error() string {
+	return fmt.Sprintf("The Namespace %s is protected from deletion: remove the %s annotation to delete it", d.namespace, v1alpha1.DeletionProtectionAnnotation)
+}
+
+func (deletionProtectedError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonNamespaceDeletionProtected
+}
@@ -16,6 +16,12 @@ limitations under the License.
 
 package namespace_quota
 
+import (
+	"fmt"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
 type namespaceQuotaExceededError struct{}
 
 func NewNamespaceQuotaExceededError() error {
@@ -25,3 +31,23 @@ func NewNamespaceQuotaExceededError() error {
 func (namespaceQuotaExceededError) Error() string {
 	return "Cannot exceed Namespace quota: please, reach out the system administrators"
 }
+
+func (namespaceQuotaExceededError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonNamespaceQuotaExceeded
+}
+
+type tenantCordonedError struct {
+	tenant string
+}
+
+func NewTenantCordonedError(tenant string) error {
+	return &tenantCordonedError{tenant: tenant}
+}
+
+func (t tenantCordonedError) Error() string {
+	return fmt.Sprintf("Cannot create Namespaces in the cordoned Tenant %s: please, reach out the system administrators", t.tenant)
+}
+
+func (tenantCordonedError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonTenantCordoned
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_quota
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var deniedCreations = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "capsule_namespace_creations_denied_total",
+	Help: "The number of Namespace creations denied since the Tenant quota is exhausted.",
+}, []string{"tenant"})
+
+func init() {
+	metrics.Registry.MustRegister(deniedCreations)
+}
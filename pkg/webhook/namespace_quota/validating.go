@@ -19,9 +19,13 @@ package namespace_quota
 import (
 	"context"
 	"net/http"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -29,7 +33,7 @@ import (
 	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
 )
 
-// +kubebuilder:webhook:path=/validate-v1-namespace-quota,mutating=false,failurePolicy=fail,groups="",resources=namespaces,verbs=create,versions=v1,name=quota.namespace.capsule.clastix.io
+// +kubebuilder:webhook:path=/validate-v1-namespace-quota,mutating=false,failurePolicy=fail,sideEffects=NoneOnDryRun,groups="",resources=namespaces,verbs=create,versions=v1,name=quota.namespace.capsule.clastix.io
 
 type webhook struct {
 	handler capsulewebhook.Handler
@@ -53,28 +57,78 @@ func (w *webhook) GetPath() string {
 	return "/validate-v1-namespace-quota"
 }
 
+// reservationBackoff is jittered to spread the concurrent reservations on the same Tenant, conflicting upon update.
+var reservationBackoff = wait.Backoff{
+	Steps:    10,
+	Duration: 10 * time.Millisecond,
+	Factor:   1.5,
+	Jitter:   0.5,
+}
+
 type handler struct {
+	recorder record.EventRecorder
+	reader   client.Reader
 }
 
-func Handler() capsulewebhook.Handler {
-	return &handler{}
+// Handler denies the Namespace creation in the full Tenants, recording the denial as an Event on the Tenant: the
+// Tenant is retrieved with the uncached reader, since the reservation of the Namespace slot is relying on the resource
+// version. The dry-run requests get the same verdict, although neither the slot is reserved nor the denial recorded.
+func Handler(recorder record.EventRecorder, reader client.Reader) capsulewebhook.Handler {
+	return &handler{
+		recorder: recorder,
+		reader:   reader,
+	}
 }
 
 func (r *handler) OnCreate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) admission.Response {
 		ns := &corev1.Namespace{}
 		if err := decoder.Decode(req, ns); err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
 		}
 
+		dryRun := req.DryRun != nil && *req.DryRun
+
 		for _, or := range ns.ObjectMeta.OwnerReferences {
-			// retrieving the selected Tenant
+			// reserving the Namespace slot in the selected Tenant, retrying upon concurrent reservations
 			t := &capsulev1alpha1.Tenant{}
-			if err := client.Get(ctx, types.NamespacedName{Name: or.Name}, t); err != nil {
-				return admission.Errored(http.StatusBadRequest, err)
+			var reserved bool
+			err := retry.RetryOnConflict(reservationBackoff, func() error {
+				if err := r.reader.Get(ctx, types.NamespacedName{Name: or.Name}, t); err != nil {
+					return err
+				}
+				if t.Spec.Cordoned {
+					return nil
+				}
+				// the API server generates the name before the validating admission: lacking it, the quota is
+				// checked anyway, although no slot can be reserved
+				if len(ns.GetName()) == 0 {
+					t.PruneReservations()
+					reserved = !t.IsFull()
+					return nil
+				}
+				if reserved = t.ReserveNamespace(ns.GetName()); !reserved || dryRun {
+					return nil
+				}
+				return client.Status().Update(ctx, t)
+			})
+			if err != nil {
+				return capsulewebhook.Errored(http.StatusBadRequest, err)
+			}
+			if t.Spec.Cordoned {
+				return capsulewebhook.Denied(NewTenantCordonedError(t.GetName()))
+			}
+			if !reserved && dryRun {
+				return capsulewebhook.Denied(NewNamespaceQuotaExceededError())
 			}
-			if t.IsFull() {
-				return admission.Denied(NewNamespaceQuotaExceededError().Error())
+			if !reserved {
+				name := ns.GetName()
+				if len(name) == 0 {
+					name = ns.GetGenerateName()
+				}
+				r.recorder.Eventf(t, corev1.EventTypeWarning, "NamespaceQuotaExceeded", "Namespace %s creation by %s denied, since the quota of %d Namespaces is exhausted", name, req.UserInfo.Username, t.Spec.NamespaceQuota)
+				deniedCreations.WithLabelValues(t.GetName()).Inc()
+				return capsulewebhook.Denied(NewNamespaceQuotaExceededError())
 			}
 		}
 		// creating NS that is not bounded to any Tenant
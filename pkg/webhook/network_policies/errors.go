@@ -0,0 +1,47 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network_policies
+
+import (
+	"fmt"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type capsuleNetworkPolicyError struct {
+	namespace string
+	name      string
+	tenant    string
+	operation string
+}
+
+func NewCapsuleNetworkPolicyError(namespace, name, tenant, operation string) error {
+	return &capsuleNetworkPolicyError{
+		namespace: namespace,
+		name:      name,
+		tenant:    tenant,
+		operation: operation,
+	}
+}
+
+func (c capsuleNetworkPolicyError) Error() string {
+	return fmt.Sprintf("The NetworkPolicy %s/%s is managed by Capsule from the networkPolicies of the Tenant %s and cannot be %s: please, reach out the system administrators to change the Tenant spec", c.namespace, c.name, c.tenant, c.operation)
+}
+
+func (capsuleNetworkPolicyError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonNetworkPolicyProtected
+}
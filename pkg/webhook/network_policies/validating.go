@@ -21,7 +21,6 @@ import (
 	"net/http"
 
 	networkingv1 "k8s.io/api/networking/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -29,7 +28,7 @@ import (
 	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
 )
 
-// +kubebuilder:webhook:path=/validating-v1-network-policy,mutating=false,failurePolicy=fail,groups=networking.k8s.io,resources=networkpolicies,verbs=create;update;delete,versions=v1,name=validating.network-policy.capsule.clastix.io
+// +kubebuilder:webhook:path=/validating-v1-network-policy,mutating=false,failurePolicy=fail,sideEffects=None,groups=networking.k8s.io,resources=networkpolicies,verbs=create;update;delete,versions=v1,name=validating.network-policy.capsule.clastix.io
 
 type webhook struct {
 	handler capsulewebhook.Handler
@@ -54,6 +53,8 @@ func (w *webhook) GetPath() string {
 type handler struct {
 }
 
+// Handler protects the Network Policies replicated by Capsule from the Tenant ones, denying their update and deletion
+// to the Tenant users, while the Network Policies created by them are left untouched.
 func Handler() capsulewebhook.Handler {
 	return &handler{}
 }
@@ -64,47 +65,36 @@ func (r *handler) OnCreate(client client.Client, decoder *admission.Decoder) cap
 	}
 }
 
-func (r *handler) generic(ctx context.Context, req admission.Request, client client.Client, decoder *admission.Decoder) (bool, error) {
-	var err error
+// generic denies the operation on the Network Policies labelled as replicated from a Tenant: the existing object is
+// checked, so the label cannot be removed to bypass the protection.
+func (r *handler) generic(req admission.Request, decoder *admission.Decoder, operation string) admission.Response {
 	np := &networkingv1.NetworkPolicy{}
-	err = client.Get(ctx, types.NamespacedName{Namespace: req.AdmissionRequest.Namespace, Name: req.AdmissionRequest.Name}, np)
-	if err != nil {
-		return false, err
+	if err := decoder.DecodeRaw(req.OldObject, np); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
 	}
 
-	return r.isCapsuleNetworkPolicy(np), nil
+	if tenant, ok := r.capsuleNetworkPolicyTenant(np); ok {
+		return capsulewebhook.Denied(NewCapsuleNetworkPolicyError(np.GetNamespace(), np.GetName(), tenant, operation))
+	}
+
+	return admission.Allowed("")
 }
 
 func (r *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) admission.Response {
-		ok, err := r.generic(ctx, req, client, decoder)
-		if err != nil {
-			return admission.Errored(http.StatusInternalServerError, err)
-		}
-		if ok {
-			return admission.Denied("Capsule Network Policies cannot be deleted: please, reach out the system administrators")
-		}
-
-		return admission.Allowed("")
+		return r.generic(req, decoder, "deleted")
 	}
 }
 
-func (r *handler) isCapsuleNetworkPolicy(np *networkingv1.NetworkPolicy) (ok bool) {
+// capsuleNetworkPolicyTenant returns the name of the Tenant the Network Policy has been replicated from, if any.
+func (r *handler) capsuleNetworkPolicyTenant(np *networkingv1.NetworkPolicy) (tenant string, ok bool) {
 	l, _ := v1alpha1.GetTypeLabel(&v1alpha1.Tenant{})
-	_, ok = np.GetLabels()[l]
+	tenant, ok = np.GetLabels()[l]
 	return
 }
 
 func (r *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) admission.Response {
-		ok, err := r.generic(ctx, req, client, decoder)
-		if err != nil {
-			return admission.Errored(http.StatusInternalServerError, err)
-		}
-		if ok {
-			return admission.Denied("Capsule Network Policies cannot be updated: please, reach out the system administrators")
-		}
-
-		return admission.Allowed("")
+		return r.generic(req, decoder, "updated")
 	}
 }
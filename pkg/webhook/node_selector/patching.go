@@ -0,0 +1,132 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node_selector
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"gomodules.xyz/jsonpatch/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/mutate-v1-pod-node-selector,mutating=true,failurePolicy=fail,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=nodeselector.pod.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+func (w *webhook) GetName() string {
+	return "NodeSelector"
+}
+
+func (w *webhook) GetPath() string {
+	return "/mutate-v1-pod-node-selector"
+}
+
+type handler struct {
+}
+
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+// OnCreate merges the Tenant node selector into the Pod one, denying the Pods selecting different values for the same
+// keys: it's the fallback of the PodNodeSelector admission plugin, enforcing the Namespace annotation only if enabled.
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		pod := &corev1.Pod{}
+		if err := decoder.Decode(req, pod); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		tl := &v1alpha1.TenantList{}
+		if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+		}); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		if len(tl.Items) == 0 || len(tl.Items[0].Spec.NodeSelector) == 0 {
+			return admission.Allowed("")
+		}
+
+		selector := tl.Items[0].Spec.NodeSelector
+		if pod.Spec.NodeSelector == nil {
+			return admission.Patched("Assigning the Tenant node selector", jsonpatch.JsonPatchOperation{
+				Operation: "add",
+				Path:      "/spec/nodeSelector",
+				Value:     selector,
+			})
+		}
+
+		keys := make([]string, 0, len(selector))
+		for k := range selector {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var patch []jsonpatch.JsonPatchOperation
+		for _, k := range keys {
+			v, ok := pod.Spec.NodeSelector[k]
+			if !ok {
+				patch = append(patch, jsonpatch.JsonPatchOperation{
+					Operation: "add",
+					Path:      "/spec/nodeSelector/" + strings.ReplaceAll(k, "/", "~1"), // http://jsonpatch.com/#json-pointer
+					Value:     selector[k],
+				})
+				continue
+			}
+			if v != selector[k] {
+				return capsulewebhook.Denied(capsulewebhook.NewError(capsulewebhook.ReasonNodeSelectorConflict, "The node selector %s=%s conflicts with the Tenant one %s=%s", k, v, k, selector[k]))
+			}
+		}
+
+		if len(patch) > 0 {
+			return admission.Patched("Assigning the Tenant node selector", patch...)
+		}
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object_quota
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type objectQuotaExceeded struct {
+	resource corev1.ResourceName
+	tenant   string
+	max      uint
+}
+
+func NewObjectQuotaExceeded(resource corev1.ResourceName, tenant string, max uint) error {
+	return &objectQuotaExceeded{resource: resource, tenant: tenant, max: max}
+}
+
+func (o objectQuotaExceeded) Error() string {
+	return fmt.Sprintf("Cannot create more %s in the Tenant %s, limited to %d across all its Namespaces: please, reach out the system administrators", o.resource, o.tenant, o.max)
+}
+
+func (objectQuotaExceeded) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonObjectQuotaExceeded
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object_quota
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/validating-v1-object-quota,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=services;secrets;configmaps;persistentvolumeclaims;pods;serviceaccounts,verbs=create,versions=v1,name=object-quota.capsule.clastix.io
+
+// lists returns the list type of the resources the Tenant can limit the count of: these are served by the informers
+// cache, listing them from the API server upon the start, so the count is rebuilt after a restart.
+var lists = map[corev1.ResourceName]func() runtime.Object{
+	corev1.ResourceServices:               func() runtime.Object { return &corev1.ServiceList{} },
+	corev1.ResourceSecrets:                func() runtime.Object { return &corev1.SecretList{} },
+	corev1.ResourceConfigMaps:             func() runtime.Object { return &corev1.ConfigMapList{} },
+	corev1.ResourcePersistentVolumeClaims: func() runtime.Object { return &corev1.PersistentVolumeClaimList{} },
+	corev1.ResourcePods:                   func() runtime.Object { return &corev1.PodList{} },
+	"serviceaccounts":                     func() runtime.Object { return &corev1.ServiceAccountList{} },
+}
+
+// IsSupportedResource returns true if the Tenant can limit the count of the resource.
+func IsSupportedResource(resource corev1.ResourceName) bool {
+	_, ok := lists[resource]
+	return ok
+}
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+func (w *webhook) GetName() string {
+	return "ObjectQuota"
+}
+
+func (w *webhook) GetPath() string {
+	return "/validating-v1-object-quota"
+}
+
+type handler struct {
+	failOpen bool
+}
+
+// Handler returns the handler capping the Tenant object count: when the count cannot be computed, the creation is
+// allowed if failing open, otherwise denied.
+func Handler(failOpen bool) capsulewebhook.Handler {
+	return &handler{
+		failOpen: failOpen,
+	}
+}
+
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		resource := corev1.ResourceName(req.Resource.Resource)
+		newList, ok := lists[resource]
+		if !ok {
+			return admission.Allowed("")
+		}
+
+		tl := &v1alpha1.TenantList{}
+		if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+		}); err != nil {
+			return h.failure(err)
+		}
+		if len(tl.Items) == 0 {
+			return admission.Allowed("")
+		}
+
+		tenant := tl.Items[0]
+		max, ok := tenant.Spec.ObjectQuota[resource]
+		if !ok {
+			return admission.Allowed("")
+		}
+
+		var count uint
+		for _, ns := range tenant.Status.Namespaces {
+			list := newList()
+			if err := c.List(ctx, list, client.InNamespace(ns)); err != nil {
+				return h.failure(err)
+			}
+			count += uint(meta.LenList(list))
+		}
+		// the count is eventually consistent, the concurrent creations could exceed it by few objects
+		if count >= max {
+			return capsulewebhook.Denied(NewObjectQuotaExceeded(resource, tenant.GetName(), max))
+		}
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) failure(err error) admission.Response {
+	if h.failOpen {
+		return admission.Allowed(fmt.Sprintf("Unable to count the Tenant objects, allowed by the failure policy: %s", err.Error()))
+	}
+	return capsulewebhook.Errored(http.StatusInternalServerError, err)
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
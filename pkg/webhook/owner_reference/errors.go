@@ -0,0 +1,77 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package owner_reference
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type tenantNotFoundError struct {
+	tenant string
+}
+
+func NewTenantNotFoundError(tenant string) error {
+	return &tenantNotFoundError{tenant: tenant}
+}
+
+func (t tenantNotFoundError) Error() string {
+	return fmt.Sprintf("Cannot assign the Namespace to the Tenant %s, since it doesn't exist", t.tenant)
+}
+
+func (tenantNotFoundError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonTenantNotFound
+}
+
+type tenantNotOwnedError struct {
+	tenant string
+	owned  []string
+}
+
+func NewTenantNotOwnedError(tenant string, owned []string) error {
+	return &tenantNotOwnedError{tenant: tenant, owned: owned}
+}
+
+func (t tenantNotOwnedError) Error() string {
+	if len(t.owned) == 0 {
+		return fmt.Sprintf("Cannot assign the Namespace to the Tenant %s, since not owned: you do not have any Tenant assigned", t.tenant)
+	}
+	return fmt.Sprintf("Cannot assign the Namespace to the Tenant %s, since not owned: please, select one of the owned Tenants (%s) with the %s annotation", t.tenant, strings.Join(t.owned, ", "), v1alpha1.TenantAssignmentAnnotation)
+}
+
+func (tenantNotOwnedError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonTenantNotOwned
+}
+
+type tenantSelectionRequiredError struct {
+	owned []string
+}
+
+func NewTenantSelectionRequiredError(owned []string) error {
+	return &tenantSelectionRequiredError{owned: owned}
+}
+
+func (t tenantSelectionRequiredError) Error() string {
+	return fmt.Sprintf("Unable to assign the Namespace, since multiple Tenants are owned: please, select one of them (%s) with the %s annotation", strings.Join(t.owned, ", "), v1alpha1.TenantAssignmentAnnotation)
+}
+
+func (tenantSelectionRequiredError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonTenantSelectionRequired
+}
@@ -21,9 +21,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,11 +34,13 @@ import (
 
 	"github.com/clastix/capsule/api/v1alpha1"
 	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/pkg/configuration"
+	"github.com/clastix/capsule/pkg/utils"
 	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
 	authenticationv1 "k8s.io/api/authentication/v1"
 )
 
-// +kubebuilder:webhook:path=/mutate-v1-namespace-owner-reference,mutating=true,failurePolicy=fail,groups="",resources=namespaces,verbs=create,versions=v1,name=owner.namespace.capsule.clastix.io
+// +kubebuilder:webhook:path=/mutate-v1-namespace-owner-reference,mutating=true,failurePolicy=fail,sideEffects=None,groups="",resources=namespaces,verbs=create,versions=v1,name=owner.namespace.capsule.clastix.io
 
 type webhook struct {
 	handler capsulewebhook.Handler
@@ -59,12 +63,14 @@ func (w *webhook) GetPath() string {
 }
 
 type handler struct {
-	forceTenantPrefix bool
+	configuration *configuration.Store
 }
 
-func Handler(forceTenantPrefix bool) capsulewebhook.Handler {
+// Handler assigns the new Namespace to the Tenant owned by the requesting user, or to the Tenant named by the
+// assignment annotation when requested by a member of the break-glass groups, as the cluster administrators.
+func Handler(configuration *configuration.Store) capsulewebhook.Handler {
 	return &handler{
-		forceTenantPrefix: forceTenantPrefix,
+		configuration: configuration,
 	}
 }
 
@@ -72,81 +78,109 @@ func (h *handler) OnCreate(clt client.Client, decoder *admission.Decoder) capsul
 	return func(ctx context.Context, req admission.Request) admission.Response {
 		ns := &corev1.Namespace{}
 		if err := decoder.Decode(req, ns); err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
 		}
 		ln, err := capsulev1alpha1.GetTypeLabel(&capsulev1alpha1.Tenant{})
 		if err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
 		}
-		// If we already had TenantName label on NS -> assign to it
-		if len(ns.ObjectMeta.Labels) > 0 {
-			l, ok := ns.ObjectMeta.Labels[ln]
-			// assigning namespace to Tenant in case of label
-			if ok {
-				// retrieving the selected Tenant
-				t := &capsulev1alpha1.Tenant{}
-				if err := clt.Get(ctx, types.NamespacedName{Name: l}, t); err != nil {
-					return admission.Errored(http.StatusBadRequest, err)
+
+		// The Tenant is selected by the assignment annotation, or by the Tenant label as before: the members of the
+		// break-glass groups act on behalf of the Tenant owners, while the Tenant users must own the selected Tenant, so
+		// they cannot move Namespaces across Tenants
+		selected, annotated := ns.GetAnnotations()[capsulev1alpha1.TenantAssignmentAnnotation]
+		if !annotated {
+			selected = ns.GetLabels()[ln]
+		}
+		if len(selected) > 0 {
+			t := &capsulev1alpha1.Tenant{}
+			if err := clt.Get(ctx, types.NamespacedName{Name: selected}, t); err != nil {
+				if errors.IsNotFound(err) {
+					return capsulewebhook.Denied(NewTenantNotFoundError(selected))
+				}
+				return capsulewebhook.Errored(http.StatusBadRequest, err)
+			}
+			if !t.IsOwner(req.UserInfo) {
+				if annotated && utils.UserGroupList(req.UserInfo.Groups).IsInCapsuleGroups(h.configuration.FromContext(ctx).BreakGlassGroups) {
+					// the ownership denial overridden by the assignment is audited as the other break-glass ones
+					return capsulewebhook.BreakGlassPatch(h.patchResponseForOwnerRef(t, ns), capsulewebhook.ReasonTenantNotOwned)
 				}
-				// Tenant owner must adhere to user that asked for NS creation
-				if !h.isTenantOwner(t.Spec.Owner, req.UserInfo) {
-					return admission.Denied("Cannot assign the desired namespace to a non-owned Tenant")
+				_, names, err := h.ownedTenants(ctx, clt, req.UserInfo)
+				if err != nil {
+					return capsulewebhook.Errored(http.StatusBadRequest, err)
 				}
-				// Patching the response
-				return h.patchResponseForOwnerRef(t, ns)
+				return capsulewebhook.Denied(NewTenantNotOwnedError(selected, names))
 			}
-
+			return h.patchResponseForOwnerRef(t, ns)
 		}
-		// If we forceTenantPrefix -> find Tenant from NS name
-		if h.forceTenantPrefix {
+
+		// If we forceTenantPrefix -> find Tenant from NS name, or the generated name prefix
+		if h.configuration.FromContext(ctx).ForceTenantPrefix {
+			name := ns.GetName()
+			if len(name) == 0 {
+				name = ns.GetGenerateName()
+			}
 			t := &v1alpha1.Tenant{}
-			tenantName := strings.Split(ns.GetName(), "-")[0]
+			tenantName := strings.Split(name, "-")[0]
 			if err := clt.Get(ctx, types.NamespacedName{Name: tenantName}, t); err != nil {
-				return admission.Errored(http.StatusBadRequest, err)
+				if errors.IsNotFound(err) {
+					return capsulewebhook.Denied(NewTenantNotFoundError(tenantName))
+				}
+				return capsulewebhook.Errored(http.StatusBadRequest, err)
 			}
 			return h.patchResponseForOwnerRef(t, ns)
 		}
 
-		tenants := []*capsulev1alpha1.Tenant{}
-
-		// Find tenants belonging to user
-		tlu, err := h.listTenantsForOwnerKind(ctx, "User", req.UserInfo.Username, clt)
+		tenants, names, err := h.ownedTenants(ctx, clt, req.UserInfo)
 		if err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
-		}
-		// No groups single tenant short-circuit
-		if len(req.UserInfo.Groups) == 0 && len(tlu.Items) == 1 {
-			return h.patchResponseForOwnerRef(&tlu.Items[0], ns)
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
 		}
 
-		switch userTenants := len(tlu.Items); {
-		case userTenants > 1:
-			return admission.Denied("Unable to assign namespace to tenant. Please use " + ln + " label when creating a namespace")
-		case userTenants == 1:
-			tenants = append(tenants, &tlu.Items[0])
+		// the user must select the Tenant rather than being assigned to the first match
+		if len(tenants) > 1 {
+			return capsulewebhook.Denied(NewTenantSelectionRequiredError(names))
+		}
+		if len(tenants) == 1 {
+			return h.patchResponseForOwnerRef(tenants[names[0]], ns)
 		}
 
-		for _, group := range req.UserInfo.Groups {
-			tl, err := h.listTenantsForOwnerKind(ctx, "Group", group, clt)
-			if err != nil {
-				return admission.Errored(http.StatusBadRequest, err)
-			}
-			for _, item := range tl.Items {
-				tenants = append(tenants, &item)
-			}
-			// more than one tenant found, returning error
-			if len(tenants) > 1 {
-				return admission.Denied("Unable to assign namespace to tenant. Please use " + ln + " label when creating a namespace")
+		return capsulewebhook.Denied(capsulewebhook.NewError(capsulewebhook.ReasonTenantNotAssigned, "You do not have any Tenant assigned: please, reach out the system administrators"))
+	}
+}
+
+// ownedTenants returns the Tenants owned by the user, directly or by any of their groups, along with their sorted
+// names: the same Tenant could be owned in both ways, so these are tracked by name.
+func (h *handler) ownedTenants(ctx context.Context, clt client.Client, userInfo authenticationv1.UserInfo) (map[string]*capsulev1alpha1.Tenant, []string, error) {
+	tenants := make(map[string]*capsulev1alpha1.Tenant)
+	var names []string
+	collect := func(ownerKind string, ownerName string) error {
+		tl, err := h.listTenantsForOwnerKind(ctx, ownerKind, ownerName, clt)
+		if err != nil {
+			return err
+		}
+		for i := range tl.Items {
+			if _, ok := tenants[tl.Items[i].GetName()]; ok {
+				continue
 			}
+			tenants[tl.Items[i].GetName()] = &tl.Items[i]
+			names = append(names, tl.Items[i].GetName())
 		}
+		return nil
+	}
 
-		// Single tenant found for group
-		if len(tenants) == 1 {
-			return h.patchResponseForOwnerRef(tenants[0], ns)
+	// ServiceAccount owners are matched by their username too
+	for _, kind := range []string{"User", "ServiceAccount"} {
+		if err := collect(kind, userInfo.Username); err != nil {
+			return nil, nil, err
 		}
-
-		return admission.Denied("You do not have any Tenant assigned: please, reach out the system administrators")
 	}
+	for _, group := range userInfo.Groups {
+		if err := collect("Group", group); err != nil {
+			return nil, nil, err
+		}
+	}
+	sort.Strings(names)
+	return tenants, names, nil
 }
 
 func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
@@ -157,7 +191,7 @@ func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) cap
 
 func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) admission.Response {
-		return admission.Denied("Capsule user cannot update a Namespace")
+		return capsulewebhook.Denied(capsulewebhook.NewError(capsulewebhook.ReasonNamespaceUpdateForbidden, "Capsule user cannot update a Namespace"))
 	}
 }
 
@@ -166,14 +200,50 @@ func (h *handler) patchResponseForOwnerRef(tenant *capsulev1alpha1.Tenant, ns *c
 	_ = capsulev1alpha1.AddToScheme(scheme)
 	_ = corev1.AddToScheme(scheme)
 
+	// the Namespaces of a Tenant being deleted would be released or deleted along with it
+	if tenant.GetDeletionTimestamp() != nil {
+		return capsulewebhook.Denied(capsulewebhook.NewError(capsulewebhook.ReasonTenantTerminating, "Cannot assign the Namespace to the Tenant %s, since it is being deleted", tenant.GetName()))
+	}
+
 	o, _ := json.Marshal(ns.DeepCopy())
+	if tenant.Spec.ForceTenantPrefix {
+		h.prefixName(tenant, ns)
+	}
+	if _, ok := ns.GetAnnotations()[capsulev1alpha1.DeletionProtectionAnnotation]; tenant.Spec.NamespaceDeletionProtection && !ok {
+		if ns.Annotations == nil {
+			ns.Annotations = make(map[string]string)
+		}
+		ns.Annotations[capsulev1alpha1.DeletionProtectionAnnotation] = "true"
+	}
+	// labelling the Namespace in the patch rather than waiting for the reconciliation, since the generated names
+	// are known only after the admission
+	ln, err := capsulev1alpha1.GetTypeLabel(&capsulev1alpha1.Tenant{})
+	if err != nil {
+		return capsulewebhook.Errored(http.StatusInternalServerError, err)
+	}
+	if ns.Labels == nil {
+		ns.Labels = make(map[string]string)
+	}
+	ns.Labels[ln] = tenant.GetName()
 	if err := controllerutil.SetControllerReference(tenant, ns, scheme); err != nil {
-		return admission.Errored(http.StatusInternalServerError, err)
+		return capsulewebhook.Errored(http.StatusInternalServerError, err)
 	}
 	c, _ := json.Marshal(ns)
 	return admission.PatchResponseFromRaw(o, c)
 }
 
+// prefixName prepends the Tenant name to the Namespace one, or to the generated name prefix since the name is
+// generated by the API server after the mutating admission.
+func (h *handler) prefixName(tenant *capsulev1alpha1.Tenant, ns *corev1.Namespace) {
+	prefix := tenant.GetName() + "-"
+	switch {
+	case len(ns.GetName()) > 0 && !strings.HasPrefix(ns.GetName(), prefix):
+		ns.SetName(prefix + ns.GetName())
+	case len(ns.GetName()) == 0 && !strings.HasPrefix(ns.GetGenerateName(), prefix):
+		ns.SetGenerateName(prefix + ns.GetGenerateName())
+	}
+}
+
 func (h *handler) listTenantsForOwnerKind(ctx context.Context, ownerKind string, ownerName string, clt client.Client) (*v1alpha1.TenantList, error) {
 	tl := &v1alpha1.TenantList{}
 	f := client.MatchingFields{
@@ -182,17 +252,3 @@ func (h *handler) listTenantsForOwnerKind(ctx context.Context, ownerKind string,
 	err := clt.List(ctx, tl, f)
 	return tl, err
 }
-
-func (h *handler) isTenantOwner(os v1alpha1.OwnerSpec, userInfo authenticationv1.UserInfo) bool {
-	if os.Kind == "User" && userInfo.Username == os.Name {
-		return true
-	}
-	if os.Kind == "Group" {
-		for _, group := range userInfo.Groups {
-			if group == os.Name {
-				return true
-			}
-		}
-	}
-	return false
-}
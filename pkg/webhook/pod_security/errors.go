@@ -0,0 +1,114 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod_security
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type csiDriverForbidden struct {
+	volume  string
+	driver  string
+	allowed []string
+}
+
+func NewCSIDriverForbidden(volume, driver string, allowed []string) error {
+	return &csiDriverForbidden{volume: volume, driver: driver, allowed: allowed}
+}
+
+func (c csiDriverForbidden) Error() string {
+	return fmt.Sprintf("Volume %s CSI driver %s is forbidden for the current Tenant: allowed ones are %s", c.volume, c.driver, strings.Join(c.allowed, ", "))
+}
+
+func (csiDriverForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonCSIDriverForbidden
+}
+
+type hostNamespaceForbidden struct {
+	field string
+}
+
+func NewHostNamespaceForbidden(field string) error {
+	return &hostNamespaceForbidden{field: field}
+}
+
+func (h hostNamespaceForbidden) Error() string {
+	return fmt.Sprintf("Pods using %s are forbidden for the current Tenant", h.field)
+}
+
+func (hostNamespaceForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonHostNamespaceForbidden
+}
+
+type hostPathForbidden struct {
+	volume string
+	path   string
+}
+
+func NewHostPathForbidden(volume, path string) error {
+	return &hostPathForbidden{volume: volume, path: path}
+}
+
+func (h hostPathForbidden) Error() string {
+	return fmt.Sprintf("Volume %s host path %s is forbidden for the current Tenant, since hostPath volumes are not allowed", h.volume, h.path)
+}
+
+func (hostPathForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonHostPathForbidden
+}
+
+type hostPortForbidden struct {
+	container string
+	port      int32
+	r         *v1alpha1.HostPortRange
+}
+
+func NewHostPortForbidden(container string, port int32, r *v1alpha1.HostPortRange) error {
+	return &hostPortForbidden{container: container, port: port, r: r}
+}
+
+func (h hostPortForbidden) Error() string {
+	if h.r == nil {
+		return fmt.Sprintf("Container %s host port %d is forbidden for the current Tenant, since host ports are not allowed", h.container, h.port)
+	}
+	return fmt.Sprintf("Container %s host port %d is forbidden for the current Tenant: allowed ones are in the %d-%d range", h.container, h.port, h.r.Min, h.r.Max)
+}
+
+func (hostPortForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonHostPortForbidden
+}
+
+type privilegeForbidden struct {
+	container string
+	field     string
+}
+
+func NewPrivilegeForbidden(container, field string) error {
+	return &privilegeForbidden{container: container, field: field}
+}
+
+func (p privilegeForbidden) Error() string {
+	return fmt.Sprintf("Container %s security context %s is forbidden for the current Tenant", p.container, p.field)
+}
+
+func (privilegeForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonPrivilegeForbidden
+}
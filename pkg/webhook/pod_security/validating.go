@@ -0,0 +1,219 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod_security
+
+import (
+	"context"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/validating-v1-pod-security,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=pods;pods/ephemeralcontainers,verbs=create;update,versions=v1,name=security.pod.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetName() string {
+	return "PodSecurity"
+}
+
+func (w *webhook) GetPath() string {
+	return "/validating-v1-pod-security"
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+type handler struct {
+}
+
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		pod := &corev1.Pod{}
+		if err := decoder.Decode(req, pod); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		spec, ok, err := h.podSecurity(ctx, c, req.Namespace)
+		if err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+		if !ok {
+			return admission.Allowed("")
+		}
+
+		if err := validateHostNamespaces(spec, pod.Spec); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+		if err := validateVolumes(spec, pod.Spec.Volumes); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+		for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+			for _, container := range containers {
+				if err := validateSecurityContext(spec, container.Name, container.SecurityContext); err != nil {
+					return capsulewebhook.Errored(http.StatusBadRequest, err)
+				}
+			}
+		}
+		for _, container := range pod.Spec.EphemeralContainers {
+			if err := validateSecurityContext(spec, container.Name, container.SecurityContext); err != nil {
+				return capsulewebhook.Errored(http.StatusBadRequest, err)
+			}
+		}
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+// OnUpdate validates the ephemeral containers, the only ones that can be added to a running Pod through their own
+// subresource, carrying an EphemeralContainers object rather than a Pod.
+func (h *handler) OnUpdate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		if req.SubResource != "ephemeralcontainers" {
+			return admission.Allowed("")
+		}
+
+		ec := &corev1.EphemeralContainers{}
+		if err := decoder.Decode(req, ec); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		spec, ok, err := h.podSecurity(ctx, c, req.Namespace)
+		if err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+		if !ok {
+			return admission.Allowed("")
+		}
+
+		for _, container := range ec.EphemeralContainers {
+			if err := validateSecurityContext(spec, container.Name, container.SecurityContext); err != nil {
+				return capsulewebhook.Errored(http.StatusBadRequest, err)
+			}
+		}
+		return admission.Allowed("")
+	}
+}
+
+// podSecurity returns the Pod Security spec of the Tenant the Namespace belongs to, if any.
+func (h *handler) podSecurity(ctx context.Context, c client.Client, namespace string) (v1alpha1.PodSecuritySpec, bool, error) {
+	tl := &v1alpha1.TenantList{}
+	if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", namespace),
+	}); err != nil {
+		return v1alpha1.PodSecuritySpec{}, false, err
+	}
+	if len(tl.Items) == 0 {
+		return v1alpha1.PodSecuritySpec{}, false, nil
+	}
+	return tl.Items[0].Spec.PodSecurity, true, nil
+}
+
+// validateSecurityContext checks the privileges requested by a container: the privilege escalation is denied only if
+// explicitly requested, although allowed by default by Kubernetes.
+func validateSecurityContext(spec v1alpha1.PodSecuritySpec, container string, sc *corev1.SecurityContext) error {
+	if sc == nil {
+		return nil
+	}
+	if sc.Privileged != nil && *sc.Privileged && !spec.AllowPrivilegedContainers {
+		return NewPrivilegeForbidden(container, "privileged")
+	}
+	if sc.AllowPrivilegeEscalation != nil && *sc.AllowPrivilegeEscalation && !spec.AllowPrivilegeEscalation {
+		return NewPrivilegeForbidden(container, "allowPrivilegeEscalation")
+	}
+	return nil
+}
+
+// validateHostNamespaces checks the host namespaces and the host ports requested by the Pod: the latter are skipped
+// for the Pods in the host network, since these are already bound to any host port.
+func validateHostNamespaces(spec v1alpha1.PodSecuritySpec, pod corev1.PodSpec) error {
+	if pod.HostNetwork && !spec.AllowHostNetwork {
+		return NewHostNamespaceForbidden("hostNetwork")
+	}
+	if pod.HostPID && !spec.AllowHostPID {
+		return NewHostNamespaceForbidden("hostPID")
+	}
+	if pod.HostIPC && !spec.AllowHostIPC {
+		return NewHostNamespaceForbidden("hostIPC")
+	}
+	if pod.HostNetwork {
+		return nil
+	}
+
+	for _, containers := range [][]corev1.Container{pod.InitContainers, pod.Containers} {
+		for _, container := range containers {
+			for _, port := range container.Ports {
+				if port.HostPort == 0 {
+					continue
+				}
+				if !spec.AllowHostPorts {
+					return NewHostPortForbidden(container.Name, port.HostPort, nil)
+				}
+				if r := spec.HostPortRange; r != nil && (port.HostPort < r.Min || port.HostPort > r.Max) {
+					return NewHostPortForbidden(container.Name, port.HostPort, r)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateVolumes checks the hostPath and the CSI inline ephemeral volumes declared by the Pod: the hostPath ones are
+// denied only if explicitly disallowed, since the Tenants never updated since their restriction keep them allowed.
+func validateVolumes(spec v1alpha1.PodSecuritySpec, volumes []corev1.Volume) error {
+	for _, volume := range volumes {
+		if volume.HostPath != nil && spec.AllowHostPath != nil && !*spec.AllowHostPath {
+			return NewHostPathForbidden(volume.Name, volume.HostPath.Path)
+		}
+		if volume.CSI == nil || len(spec.AllowedCSIDrivers) == 0 {
+			continue
+		}
+		allowed := false
+		for _, driver := range spec.AllowedCSIDrivers {
+			if driver == volume.CSI.Driver {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return NewCSIDriverForbidden(volume.Name, volume.CSI.Driver, spec.AllowedCSIDrivers)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority_class
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type priorityClassForbidden struct {
+	priorityClassName string
+	spec              v1alpha1.PriorityClassesSpec
+}
+
+func NewPriorityClassForbidden(priorityClassName string, spec v1alpha1.PriorityClassesSpec) error {
+	return &priorityClassForbidden{priorityClassName: priorityClassName, spec: spec}
+}
+
+func (f priorityClassForbidden) Error() string {
+	return fmt.Sprintf("Priority Class %s is forbidden for the current Tenant: allowed ones are [%s] or matching the pattern %q", f.priorityClassName, strings.Join(f.spec.Allowed, ", "), f.spec.AllowedRegex)
+}
+
+func (priorityClassForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonPriorityClassForbidden
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority_class
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	v1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/validating-v1-pod-priority-class,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=priorityclass.pod.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetName() string {
+	return "PriorityClass"
+}
+
+func (w *webhook) GetPath() string {
+	return "/validating-v1-pod-priority-class"
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+type handler struct {
+}
+
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		pod := &v1.Pod{}
+		if err := decoder.Decode(req, pod); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		pc := pod.Spec.PriorityClassName
+		if len(pc) == 0 {
+			return admission.Allowed("")
+		}
+
+		tl := &v1alpha1.TenantList{}
+		if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+		}); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		// the Namespace doesn't belong to any Tenant
+		if len(tl.Items) == 0 {
+			return admission.Allowed("")
+		}
+
+		spec := tl.Items[0].Spec.PriorityClasses
+		if len(spec.Allowed) > 0 && spec.Allowed.IsStringInList(pc) {
+			return admission.Allowed("")
+		}
+		if len(spec.AllowedRegex) > 0 {
+			if matched, _ := regexp.MatchString(spec.AllowedRegex, pc); matched {
+				return admission.Allowed("")
+			}
+		}
+
+		// the cluster default PriorityClass is assigned by the API server to the Pods without one
+		def, err := h.defaultPriorityClass(ctx, c)
+		if err != nil {
+			return capsulewebhook.Errored(http.StatusInternalServerError, err)
+		}
+		if pc == def {
+			if spec.DenyDefault {
+				return capsulewebhook.Errored(http.StatusBadRequest, NewPriorityClassForbidden(pc, spec))
+			}
+			return admission.Allowed("")
+		}
+
+		if len(spec.Allowed) == 0 && len(spec.AllowedRegex) == 0 {
+			return admission.Allowed("")
+		}
+		return capsulewebhook.Errored(http.StatusBadRequest, NewPriorityClassForbidden(pc, spec))
+	}
+}
+
+func (h *handler) defaultPriorityClass(ctx context.Context, c client.Client) (string, error) {
+	pcl := &schedulingv1.PriorityClassList{}
+	if err := c.List(ctx, pcl); err != nil {
+		return "", err
+	}
+	for _, pc := range pcl.Items {
+		if pc.GlobalDefault {
+			return pc.GetName(), nil
+		}
+	}
+	return "", nil
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
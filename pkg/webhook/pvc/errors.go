@@ -18,6 +18,10 @@ package pvc
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
 )
 
 type storageClassNotValid struct{}
@@ -27,17 +31,42 @@ func NewStorageClassNotValid() error {
 }
 
 func (storageClassNotValid) Error() string {
-	return "A valid Storage Class must be used"
+	return "A valid Storage Class must be used, since no default one is available in the cluster"
+}
+
+func (storageClassNotValid) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonStorageClassNotValid
 }
 
 type storageClassForbidden struct {
 	storageClassName string
+	spec             v1alpha1.StorageClassesSpec
 }
 
-func NewStorageClassForbidden(storageClassName string) error {
-	return &storageClassForbidden{storageClassName: storageClassName}
+func NewStorageClassForbidden(storageClassName string, spec v1alpha1.StorageClassesSpec) error {
+	return &storageClassForbidden{storageClassName: storageClassName, spec: spec}
 }
 
 func (f storageClassForbidden) Error() string {
-	return fmt.Sprintf("Storage Class %s is forbidden for the current Tenant", f.storageClassName)
+	return fmt.Sprintf("Storage Class %s is forbidden for the current Tenant: allowed ones are [%s] or matching the pattern %q", f.storageClassName, strings.Join(f.spec.Allowed, ", "), f.spec.AllowedRegex)
+}
+
+func (storageClassForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonStorageClassForbidden
+}
+
+type storageClassRequired struct {
+	spec v1alpha1.StorageClassesSpec
+}
+
+func NewStorageClassRequired(spec v1alpha1.StorageClassesSpec) error {
+	return &storageClassRequired{spec: spec}
+}
+
+func (r storageClassRequired) Error() string {
+	return fmt.Sprintf("A Storage Class must be explicitly set for the current Tenant: allowed ones are [%s] or matching the pattern %q", strings.Join(r.spec.Allowed, ", "), r.spec.AllowedRegex)
+}
+
+func (storageClassRequired) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonStorageClassRequired
 }
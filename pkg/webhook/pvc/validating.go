@@ -22,6 +22,7 @@ import (
 	"regexp"
 
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -30,7 +31,9 @@ import (
 	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
 )
 
-// +kubebuilder:webhook:path=/validating-v1-pvc,mutating=false,failurePolicy=fail,groups="",resources=persistentvolumeclaims,verbs=create,versions=v1,name=pvc.capsule.clastix.io
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// +kubebuilder:webhook:path=/validating-v1-pvc,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=persistentvolumeclaims,verbs=create,versions=v1,name=pvc.capsule.clastix.io
 
 type webhook struct {
 	handler capsulewebhook.Handler
@@ -61,40 +64,82 @@ func Handler() capsulewebhook.Handler {
 
 func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) admission.Response {
-		var valid, matched bool
 		pvc := &v1.PersistentVolumeClaim{}
 
 		if err := decoder.Decode(req, pvc); err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
-		}
-
-		if pvc.Spec.StorageClassName == nil {
-			return admission.Errored(http.StatusBadRequest, NewStorageClassNotValid())
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
 		}
 
 		tl := &capsulev1alpha1.TenantList{}
 		if err := c.List(ctx, tl, client.MatchingFieldsSelector{
 			Selector: fields.OneTermEqualSelector(".status.namespaces", pvc.Namespace),
 		}); err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
 		}
 
-		sc := *pvc.Spec.StorageClassName
-
-		if len(tl.Items[0].Spec.StorageClasses.Allowed) > 0 {
-			valid = tl.Items[0].Spec.StorageClasses.Allowed.IsStringInList(sc)
+		// the Namespace doesn't belong to any Tenant
+		if len(tl.Items) == 0 {
+			return admission.Allowed("")
 		}
 
-		if len(tl.Items[0].Spec.StorageClasses.AllowedRegex) > 0 {
-			matched, _ = regexp.MatchString(tl.Items[0].Spec.StorageClasses.AllowedRegex, sc)
-		}
+		return ValidateStorageClass(ctx, c, tl.Items[0].Spec.StorageClasses, pvc.Spec.StorageClassName)
+	}
+}
 
-		if !valid && !matched {
-			return admission.Errored(http.StatusBadRequest, NewStorageClassForbidden(*pvc.Spec.StorageClassName))
+// ValidateStorageClass checks the Storage Class a PVC is going to use against the Tenant ones: the Tenant default
+// Storage Class is the one assigned by the mutating webhook, so the PVCs generated from the StatefulSet templates can
+// be checked before their creation too.
+func ValidateStorageClass(ctx context.Context, c client.Client, spec capsulev1alpha1.StorageClassesSpec, storageClassName *string) admission.Response {
+	var valid, matched bool
+
+	if len(spec.Default) > 0 && (storageClassName == nil || (len(*storageClassName) == 0 && spec.DefaultOnEmpty)) {
+		storageClassName = &spec.Default
+	}
+
+	if storageClassName == nil && spec.RequireExplicitClass {
+		return capsulewebhook.Errored(http.StatusBadRequest, NewStorageClassRequired(spec))
+	}
+
+	// a PVC with no Storage Class is going to use the cluster default one, if any
+	var sc string
+	if storageClassName != nil {
+		sc = *storageClassName
+	}
+	if len(sc) == 0 {
+		var err error
+		if sc, err = defaultStorageClass(ctx, c); err != nil {
+			return capsulewebhook.Errored(http.StatusInternalServerError, err)
 		}
-		return admission.Allowed("")
+		if len(sc) == 0 {
+			return capsulewebhook.Errored(http.StatusBadRequest, NewStorageClassNotValid())
+		}
+	}
+
+	if len(spec.Allowed) > 0 {
+		valid = spec.Allowed.IsStringInList(sc)
+	}
+
+	if len(spec.AllowedRegex) > 0 {
+		matched, _ = regexp.MatchString(spec.AllowedRegex, sc)
+	}
+
+	if !valid && !matched {
+		return capsulewebhook.Errored(http.StatusBadRequest, NewStorageClassForbidden(sc, spec))
+	}
+	return admission.Allowed("")
+}
 
+func defaultStorageClass(ctx context.Context, c client.Client) (string, error) {
+	scl := &storagev1.StorageClassList{}
+	if err := c.List(ctx, scl); err != nil {
+		return "", err
+	}
+	for _, sc := range scl.Items {
+		if sc.GetAnnotations()[defaultStorageClassAnnotation] == "true" {
+			return sc.GetName(), nil
+		}
 	}
+	return "", nil
 }
 
 func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// CaBundleCheck reports the webhook server as not ready when the served certificate is not signed by the CA bundle
+// of the given ValidatingWebhookConfiguration, since the API Server would reject any admission request.
+func CaBundleCheck(c client.Client, configurationName string) healthz.Checker {
+	return func(_ *http.Request) error {
+		dat, err := ioutil.ReadFile(ServingCertificatePath)
+		if err != nil || len(dat) == 0 {
+			return fmt.Errorf("serving certificate is not available yet")
+		}
+		b, _ := pem.Decode(dat)
+		if b == nil {
+			return fmt.Errorf("cannot decode the serving certificate")
+		}
+		var crt *x509.Certificate
+		if crt, err = x509.ParseCertificate(b.Bytes); err != nil {
+			return err
+		}
+
+		vw := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err = c.Get(context.TODO(), types.NamespacedName{Name: configurationName}, vw); err != nil {
+			return err
+		}
+		for _, w := range vw.Webhooks {
+			if w.ClientConfig.Service == nil {
+				continue
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(w.ClientConfig.CABundle) {
+				return fmt.Errorf("webhook %s has no valid CA bundle", w.Name)
+			}
+			if _, err = crt.Verify(x509.VerifyOptions{Roots: pool}); err != nil {
+				return fmt.Errorf("served certificate doesn't match the CA bundle of webhook %s: %w", w.Name, err)
+			}
+		}
+		return nil
+	}
+}
@@ -18,26 +18,42 @@ package registry
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
 )
 
-type registryClassNotValid struct{}
+type containerImageNotValid struct {
+	container string
+}
+
+func NewContainerImageNotValid(container string) error {
+	return &containerImageNotValid{container: container}
+}
+
+func (c containerImageNotValid) Error() string {
+	return fmt.Sprintf("Container %s must reference a valid image", c.container)
+}
 
-func NewRegistryClassNotValid() error {
-	return &registryClassNotValid{}
+func (containerImageNotValid) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonContainerImageNotValid
 }
 
-func (registryClassNotValid) Error() string {
-	return "A valid Registry Class must be used"
+type containerRegistryForbidden struct {
+	container string
+	image     string
+	spec      v1alpha1.ContainerRegistriesSpec
 }
 
-type registryClassForbidden struct {
-	registryClassName string
+func NewContainerRegistryForbidden(container, image string, spec v1alpha1.ContainerRegistriesSpec) error {
+	return &containerRegistryForbidden{container: container, image: image, spec: spec}
 }
 
-func NewregistryClassForbidden(registryClassName string) error {
-	return &registryClassForbidden{registryClassName: registryClassName}
+func (c containerRegistryForbidden) Error() string {
+	return fmt.Sprintf("Container %s image %s is forbidden for the current Tenant: allowed registries are [%s] or images matching the pattern %q", c.container, c.image, strings.Join(c.spec.Allowed, ", "), c.spec.AllowedRegex)
 }
 
-func (f registryClassForbidden) Error() string {
-	return fmt.Sprintf("Registry Class %s is forbidden for the current Tenant", f.registryClassName)
+func (containerRegistryForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonContainerRegistryForbidden
 }
@@ -19,18 +19,18 @@ package registry
 import (
 	"context"
 	"net/http"
-	"regexp"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
-	capsulev1alpha1 "github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/pkg/utils"
 	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
 )
 
-// +kubebuilder:webhook:path=/validating-v1-registry,mutating=false,failurePolicy=fail,groups="",resources=pods,verbs=create,versions=v1,name=pod.capsule.clastix.io
+// +kubebuilder:webhook:path=/validating-v1-registry,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=pods;pods/ephemeralcontainers,verbs=create;update,versions=v1,name=pod.capsule.clastix.io
 
 type webhook struct {
 	handler capsulewebhook.Handler
@@ -61,58 +61,82 @@ func Handler() capsulewebhook.Handler {
 
 func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) admission.Response {
-		var valid, matched bool
-		pod := &v1.Pod{}
+		return h.validate(ctx, c, decoder, req)
+	}
+}
 
-		if err := decoder.Decode(req, pod); err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
-		}
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
 
-		containers := pod.Spec.Containers
+func (h *handler) OnUpdate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return h.validate(ctx, c, decoder, req)
+	}
+}
 
-		for _, container := range containers {
-			if container.Image == "" {
-				return admission.Errored(http.StatusBadRequest, NewRegistryClassNotValid())
-			}
-		}
+// containerImages returns the images referenced by the request, keyed by container name: ephemeral containers are
+// added through their own subresource, that carries an EphemeralContainers object rather than a Pod.
+func (h *handler) containerImages(decoder *admission.Decoder, req admission.Request) (map[string]string, error) {
+	images := make(map[string]string)
 
-		tl := &capsulev1alpha1.TenantList{}
-		if err := c.List(ctx, tl, client.MatchingFieldsSelector{
-			Selector: fields.OneTermEqualSelector(".status.namespaces", pod.Namespace),
-		}); err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+	if req.SubResource == "ephemeralcontainers" {
+		ec := &v1.EphemeralContainers{}
+		if err := decoder.Decode(req, ec); err != nil {
+			return nil, err
 		}
-
-		if len(tl.Items[0].Spec.RegistryClasses.Allowed) > 0 {
-			for _, container := range containers {
-				valid = tl.Items[0].Spec.RegistryClasses.Allowed.IsStringInList(container.Image)
-			}
+		for _, container := range ec.EphemeralContainers {
+			images[container.Name] = container.Image
 		}
+		return images, nil
+	}
 
-		if len(tl.Items[0].Spec.RegistryClasses.Allowed) > 0 {
-			for _, container := range containers {
-				matched, _ = regexp.MatchString(tl.Items[0].Spec.RegistryClasses.AllowedRegex, container.Image)
-			}
-		}
+	pod := &v1.Pod{}
+	if err := decoder.Decode(req, pod); err != nil {
+		return nil, err
+	}
+	for _, container := range pod.Spec.InitContainers {
+		images[container.Name] = container.Image
+	}
+	for _, container := range pod.Spec.Containers {
+		images[container.Name] = container.Image
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		images[container.Name] = container.Image
+	}
+	return images, nil
+}
 
-		if !valid && !matched {
-			for _, container := range containers {
-				return admission.Errored(http.StatusBadRequest, NewregistryClassForbidden(container.Image))
-			}
-		}
-		return admission.Allowed("")
+func (h *handler) validate(ctx context.Context, c client.Client, decoder *admission.Decoder, req admission.Request) admission.Response {
+	images, err := h.containerImages(decoder, req)
+	if err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
+	}
 
+	tl := &v1alpha1.TenantList{}
+	if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+	}); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
 	}
-}
 
-func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
-	return func(ctx context.Context, req admission.Request) admission.Response {
+	if len(tl.Items) == 0 {
 		return admission.Allowed("")
 	}
-}
 
-func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
-	return func(ctx context.Context, req admission.Request) admission.Response {
-		return admission.Allowed("")
+	spec := tl.Items[0].GetContainerRegistries()
+	for name, image := range images {
+		if len(image) == 0 {
+			return capsulewebhook.Errored(http.StatusBadRequest, NewContainerImageNotValid(name))
+		}
+
+		ref := utils.NewImageReference(image)
+		if !spec.IsImageAllowed(ref.Registry, ref.String()) {
+			return capsulewebhook.Errored(http.StatusBadRequest, NewContainerRegistryForbidden(name, ref.String(), spec))
+		}
 	}
+
+	return admission.Allowed("")
 }
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolebinding
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type clusterRoleForbidden struct {
+	clusterRoleName string
+	spec            v1alpha1.ClusterRolesSpec
+}
+
+func NewClusterRoleForbidden(clusterRoleName string, spec v1alpha1.ClusterRolesSpec) error {
+	return &clusterRoleForbidden{clusterRoleName: clusterRoleName, spec: spec}
+}
+
+func (f clusterRoleForbidden) Error() string {
+	if len(f.spec.Allowed) == 0 && len(f.spec.AllowedRegex) == 0 {
+		return fmt.Sprintf("Cluster Role %s cannot be bound in the current Tenant, since no Cluster Role is allowed", f.clusterRoleName)
+	}
+	return fmt.Sprintf("Cluster Role %s cannot be bound in the current Tenant: allowed ones are [%s] or matching the pattern %q", f.clusterRoleName, strings.Join(f.spec.Allowed, ", "), f.spec.AllowedRegex)
+}
+
+func (clusterRoleForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonClusterRoleForbidden
+}
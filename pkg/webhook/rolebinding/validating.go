@@ -0,0 +1,149 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolebinding
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/validating-v1-rolebinding,mutating=false,failurePolicy=fail,sideEffects=None,groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=create;update;delete,versions=v1,name=rolebinding.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+func (w *webhook) GetName() string {
+	return "RoleBinding"
+}
+
+func (w *webhook) GetPath() string {
+	return "/validating-v1-rolebinding"
+}
+
+type handler struct {
+}
+
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+func (r *handler) OnCreate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return r.validateClusterRole(ctx, req, client, decoder)
+	}
+}
+
+// validateClusterRole denies the RoleBindings referring a ClusterRole not allowed by the Tenant, if any is listed or the
+// RoleBindings are restricted: the namespaced Roles are always allowed, since created by the Tenant users in their
+// Namespaces.
+func (r *handler) validateClusterRole(ctx context.Context, req admission.Request, c client.Client, decoder *admission.Decoder) admission.Response {
+	rb := &rbacv1.RoleBinding{}
+	if err := decoder.Decode(req, rb); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
+	}
+	if rb.RoleRef.Kind != "ClusterRole" {
+		return admission.Allowed("")
+	}
+
+	tl := &v1alpha1.TenantList{}
+	if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+	}); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
+	}
+	if len(tl.Items) == 0 {
+		return admission.Allowed("")
+	}
+
+	spec := tl.Items[0].Spec.ClusterRoles
+	if len(spec.Allowed) == 0 && len(spec.AllowedRegex) == 0 && !tl.Items[0].IsRoleBindingRestricted() {
+		return admission.Allowed("")
+	}
+	if spec.Allowed.IsStringInList(rb.RoleRef.Name) {
+		return admission.Allowed("")
+	}
+	if len(spec.AllowedRegex) > 0 {
+		if matched, _ := regexp.MatchString(spec.AllowedRegex, rb.RoleRef.Name); matched {
+			return admission.Allowed("")
+		}
+	}
+	return capsulewebhook.Denied(NewClusterRoleForbidden(rb.RoleRef.Name, spec))
+}
+
+func (r *handler) generic(ctx context.Context, req admission.Request, client client.Client, decoder *admission.Decoder) (bool, error) {
+	var err error
+	rb := &rbacv1.RoleBinding{}
+	err = client.Get(ctx, types.NamespacedName{Namespace: req.AdmissionRequest.Namespace, Name: req.AdmissionRequest.Name}, rb)
+	if err != nil {
+		return false, err
+	}
+
+	return r.isCapsuleRoleBinding(rb), nil
+}
+
+func (r *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		ok, err := r.generic(ctx, req, client, decoder)
+		if err != nil {
+			return capsulewebhook.Errored(http.StatusInternalServerError, err)
+		}
+		if ok {
+			return capsulewebhook.Denied(capsulewebhook.NewError(capsulewebhook.ReasonRoleBindingProtected, "Capsule Role Bindings cannot be deleted: please, reach out the system administrators"))
+		}
+
+		return admission.Allowed("")
+	}
+}
+
+func (r *handler) isCapsuleRoleBinding(rb *rbacv1.RoleBinding) (ok bool) {
+	l, _ := v1alpha1.GetTypeLabel(&v1alpha1.Tenant{})
+	_, ok = rb.GetLabels()[l]
+	return
+}
+
+func (r *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		ok, err := r.generic(ctx, req, client, decoder)
+		if err != nil {
+			return capsulewebhook.Errored(http.StatusInternalServerError, err)
+		}
+		if ok {
+			return capsulewebhook.Denied(capsulewebhook.NewError(capsulewebhook.ReasonRoleBindingProtected, "Capsule Role Bindings cannot be updated: please, reach out the system administrators"))
+		}
+
+		return r.validateClusterRole(ctx, req, client, decoder)
+	}
+}
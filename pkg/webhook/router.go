@@ -27,10 +27,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
+// ServingCertificatePath is the mount path of the webhook serving certificate: the controller-runtime webhook server
+// watches it, reloading the key pair upon the Secret update with no need of a restart.
+const ServingCertificatePath = "/tmp/k8s-webhook-server/serving-certs/tls.crt"
+
+// IsServingCertificateMounted returns true if the serving certificate is available.
+func IsServingCertificateMounted() bool {
+	dat, _ := ioutil.ReadFile(ServingCertificatePath)
+	return len(dat) > 0
+}
+
 func Register(mgr controllerruntime.Manager, webhookList ...Webhook) error {
 	// skipping webhook setup if certificate is missing
-	dat, _ := ioutil.ReadFile("/tmp/k8s-webhook-server/serving-certs/tls.crt")
-	if len(dat) == 0 {
+	if !IsServingCertificateMounted() {
 		return nil
 	}
 
@@ -52,16 +61,18 @@ type handlerRouter struct {
 }
 
 func (r *handlerRouter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var res admission.Response
 	switch req.Operation {
 	case admissionv1beta1.Create:
-		return r.handler.OnCreate(r.client, r.decoder)(ctx, req)
+		res = r.handler.OnCreate(r.client, r.decoder)(ctx, req)
 	case admissionv1beta1.Update:
-		return r.handler.OnUpdate(r.client, r.decoder)(ctx, req)
+		res = r.handler.OnUpdate(r.client, r.decoder)(ctx, req)
 	case admissionv1beta1.Delete:
-		return r.handler.OnDelete(r.client, r.decoder)(ctx, req)
+		res = r.handler.OnDelete(r.client, r.decoder)(ctx, req)
 	default:
 		return admission.Allowed("")
 	}
+	return withAuditAnnotations(res, requestTenant(ctx, r.client, req))
 }
 
 func (r *handlerRouter) InjectClient(c client.Client) error {
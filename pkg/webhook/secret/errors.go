@@ -0,0 +1,40 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"fmt"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type protectedSecretError struct {
+	name      string
+	operation string
+}
+
+func NewProtectedSecretError(name, operation string) error {
+	return &protectedSecretError{name: name, operation: operation}
+}
+
+func (e protectedSecretError) Error() string {
+	return fmt.Sprintf("Secret %s is managed by Capsule and cannot be subject of %s: please, reach out the system administrators", e.name, e.operation)
+}
+
+func (protectedSecretError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonSecretProtected
+}
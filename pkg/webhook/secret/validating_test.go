@@ -0,0 +1,150 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+const (
+	capsuleNamespace = "capsule-system"
+	deploymentName   = "capsule-controller-manager"
+	deploymentUID    = "c4p5u1e"
+)
+
+func newRequest(operation admissionv1beta1.Operation, namespace, name, username string, groups ...string) admission.Request {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: deploymentName, UID: deploymentUID}}, appsv1.SchemeGroupVersion.WithKind("Deployment")),
+			},
+		},
+	}
+	raw, _ := json.Marshal(secret)
+
+	return admission.Request{
+		AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: operation,
+			Namespace: namespace,
+			Name:      name,
+			UserInfo:  authenticationv1.UserInfo{Username: username, Groups: groups},
+			OldObject: runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func newNamespace(terminating bool) *corev1.Namespace {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: capsuleNamespace}}
+	if terminating {
+		now := metav1.Now()
+		ns.DeletionTimestamp = &now
+	}
+	return ns
+}
+
+func newDeployment(terminating bool) *appsv1.Deployment {
+	d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: deploymentName, Namespace: capsuleNamespace, UID: deploymentUID}}
+	if terminating {
+		now := metav1.Now()
+		d.DeletionTimestamp = &now
+	}
+	return d
+}
+
+func TestHandler_OnUpdate(t *testing.T) {
+	type testCase struct {
+		req     admission.Request
+		allowed bool
+	}
+
+	decoder, err := admission.NewDecoder(clientgoscheme.Scheme)
+	assert.NoError(t, err)
+
+	h := Handler(capsuleNamespace, "capsule", "capsule-ca", "capsule-tls")
+	c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, newNamespace(false), newDeployment(false))
+
+	for _, tc := range []testCase{
+		{newRequest(admissionv1beta1.Update, capsuleNamespace, "capsule-tls", "system:serviceaccount:capsule-system:capsule"), true},
+		// the break-glass groups are allowed by the wrapping handler only
+		{newRequest(admissionv1beta1.Update, capsuleNamespace, "capsule-ca", "alice", "system:authenticated", "system:masters"), false},
+		{newRequest(admissionv1beta1.Update, capsuleNamespace, "capsule-ca", "alice", "system:authenticated"), false},
+		{newRequest(admissionv1beta1.Update, capsuleNamespace, "capsule-tls", "system:serviceaccount:oil-production:capsule"), false},
+		{newRequest(admissionv1beta1.Update, capsuleNamespace, "registry-credentials", "alice"), true},
+		{newRequest(admissionv1beta1.Update, "oil-production", "capsule-ca", "alice"), true},
+	} {
+		res := h.OnUpdate(c, decoder)(context.TODO(), tc.req)
+		assert.Equal(t, tc.allowed, res.Allowed, "%s by %s", tc.req.Name, tc.req.UserInfo.Username)
+		if !tc.allowed {
+			assert.Equal(t, metav1.StatusReason(capsulewebhook.ReasonSecretProtected), res.Result.Reason)
+		}
+	}
+}
+
+func TestHandler_OnDelete(t *testing.T) {
+	type testCase struct {
+		objects []runtime.Object
+		req     admission.Request
+		allowed bool
+	}
+
+	decoder, err := admission.NewDecoder(clientgoscheme.Scheme)
+	assert.NoError(t, err)
+
+	h := Handler(capsuleNamespace, "capsule", "capsule-ca", "capsule-tls")
+
+	for name, tc := range map[string]testCase{
+		"denied": {
+			[]runtime.Object{newNamespace(false), newDeployment(false)},
+			newRequest(admissionv1beta1.Delete, capsuleNamespace, "capsule-tls", "alice"),
+			false,
+		},
+		"service account": {
+			[]runtime.Object{newNamespace(false), newDeployment(false)},
+			newRequest(admissionv1beta1.Delete, capsuleNamespace, "capsule-tls", "system:serviceaccount:capsule-system:capsule"),
+			true,
+		},
+		"cluster administrator": {
+			[]runtime.Object{newNamespace(false), newDeployment(false)},
+			newRequest(admissionv1beta1.Delete, capsuleNamespace, "capsule-ca", "alice", "system:masters"),
+			false,
+		},
+		"terminating namespace": {
+			[]runtime.Object{newNamespace(true), newDeployment(false)},
+			newRequest(admissionv1beta1.Delete, capsuleNamespace, "capsule-ca", "system:serviceaccount:kube-system:namespace-controller"),
+			true,
+		},
+		"deleted owner": {
+			[]runtime.Object{newNamespace(false)},
+			newRequest(admissionv1beta1.Delete, capsuleNamespace, "capsule-tls", "system:serviceaccount:kube-system:generic-garbage-collector"),
+			true,
+		},
+		"terminating owner": {
+			[]runtime.Object{newNamespace(false), newDeployment(true)},
+			newRequest(admissionv1beta1.Delete, capsuleNamespace, "capsule-tls", "system:serviceaccount:kube-system:generic-garbage-collector"),
+			true,
+		},
+	} {
+		c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, tc.objects...)
+
+		res := h.OnDelete(c, decoder)(context.TODO(), tc.req)
+		assert.Equal(t, tc.allowed, res.Allowed, name)
+		if !tc.allowed {
+			assert.Equal(t, metav1.StatusReason(capsulewebhook.ReasonSecretProtected), res.Result.Reason, name)
+		}
+	}
+}
@@ -20,9 +20,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/pkg/utils"
 	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
 	"gomodules.xyz/jsonpatch/v2"
 	corev1 "k8s.io/api/core/v1"
@@ -32,7 +32,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-// +kubebuilder:webhook:path=/mutate-v1-service-labels,mutating=true,failurePolicy=ignore,groups="";discovery.k8s.io,resources=services;endpoints;endpointslices,verbs=create;update,versions=v1;v1beta1,name=service.labels.capsule.clastix.io
+// +kubebuilder:webhook:path=/mutate-v1-service-labels,mutating=true,failurePolicy=ignore,sideEffects=None,groups="";discovery.k8s.io,resources=services;endpoints;endpointslices,verbs=create;update,versions=v1;v1beta1,name=service.labels.capsule.clastix.io
 
 type webhook struct {
 	handler capsulewebhook.Handler
@@ -65,7 +65,7 @@ func (h *handler) OnCreate(client client.Client, decoder *admission.Decoder) cap
 	return func(ctx context.Context, req admission.Request) admission.Response {
 		svc, err := h.svcFromRequest(req, decoder)
 		if err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
 		}
 		return h.syncLabels(ctx, client, svc)
 	}
@@ -75,7 +75,7 @@ func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) cap
 	return func(ctx context.Context, req admission.Request) admission.Response {
 		svc, err := h.svcFromRequest(req, decoder)
 		if err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
 		}
 		return h.syncLabels(ctx, client, svc)
 	}
@@ -114,74 +114,35 @@ func (h *handler) svcFromRequest(req admission.Request, decoder *admission.Decod
 }
 
 func (h *handler) syncLabels(ctx context.Context, client client.Client, object ServiceType) admission.Response {
-	var patch []jsonpatch.JsonPatchOperation
-
 	ns := &corev1.Namespace{}
 	tenant := &v1alpha1.Tenant{}
 	if err := client.Get(ctx, types.NamespacedName{Name: object.Namespace()}, ns); err != nil {
-		return admission.Errored(http.StatusBadRequest, err)
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
 	}
 	capsuleLabel, err := v1alpha1.GetTypeLabel(tenant)
 	if err != nil {
-		return admission.Errored(http.StatusBadRequest, err)
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
 	}
 	// not a tenant NS
 	if _, ok := ns.Labels[capsuleLabel]; !ok {
 		return admission.Allowed("")
 	}
 	if err := client.Get(ctx, types.NamespacedName{Name: ns.Labels[capsuleLabel]}, tenant); err != nil {
-		return admission.Errored(http.StatusBadRequest, err)
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
 	}
 
-	if tenant.Spec.ServicesMetadata.AdditionalLabels == nil && tenant.Spec.ServicesMetadata.AdditionalAnnotations == nil {
+	// tracking the applied metadata, the keys no more in the Tenant spec are removed: the Services metadata controller
+	// relies on these to keep the existing objects in sync upon the Tenant changes
+	if !utils.SyncObjectMetadata(object, tenant.Spec.ServicesMetadata) {
 		return admission.Allowed("")
 	}
-
-	availableLables := object.Labels()
-	availableLAnnotations := object.Annotations()
-
-	if al := tenant.Spec.ServicesMetadata.AdditionalLabels; al != nil {
-		if availableLables == nil {
-			patch = append(patch, jsonpatch.JsonPatchOperation{
-				Operation: "add",
-				Path:      "/metadata/labels",
-				Value:     al,
-			})
-		} else {
-			for key, value := range al {
-				if availableLables[key] != value {
-					patch = append(patch, jsonpatch.JsonPatchOperation{
-						Operation: "replace",
-						Path:      "/metadata/labels/" + strings.ReplaceAll(key, "/", "~1"), // http://jsonpatch.com/#json-pointer
-						Value:     value,
-					})
-				}
-			}
-		}
-	}
-
-	if aa := tenant.Spec.ServicesMetadata.AdditionalAnnotations; aa != nil {
-		if availableLAnnotations == nil {
-			patch = append(patch, jsonpatch.JsonPatchOperation{
-				Operation: "add",
-				Path:      "/metadata/annotations",
-				Value:     aa,
-			})
-		} else {
-			for key, value := range aa {
-				if availableLAnnotations[key] != value {
-					patch = append(patch, jsonpatch.JsonPatchOperation{
-						Operation: "replace",
-						Path:      "/metadata/annotations/" + strings.ReplaceAll(key, "/", "~1"), // http://jsonpatch.com/#json-pointer
-						Value:     value,
-					})
-				}
-			}
-		}
-	}
-
-	if len(patch) > 0 {
-		return admission.Patched("Updating labels and annotations", patch...)
-	}
-	return admission.Allowed("")
+	return admission.Patched("Updating labels and annotations", jsonpatch.JsonPatchOperation{
+		Operation: "add",
+		Path:      "/metadata/labels",
+		Value:     object.GetLabels(),
+	}, jsonpatch.JsonPatchOperation{
+		Operation: "add",
+		Path:      "/metadata/annotations",
+		Value:     object.GetAnnotations(),
+	})
 }
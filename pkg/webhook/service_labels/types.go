@@ -19,9 +19,11 @@ package service_labels
 import (
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type ServiceType interface {
+	metav1.Object
 	Namespace() string
 	Labels() map[string]string
 	Annotations() map[string]string
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type externalIPForbidden struct {
+	ip      string
+	allowed []string
+}
+
+func NewExternalIPForbidden(ip string, allowed []string) error {
+	return &externalIPForbidden{ip: ip, allowed: allowed}
+}
+
+func (e externalIPForbidden) Error() string {
+	if len(e.allowed) == 0 {
+		return fmt.Sprintf("Service external IP %s is forbidden for the current Tenant, since no external IPs are allowed", e.ip)
+	}
+	return fmt.Sprintf("Service external IP %s is forbidden for the current Tenant: it doesn't belong to the allowed CIDRs [%s]", e.ip, strings.Join(e.allowed, ", "))
+}
+
+func (externalIPForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonExternalIPForbidden
+}
+
+type serviceTypeForbidden struct {
+	serviceType corev1.ServiceType
+	allowed     []string
+}
+
+func NewServiceTypeForbidden(serviceType corev1.ServiceType, allowed []string) error {
+	return &serviceTypeForbidden{serviceType: serviceType, allowed: allowed}
+}
+
+func (s serviceTypeForbidden) Error() string {
+	return fmt.Sprintf("%s Services are forbidden for the current Tenant: the allowed types are [%s]", s.serviceType, strings.Join(s.allowed, ", "))
+}
+
+func (serviceTypeForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonServiceTypeForbidden
+}
+
+type nodePortDisabled struct {
+	tenant string
+}
+
+func NewNodePortDisabled(tenant string) error {
+	return &nodePortDisabled{tenant: tenant}
+}
+
+func (n nodePortDisabled) Error() string {
+	return fmt.Sprintf("NodePort Services are forbidden by the %s Tenant policy: please, reach out the system administrators", n.tenant)
+}
+
+func (nodePortDisabled) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonNodePortDisabled
+}
+
+type loadBalancerDisabled struct {
+	tenant  string
+	allowed map[string]string
+}
+
+func NewLoadBalancerDisabled(tenant string, allowed map[string]string) error {
+	return &loadBalancerDisabled{tenant: tenant, allowed: allowed}
+}
+
+func (l loadBalancerDisabled) Error() string {
+	if len(l.allowed) == 0 {
+		return fmt.Sprintf("LoadBalancer Services are forbidden by the %s Tenant policy: please, reach out the system administrators", l.tenant)
+	}
+	var annotations []string
+	for k, v := range l.allowed {
+		annotations = append(annotations, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(annotations)
+	return fmt.Sprintf("LoadBalancer Services are forbidden by the %s Tenant policy, unless using any of the annotations [%s]", l.tenant, strings.Join(annotations, ", "))
+}
+
+func (loadBalancerDisabled) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonLoadBalancerDisabled
+}
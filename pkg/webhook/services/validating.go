@@ -0,0 +1,242 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/validating-v1-service,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=services,verbs=create;update,versions=v1,name=validating.service.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetName() string {
+	return "Services"
+}
+
+func (w *webhook) GetPath() string {
+	return "/validating-v1-service"
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+type handler struct {
+	tolerateExisting bool
+}
+
+// Handler validates the Services against the Tenant policies upon creation and update: when tolerating the existing
+// violations, an update is denied only if introducing a new one, so the Services predating a stricter policy can still
+// be updated or have their finalizers removed.
+func Handler(tolerateExisting bool) capsulewebhook.Handler {
+	return &handler{
+		tolerateExisting: tolerateExisting,
+	}
+}
+
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return h.validate(ctx, c, decoder, req, nil)
+	}
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnUpdate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		old := &corev1.Service{}
+		if err := decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+		return h.validate(ctx, c, decoder, req, old)
+	}
+}
+
+// validate checks the Service against the Tenant policies: upon update, the old Service is considered only when
+// tolerating the existing violations.
+func (h *handler) validate(ctx context.Context, c client.Client, decoder *admission.Decoder, req admission.Request, old *corev1.Service) admission.Response {
+	svc := &corev1.Service{}
+	if err := decoder.Decode(req, svc); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
+	}
+
+	tl := &v1alpha1.TenantList{}
+	if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+	}); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
+	}
+
+	// the Namespace doesn't belong to any Tenant
+	if len(tl.Items) == 0 {
+		return admission.Allowed("")
+	}
+
+	if !h.tolerateExisting {
+		old = nil
+	}
+
+	tnt := tl.Items[0]
+	if (old == nil || old.Spec.Type != svc.Spec.Type) && !tnt.IsServiceTypeAllowed(serviceType(svc)) {
+		var allowed []string
+		for _, t := range tnt.Spec.ServiceOptions.AllowedTypes {
+			allowed = append(allowed, string(t))
+		}
+		return capsulewebhook.Errored(http.StatusBadRequest, NewServiceTypeForbidden(serviceType(svc), allowed))
+	}
+	if err := validateExternalIPs(tnt.Spec.ExternalServiceIPs, addedExternalIPs(old, svc)); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
+	}
+	if !tnt.IsNodePortsEnabled() && requestsNodePorts(svc) && (old == nil || addsNodePorts(old, svc)) {
+		return capsulewebhook.Errored(http.StatusBadRequest, NewNodePortDisabled(tnt.GetName()))
+	}
+	if !tnt.IsLoadBalancersEnabled() && isForbiddenLoadBalancer(tnt, svc) && (old == nil || !isForbiddenLoadBalancer(tnt, old)) {
+		return capsulewebhook.Errored(http.StatusBadRequest, NewLoadBalancerDisabled(tnt.GetName(), tnt.Spec.LoadBalancerAnnotations))
+	}
+	return admission.Allowed("")
+}
+
+// addedExternalIPs returns the external IPs of the Service missing in the old one, all of them upon creation.
+func addedExternalIPs(old, svc *corev1.Service) (added []string) {
+	if old == nil {
+		return svc.Spec.ExternalIPs
+	}
+	existing := make(map[string]struct{}, len(old.Spec.ExternalIPs))
+	for _, ip := range old.Spec.ExternalIPs {
+		existing[ip] = struct{}{}
+	}
+	for _, ip := range svc.Spec.ExternalIPs {
+		if _, ok := existing[ip]; !ok {
+			added = append(added, ip)
+		}
+	}
+	return
+}
+
+// addsNodePorts returns true if the Service is turned into a NodePort one, or explicitly requests a node port the old
+// one didn't.
+func addsNodePorts(old, svc *corev1.Service) bool {
+	if !requestsNodePorts(old) {
+		return true
+	}
+	if svc.Spec.Type == corev1.ServiceTypeNodePort && old.Spec.Type != corev1.ServiceTypeNodePort {
+		return true
+	}
+	existing := make(map[int32]struct{}, len(old.Spec.Ports))
+	for _, port := range old.Spec.Ports {
+		existing[port.NodePort] = struct{}{}
+	}
+	for _, port := range svc.Spec.Ports {
+		if _, ok := existing[port.NodePort]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isForbiddenLoadBalancer returns true for the LoadBalancer Services lacking any of the Tenant allowed annotations.
+func isForbiddenLoadBalancer(tnt v1alpha1.Tenant, svc *corev1.Service) bool {
+	return svc.Spec.Type == corev1.ServiceTypeLoadBalancer && !hasAllowedAnnotation(tnt.Spec.LoadBalancerAnnotations, svc.GetAnnotations())
+}
+
+// serviceType returns the Service type, ClusterIP if missing as defaulted by the API server.
+func serviceType(svc *corev1.Service) corev1.ServiceType {
+	if len(svc.Spec.Type) == 0 {
+		return corev1.ServiceTypeClusterIP
+	}
+	return svc.Spec.Type
+}
+
+// requestsNodePorts returns true for the NodePort Services, and the other ones explicitly requesting a node port: the
+// node ports allocated to the LoadBalancer Services are not considered.
+func requestsNodePorts(svc *corev1.Service) bool {
+	switch svc.Spec.Type {
+	case corev1.ServiceTypeNodePort:
+		return true
+	case corev1.ServiceTypeLoadBalancer:
+		return false
+	}
+	for _, port := range svc.Spec.Ports {
+		if port.NodePort != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAllowedAnnotation returns true if the Service has any of the allowed annotations, with the same value: these
+// usually restrict the load balancer to the internal network, so the Tenant isn't blocked entirely.
+func hasAllowedAnnotation(allowed, annotations map[string]string) bool {
+	for k, v := range allowed {
+		if value, ok := annotations[k]; ok && value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// validateExternalIPs checks each external IP belongs to any of the allowed CIDRs: without these, external IPs are
+// forbidden since these could intercept the traffic to arbitrary addresses.
+func validateExternalIPs(spec *v1alpha1.ExternalServiceIPsSpec, externalIPs []string) error {
+	if len(externalIPs) == 0 {
+		return nil
+	}
+
+	var allowed []string
+	if spec != nil {
+		allowed = spec.Allowed
+	}
+
+	for _, ip := range externalIPs {
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			return NewExternalIPForbidden(ip, allowed)
+		}
+		var ok bool
+		for _, cidr := range allowed {
+			if _, n, err := net.ParseCIDR(cidr); err == nil && n.Contains(parsed) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return NewExternalIPForbidden(ip, allowed)
+		}
+	}
+	return nil
+}
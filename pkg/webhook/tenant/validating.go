@@ -18,17 +18,26 @@ package tenant
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"net/http"
 	"regexp"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/pkg/utils"
 	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/object_quota"
 )
 
-// +kubebuilder:webhook:path=/validating-v1-tenant,mutating=false,failurePolicy=fail,groups="capsule.clastix.io",resources=tenants,verbs=create,versions=v1alpha1,name=tenant.capsule.clastix.io
+// +kubebuilder:webhook:path=/validating-v1-tenant,mutating=false,failurePolicy=fail,sideEffects=None,groups="capsule.clastix.io",resources=tenants,verbs=create;update,versions=v1alpha1,name=tenant.capsule.clastix.io
 
 type webhook struct {
 	handler capsulewebhook.Handler
@@ -61,30 +70,239 @@ func (r *handler) OnCreate(client client.Client, decoder *admission.Decoder) cap
 	return func(ctx context.Context, req admission.Request) admission.Response {
 		tnt := &v1alpha1.Tenant{}
 		if err := decoder.Decode(req, tnt); err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
 		}
 
-		matched, _ := regexp.MatchString(`^[a-z0-9]([a-z0-9]*[a-z0-9])?$`, tnt.GetName())
-		if !matched {
-			return admission.Denied("Tenant name has forbidden characters")
+		return r.validate(tnt)
+	}
+}
+
+func (r *handler) validate(tnt *v1alpha1.Tenant) admission.Response {
+	matched, _ := regexp.MatchString(`^[a-z0-9]([a-z0-9]*[a-z0-9])?$`, tnt.GetName())
+	if !matched {
+		return notValid("Tenant name has forbidden characters")
+	}
+
+	// Validate owners name, the empty ones would be silently skipped when granting the permissions
+	if len(tnt.Spec.Owner.Name) == 0 {
+		return notValid("spec.owner.name must not be empty")
+	}
+	for i, o := range tnt.Spec.Owners {
+		if len(o.Name) == 0 {
+			return notValid("spec.owners[%d].name must not be empty", i)
 		}
+	}
+
+	// Validate ServiceAccount owners name
+	for _, o := range tnt.GetOwners() {
+		if o.Kind != "ServiceAccount" {
+			continue
+		}
+		if _, _, err := o.GetServiceAccountNamespacedName(); err != nil {
+			return notValid("%s", err)
+		}
+	}
 
-		// Validate ingressClasses regexp
-		if len(tnt.Spec.IngressClasses.AllowedRegex) > 0 {
-			if _, err := regexp.Compile(tnt.Spec.IngressClasses.AllowedRegex); err != nil {
-				return admission.Denied("Unable to compile ingressClasses allowedRegex")
+	// Validate resourceQuotas, a resource cannot be limited twice with the same scopes and the extended ones must be
+	// fully-qualified requests
+	for i, rq := range tnt.Spec.ResourceQuota {
+		for rn := range rq.Hard {
+			if err := validateExtendedQuotaResource(rn); err != nil {
+				return notValid("spec.resourceQuotas[%d].hard.%s is not valid: %s", i, rn, err.Error())
 			}
 		}
+		for j := 0; j < i; j++ {
+			if !sameQuotaScopes(tnt.Spec.ResourceQuota[j], rq) {
+				continue
+			}
+			for rn := range rq.Hard {
+				if _, ok := tnt.Spec.ResourceQuota[j].Hard[rn]; ok {
+					return notValid("spec.resourceQuotas[%d].hard.%s is already defined by spec.resourceQuotas[%d] with the same scopes", i, rn, j)
+				}
+			}
+		}
+	}
 
-		// Validate storageClasses regexp
-		if len(tnt.Spec.StorageClasses.AllowedRegex) > 0 {
-			if _, err := regexp.Compile(tnt.Spec.StorageClasses.AllowedRegex); err != nil {
-				return admission.Denied("Unable to compile storageClasses allowedRegex")
+	// Validate objectQuota resources, the count is capped only for the supported ones
+	for rn := range tnt.Spec.ObjectQuota {
+		if !object_quota.IsSupportedResource(rn) {
+			return notValid("spec.objectQuota.%s is not a supported resource", rn)
+		}
+	}
+
+	// Validate rewriteImagePullPolicy, the image pull policy is rewritten only to a single allowed one
+	if tnt.Spec.RewriteImagePullPolicy && len(tnt.Spec.ImagePullPolicies) != 1 {
+		return notValid("spec.rewriteImagePullPolicy requires exactly one spec.imagePullPolicies entry")
+	}
+
+	// Validate podDisruptionBudget, the PodDisruptionBudget spec accepts only one of the bounds
+	if pdb := tnt.Spec.PodDisruptionBudget; pdb != nil {
+		if (pdb.MinAvailable == nil) == (pdb.MaxUnavailable == nil) {
+			return notValid("spec.podDisruptionBudget requires exactly one of minAvailable or maxUnavailable")
+		}
+	}
+
+	// Validate imagePullSecrets, the replicas keep the source name so it must be unique
+	secrets := make(map[string]struct{}, len(tnt.Spec.ImagePullSecrets))
+	for i, ref := range tnt.Spec.ImagePullSecrets {
+		if _, ok := secrets[ref.Name]; ok {
+			return notValid("spec.imagePullSecrets[%d].name %s is already in use", i, ref.Name)
+		}
+		secrets[ref.Name] = struct{}{}
+	}
+
+	// Validate additionalResources, each item is a named object with a unique key, and an object cannot be seeded twice
+	names, objects := make(map[string]struct{}), make(map[string]string)
+	for i, item := range tnt.Spec.AdditionalResources {
+		if _, ok := names[item.Name]; ok {
+			return notValid("spec.additionalResources[%d].name %s is already in use", i, item.Name)
+		}
+		names[item.Name] = struct{}{}
+
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(item.Object.Raw); err != nil {
+			return notValid("spec.additionalResources[%d].object cannot be decoded: %s", i, err.Error())
+		}
+		if len(obj.GetName()) == 0 {
+			return notValid("spec.additionalResources[%d].object requires the metadata name", i)
+		}
+		key := obj.GroupVersionKind().GroupKind().String() + "/" + obj.GetName()
+		if name, ok := objects[key]; ok {
+			return notValid("spec.additionalResources[%d].object is already seeded by %s", i, name)
+		}
+		objects[key] = item.Name
+	}
+
+	// Validate ingressClasses regexp
+	if len(tnt.Spec.IngressClasses.AllowedRegex) > 0 {
+		if _, err := regexp.Compile(tnt.Spec.IngressClasses.AllowedRegex); err != nil {
+			return notValid("Unable to compile ingressClasses allowedRegex: %s", err.Error())
+		}
+	}
+
+	// Validate ingressClasses default class, since assigned to the Ingresses it must be allowed
+	if c := tnt.Spec.IngressClasses.Default; len(c) > 0 {
+		allowed := tnt.Spec.IngressClasses.Allowed.IsStringInList(c)
+		if !allowed && len(tnt.Spec.IngressClasses.AllowedRegex) > 0 {
+			allowed, _ = regexp.MatchString(tnt.Spec.IngressClasses.AllowedRegex, c)
+		}
+		if !allowed {
+			return notValid("ingressClasses default %s must be allowed", c)
+		}
+	}
+
+	// Validate storageClasses default class, since assigned to the PersistentVolumeClaims it must be allowed
+	if c := tnt.Spec.StorageClasses.Default; len(c) > 0 {
+		allowed := tnt.Spec.StorageClasses.Allowed.IsStringInList(c)
+		if !allowed && len(tnt.Spec.StorageClasses.AllowedRegex) > 0 {
+			allowed, _ = regexp.MatchString(tnt.Spec.StorageClasses.AllowedRegex, c)
+		}
+		if !allowed {
+			return notValid("storageClasses default %s must be allowed", c)
+		}
+	}
+
+	// Validate ingressHostnames regexp
+	if len(tnt.Spec.IngressHostnames.AllowedRegex) > 0 {
+		if _, err := regexp.Compile(tnt.Spec.IngressHostnames.AllowedRegex); err != nil {
+			return notValid("Unable to compile ingressHostnames allowedRegex: %s", err.Error())
+		}
+	}
+
+	// Validate containerRegistries regexp
+	if len(tnt.Spec.ContainerRegistries.AllowedRegex) > 0 {
+		if _, err := regexp.Compile(tnt.Spec.ContainerRegistries.AllowedRegex); err != nil {
+			return notValid("Unable to compile containerRegistries allowedRegex: %s", err.Error())
+		}
+	}
+	// Validate containerRegistries default registry
+	if r := tnt.Spec.ContainerRegistries.DefaultRegistry; len(r) > 0 && !utils.HasRegistry(r+"/image") {
+		return notValid("containerRegistries defaultRegistry %s must start with a registry hostname", r)
+	}
+	// Validate priorityClasses regexp
+	if len(tnt.Spec.PriorityClasses.AllowedRegex) > 0 {
+		if _, err := regexp.Compile(tnt.Spec.PriorityClasses.AllowedRegex); err != nil {
+			return notValid("Unable to compile priorityClasses allowedRegex: %s", err.Error())
+		}
+	}
+	// Validate externalServiceIPs CIDRs
+	if spec := tnt.Spec.ExternalServiceIPs; spec != nil {
+		for _, cidr := range spec.Allowed {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return notValid("Unable to parse externalServiceIPs allowed CIDR: %s", err.Error())
 			}
 		}
+	}
+	// Validate namespaceOptions forbidden and allowed regexps
+	if options := tnt.Spec.NamespaceOptions; options != nil {
+		for kind, spec := range map[string]v1alpha1.ForbiddenListSpec{"forbiddenLabels": options.ForbiddenLabels, "forbiddenAnnotations": options.ForbiddenAnnotations} {
+			if len(spec.DeniedRegex) == 0 {
+				continue
+			}
+			if _, err := regexp.Compile(spec.DeniedRegex); err != nil {
+				return notValid("Unable to compile namespaceOptions %s deniedRegex: %s", kind, err.Error())
+			}
+		}
+		for kind, spec := range map[string]v1alpha1.AllowedListSpec{"allowedLabels": options.AllowedLabels, "allowedAnnotations": options.AllowedAnnotations} {
+			if len(spec.AllowedRegex) == 0 {
+				continue
+			}
+			if _, err := regexp.Compile(spec.AllowedRegex); err != nil {
+				return notValid("Unable to compile namespaceOptions %s allowedRegex: %s", kind, err.Error())
+			}
+		}
+	}
+	// Validate storageClasses regexp
+	if len(tnt.Spec.StorageClasses.AllowedRegex) > 0 {
+		if _, err := regexp.Compile(tnt.Spec.StorageClasses.AllowedRegex); err != nil {
+			return notValid("Unable to compile storageClasses allowedRegex: %s", err.Error())
+		}
+	}
+	// Validate clusterRoles regexp
+	if len(tnt.Spec.ClusterRoles.AllowedRegex) > 0 {
+		if _, err := regexp.Compile(tnt.Spec.ClusterRoles.AllowedRegex); err != nil {
+			return notValid("Unable to compile clusterRoles allowedRegex: %s", err.Error())
+		}
+	}
 
-		return admission.Allowed("")
+	return admission.Allowed("")
+}
+
+// notValid denies the Tenant failing the validation.
+func notValid(format string, args ...interface{}) admission.Response {
+	return capsulewebhook.Denied(capsulewebhook.NewError(capsulewebhook.ReasonTenantNotValid, format, args...))
+}
+
+func sameQuotaScopes(a, b corev1.ResourceQuotaSpec) bool {
+	return equality.Semantic.DeepEqual(a.Scopes, b.Scopes) && equality.Semantic.DeepEqual(a.ScopeSelector, b.ScopeSelector)
+}
+
+// validateExtendedQuotaResource checks the quota of the extended resources, as nvidia.com/gpu, that can be limited
+// only by the requests, as requests.nvidia.com/gpu, since their limits equal the requests. The native resources, the
+// object counts, and the storage class ones are left to the API Server validation.
+func validateExtendedQuotaResource(rn corev1.ResourceName) error {
+	name := rn.String()
+	if !strings.Contains(name, "/") || strings.HasPrefix(name, "count/") || strings.Contains(name, ".storageclass.storage.k8s.io/") {
+		return nil
+	}
+
+	var extended string
+	switch {
+	case strings.HasPrefix(name, "requests."):
+		extended = strings.TrimPrefix(name, "requests.")
+	case strings.HasPrefix(name, "limits."):
+		return fmt.Errorf("the extended resources can be limited only by the requests, as requests.%s", strings.TrimPrefix(name, "limits."))
+	default:
+		return fmt.Errorf("the extended resources can be limited only by the requests, as requests.%s", name)
+	}
+
+	if errs := validation.IsQualifiedName(extended); len(errs) > 0 {
+		return fmt.Errorf("%s is not a fully-qualified resource name: %s", extended, strings.Join(errs, ", "))
 	}
+	if strings.Contains(extended, "kubernetes.io/") {
+		return fmt.Errorf("%s is not an extended resource, since in the kubernetes.io domain", extended)
+	}
+	return nil
 }
 
 func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
@@ -95,6 +313,25 @@ func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) cap
 
 func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
 	return func(ctx context.Context, req admission.Request) admission.Response {
-		return admission.Allowed("")
+		tnt := &v1alpha1.Tenant{}
+		if err := decoder.Decode(req, tnt); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		old := &v1alpha1.Tenant{}
+		if err := decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+		// changing the owner kind grants the Tenant to a different subject, it must be acknowledged
+		if k := tnt.Spec.Owner.Kind; k != old.Spec.Owner.Kind && tnt.GetAnnotations()[v1alpha1.OwnerKindChangeAnnotation] != k.String() {
+			return notValid("spec.owner.kind cannot be changed from %s to %s without the %s=%s annotation", old.Spec.Owner.Kind, k, v1alpha1.OwnerKindChangeAnnotation, k)
+		}
+
+		res := h.validate(tnt)
+		// the Namespaces exceeding the lowered quota are retained, although the new ones are denied
+		if res.Allowed && tnt.Status.Namespaces.Len() > int(tnt.Spec.NamespaceQuota) {
+			return admission.Allowed(fmt.Sprintf("The Namespace quota %d is below the %d Namespaces in use: these are retained, although the creation of new ones is denied", tnt.Spec.NamespaceQuota, tnt.Status.Namespaces.Len()))
+		}
+		return res
 	}
 }
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant_prefix
+
+import (
+	"fmt"
+	"regexp"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type protectedNamespaceError struct {
+	regexp *regexp.Regexp
+}
+
+func NewProtectedNamespaceError(regexp *regexp.Regexp) error {
+	return &protectedNamespaceError{regexp: regexp}
+}
+
+func (p protectedNamespaceError) Error() string {
+	return fmt.Sprintf("Creating namespaces with name matching %q regexp is not allowed; please, reach out the system administrators", p.regexp.String())
+}
+
+func (protectedNamespaceError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonNamespaceNameProtected
+}
@@ -19,7 +19,6 @@ package tenant_prefix
 import (
 	"context"
 	"net/http"
-	"regexp"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -28,10 +27,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/pkg/configuration"
 	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
 )
 
-// +kubebuilder:webhook:path=/validating-v1-namespace-tenant-prefix,mutating=false,failurePolicy=fail,groups="",resources=namespaces,verbs=create,versions=v1,name=prefix.namespace.capsule.clastix.io
+// +kubebuilder:webhook:path=/validating-v1-namespace-tenant-prefix,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=namespaces,verbs=create,versions=v1,name=prefix.namespace.capsule.clastix.io
 
 type webhook struct {
 	handler capsulewebhook.Handler
@@ -56,14 +56,12 @@ func (w *webhook) GetPath() string {
 }
 
 type handler struct {
-	forceTenantPrefix        bool
-	protectedNamespacesRegex *regexp.Regexp
+	configuration *configuration.Store
 }
 
-func Handler(forceTenantPrefix bool, protectedNamespacesRegex *regexp.Regexp) capsulewebhook.Handler {
+func Handler(configuration *configuration.Store) capsulewebhook.Handler {
 	return &handler{
-		forceTenantPrefix:        forceTenantPrefix,
-		protectedNamespacesRegex: protectedNamespacesRegex,
+		configuration: configuration,
 	}
 }
 
@@ -71,33 +69,29 @@ func (r *handler) OnCreate(clt client.Client, decoder *admission.Decoder) capsul
 	return func(ctx context.Context, req admission.Request) admission.Response {
 		ns := &corev1.Namespace{}
 		if err := decoder.Decode(req, ns); err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
 		}
-		if r.protectedNamespacesRegex != nil {
-			if matched := r.protectedNamespacesRegex.MatchString(ns.GetName()); matched {
-				return admission.Denied("Creating namespaces with name matching " + r.protectedNamespacesRegex.String() + " regexp is not allowed; please, reach out the system administrators")
+		cfg := r.configuration.FromContext(ctx)
+		if cfg.ProtectedNamespaceRegexp != nil {
+			if matched := cfg.ProtectedNamespaceRegexp.MatchString(ns.GetName()); matched {
+				return capsulewebhook.Denied(NewProtectedNamespaceError(cfg.ProtectedNamespaceRegexp))
 			}
 		}
 
-		if !r.forceTenantPrefix {
-			return admission.Allowed("")
-		}
-
-		t := &v1alpha1.Tenant{}
 		for _, or := range ns.ObjectMeta.OwnerReferences {
+			if or.Kind != "Tenant" {
+				continue
+			}
 			// retrieving the selected Tenant
+			t := &v1alpha1.Tenant{}
 			if err := clt.Get(ctx, types.NamespacedName{Name: or.Name}, t); err != nil {
-				return admission.Errored(http.StatusBadRequest, err)
+				return capsulewebhook.Errored(http.StatusBadRequest, err)
 			}
-			t := &v1alpha1.Tenant{}
-			for _, or := range ns.ObjectMeta.OwnerReferences {
-				// retrieving the selected Tenant
-				if err := clt.Get(ctx, types.NamespacedName{Name: or.Name}, t); err != nil {
-					return admission.Errored(http.StatusBadRequest, err)
-				}
+			if !cfg.ForceTenantPrefix && !t.Spec.ForceTenantPrefix {
+				continue
 			}
 			if e := t.GetName() + "-" + ns.GetName(); !strings.HasPrefix(ns.GetName(), t.GetName()+"-") {
-				return admission.Denied("The namespace doesn't match the tenant prefix, expected " + e)
+				return capsulewebhook.Denied(capsulewebhook.NewError(capsulewebhook.ReasonNamespacePrefixRequired, "The namespace doesn't match the tenant prefix, expected %s", e))
 			}
 		}
 		return admission.Allowed("")
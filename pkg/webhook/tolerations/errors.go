@@ -0,0 +1,41 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tolerations
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type tolerationForbidden struct {
+	toleration corev1.Toleration
+}
+
+func NewTolerationForbidden(toleration corev1.Toleration) error {
+	return &tolerationForbidden{toleration: toleration}
+}
+
+func (t tolerationForbidden) Error() string {
+	return fmt.Sprintf("Toleration %s=%s:%s is forbidden for the current Tenant, since not listed in the Tenant ones", t.toleration.Key, t.toleration.Value, t.toleration.Effect)
+}
+
+func (tolerationForbidden) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonTolerationForbidden
+}
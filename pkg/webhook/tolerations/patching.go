@@ -0,0 +1,147 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tolerations
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"gomodules.xyz/jsonpatch/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+// Tolerations for the taints set by the node lifecycle, as the ones added by the DefaultTolerationSeconds admission
+// plugin and the DaemonSet controller, are always allowed.
+const nodeTaintPrefix = "node.kubernetes.io/"
+
+// +kubebuilder:webhook:path=/mutate-v1-pod-tolerations,mutating=true,failurePolicy=fail,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=tolerations.pod.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+func (w *webhook) GetName() string {
+	return "Tolerations"
+}
+
+func (w *webhook) GetPath() string {
+	return "/mutate-v1-pod-tolerations"
+}
+
+type handler struct {
+}
+
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+// OnCreate appends the Tenant tolerations missing from the Pod: when enforced, the Pod tolerations not listed in the
+// Tenant ones are denied.
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		pod := &corev1.Pod{}
+		if err := decoder.Decode(req, pod); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		tl := &v1alpha1.TenantList{}
+		if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+		}); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+
+		if len(tl.Items) == 0 {
+			return admission.Allowed("")
+		}
+
+		spec := tl.Items[0].Spec
+		if spec.EnforceTolerations {
+			for i := range pod.Spec.Tolerations {
+				if t := pod.Spec.Tolerations[i]; !strings.HasPrefix(t.Key, nodeTaintPrefix) && !containsToleration(spec.Tolerations, t) {
+					return capsulewebhook.Denied(NewTolerationForbidden(t))
+				}
+			}
+		}
+
+		if len(spec.Tolerations) == 0 {
+			return admission.Allowed("")
+		}
+
+		if pod.Spec.Tolerations == nil {
+			return admission.Patched("Appending the Tenant tolerations", jsonpatch.JsonPatchOperation{
+				Operation: "add",
+				Path:      "/spec/tolerations",
+				Value:     spec.Tolerations,
+			})
+		}
+
+		var patch []jsonpatch.JsonPatchOperation
+		for _, t := range spec.Tolerations {
+			if containsToleration(pod.Spec.Tolerations, t) {
+				continue
+			}
+			patch = append(patch, jsonpatch.JsonPatchOperation{
+				Operation: "add",
+				Path:      "/spec/tolerations/-",
+				Value:     t,
+			})
+		}
+
+		if len(patch) > 0 {
+			return admission.Patched("Appending the Tenant tolerations", patch...)
+		}
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+// containsToleration checks if the toleration is in the list, matching key, operator, value, and effect.
+func containsToleration(tolerations []corev1.Toleration, toleration corev1.Toleration) bool {
+	for i := range tolerations {
+		if tolerations[i].MatchToleration(&toleration) {
+			return true
+		}
+	}
+	return false
+}
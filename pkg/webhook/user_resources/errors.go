@@ -0,0 +1,40 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package user_resources
+
+import (
+	"fmt"
+
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+)
+
+type userResourceForbiddenError struct {
+	kind   string
+	tenant string
+}
+
+func NewUserResourceForbiddenError(kind, tenant string) error {
+	return &userResourceForbiddenError{kind: kind, tenant: tenant}
+}
+
+func (u userResourceForbiddenError) Error() string {
+	return fmt.Sprintf("The %s resources are managed by Capsule from the Tenant %s spec and cannot be created: please, reach out the system administrators", u.kind, u.tenant)
+}
+
+func (userResourceForbiddenError) Reason() capsulewebhook.Reason {
+	return capsulewebhook.ReasonUserResourceForbidden
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/pkg/configuration"
+	"github.com/clastix/capsule/pkg/utils"
+	"github.com/clastix/capsule/pkg/webhook"
+)
+
+// BreakGlass allows the requests of the members of the break-glass groups denied by the validating webhook handler,
+// so the platform operators can act during the incidents: every overridden denial is logged and recorded as a Warning
+// event on the requested object.
+func BreakGlass(configuration *configuration.Store, recorder record.EventRecorder, log logr.Logger, webhookHandler webhook.Handler) webhook.Handler {
+	return &breakGlassHandler{
+		configuration: configuration,
+		recorder:      recorder,
+		log:           log,
+		handler:       webhookHandler,
+	}
+}
+
+type breakGlassHandler struct {
+	configuration *configuration.Store
+	recorder      record.EventRecorder
+	log           logr.Logger
+	handler       webhook.Handler
+}
+
+func (h *breakGlassHandler) OnCreate(client client.Client, decoder *admission.Decoder) webhook.Func {
+	return h.allowBreakGlass(h.handler.OnCreate(client, decoder))
+}
+
+func (h *breakGlassHandler) OnDelete(client client.Client, decoder *admission.Decoder) webhook.Func {
+	return h.allowBreakGlass(h.handler.OnDelete(client, decoder))
+}
+
+func (h *breakGlassHandler) OnUpdate(client client.Client, decoder *admission.Decoder) webhook.Func {
+	return h.allowBreakGlass(h.handler.OnUpdate(client, decoder))
+}
+
+func (h *breakGlassHandler) allowBreakGlass(fn webhook.Func) webhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		c := h.configuration.FromContext(ctx)
+		res := fn(configuration.NewContext(ctx, c), req)
+		if res.Allowed || !utils.UserGroupList(req.UserInfo.Groups).IsInCapsuleGroups(c.BreakGlassGroups) {
+			return res
+		}
+
+		var message string
+		if res.Result != nil {
+			if message = res.Result.Message; len(message) == 0 {
+				message = string(res.Result.Reason)
+			}
+		}
+		h.log.Info("Denied request allowed to the break-glass group", "user", req.UserInfo.Username, "operation", req.Operation,
+			"kind", req.Kind.Kind, "namespace", req.Namespace, "name", req.Name, "denial", message)
+		ref := &corev1.ObjectReference{
+			APIVersion: schema.GroupVersion{Group: req.Kind.Group, Version: req.Kind.Version}.String(),
+			Kind:       req.Kind.Kind,
+			Namespace:  req.Namespace,
+			Name:       req.Name,
+		}
+		h.recorder.Eventf(ref, corev1.EventTypeWarning, "BreakGlass", "%s by %s allowed to the break-glass group, although denied: %s", req.Operation, req.UserInfo.Username, message)
+
+		return webhook.BreakGlass(res)
+	}
+}
@@ -22,30 +22,39 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/clastix/capsule/pkg/configuration"
 	"github.com/clastix/capsule/pkg/utils"
 	"github.com/clastix/capsule/pkg/webhook"
 )
 
-func InCapsuleGroup(capsuleGroup string, webhookHandler webhook.Handler) webhook.Handler {
+// InCapsuleGroup applies the webhook handler to the members of the Capsule user groups only: the configuration
+// snapshot used to check the groups is bound to the request context, shared with the wrapped handler.
+func InCapsuleGroup(configuration *configuration.Store, webhookHandler webhook.Handler) webhook.Handler {
 	return &handler{
-		handler:      webhookHandler,
-		capsuleGroup: capsuleGroup,
+		handler:       webhookHandler,
+		configuration: configuration,
 	}
 }
 
 type handler struct {
-	capsuleGroup string
-	handler      webhook.Handler
+	configuration *configuration.Store
+	handler       webhook.Handler
 }
 
 // If the user performing action is not a Capsule user, can be skipped
-func (h handler) isCapsuleUser(req admission.Request) bool {
-	return utils.UserGroupList(req.UserInfo.Groups).IsInCapsuleGroup(h.capsuleGroup)
+func (h handler) isCapsuleUser(ctx context.Context, req admission.Request) bool {
+	return utils.UserGroupList(req.UserInfo.Groups).IsInCapsuleGroups(h.configuration.FromContext(ctx).UserGroups)
+}
+
+// withConfiguration binds the current configuration snapshot to the request context, unless already bound.
+func (h handler) withConfiguration(ctx context.Context) context.Context {
+	return configuration.NewContext(ctx, h.configuration.FromContext(ctx))
 }
 
 func (h *handler) OnCreate(client client.Client, decoder *admission.Decoder) webhook.Func {
 	return func(ctx context.Context, req admission.Request) admission.Response {
-		if !h.isCapsuleUser(req) {
+		ctx = h.withConfiguration(ctx)
+		if !h.isCapsuleUser(ctx, req) {
 			return admission.Allowed("")
 		}
 
@@ -55,7 +64,8 @@ func (h *handler) OnCreate(client client.Client, decoder *admission.Decoder) web
 
 func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) webhook.Func {
 	return func(ctx context.Context, req admission.Request) admission.Response {
-		if !h.isCapsuleUser(req) {
+		ctx = h.withConfiguration(ctx)
+		if !h.isCapsuleUser(ctx, req) {
 			return admission.Allowed("")
 		}
 		return h.handler.OnDelete(client, decoder)(ctx, req)
@@ -64,7 +74,8 @@ func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) web
 
 func (h *handler) OnUpdate(client client.Client, decoder *admission.Decoder) webhook.Func {
 	return func(ctx context.Context, req admission.Request) admission.Response {
-		if !h.isCapsuleUser(req) {
+		ctx = h.withConfiguration(ctx)
+		if !h.isCapsuleUser(ctx, req) {
 			return admission.Allowed("")
 		}
 		return h.handler.OnUpdate(client, decoder)(ctx, req)
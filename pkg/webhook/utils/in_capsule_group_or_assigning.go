@@ -0,0 +1,68 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	"github.com/clastix/capsule/pkg/configuration"
+	"github.com/clastix/capsule/pkg/utils"
+	"github.com/clastix/capsule/pkg/webhook"
+)
+
+// InCapsuleGroupOrAssigning applies the Namespace webhook handler to the Capsule users, as InCapsuleGroup does, and to
+// the members of the break-glass groups creating a Namespace with the Tenant assignment annotation, so the Namespaces
+// assigned by the cluster administrators go through the same checks as the ones created by the Tenant owners.
+func InCapsuleGroupOrAssigning(configuration *configuration.Store, webhookHandler webhook.Handler) webhook.Handler {
+	return &assigningHandler{
+		handler: &handler{
+			handler:       webhookHandler,
+			configuration: configuration,
+		},
+	}
+}
+
+type assigningHandler struct {
+	*handler
+}
+
+func (h *assigningHandler) OnCreate(client client.Client, decoder *admission.Decoder) webhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		ctx = h.withConfiguration(ctx)
+		if h.isCapsuleUser(ctx, req) {
+			return h.handler.handler.OnCreate(client, decoder)(ctx, req)
+		}
+		if !utils.UserGroupList(req.UserInfo.Groups).IsInCapsuleGroups(h.configuration.FromContext(ctx).BreakGlassGroups) {
+			return admission.Allowed("")
+		}
+
+		ns := &corev1.Namespace{}
+		if err := decoder.Decode(req, ns); err != nil {
+			return webhook.Errored(http.StatusBadRequest, err)
+		}
+		if _, ok := ns.GetAnnotations()[v1alpha1.TenantAssignmentAnnotation]; !ok {
+			return admission.Allowed("")
+		}
+		return h.handler.handler.OnCreate(client, decoder)(ctx, req)
+	}
+}
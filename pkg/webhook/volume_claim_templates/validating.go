@@ -0,0 +1,117 @@
+/*
+Copyright 2020 Clastix Labs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume_claim_templates
+
+import (
+	"context"
+	"net/http"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/clastix/capsule/api/v1alpha1"
+	capsulewebhook "github.com/clastix/capsule/pkg/webhook"
+	"github.com/clastix/capsule/pkg/webhook/pvc"
+)
+
+// +kubebuilder:webhook:path=/validating-v1-statefulset-volume-claim-templates,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps,resources=statefulsets,verbs=create;update,versions=v1,name=volume-claim-templates.statefulset.capsule.clastix.io
+
+type webhook struct {
+	handler capsulewebhook.Handler
+}
+
+func Webhook(handler capsulewebhook.Handler) capsulewebhook.Webhook {
+	return &webhook{handler: handler}
+}
+
+func (w *webhook) GetName() string {
+	return "VolumeClaimTemplates"
+}
+
+func (w *webhook) GetPath() string {
+	return "/validating-v1-statefulset-volume-claim-templates"
+}
+
+func (w *webhook) GetHandler() capsulewebhook.Handler {
+	return w.handler
+}
+
+type handler struct {
+}
+
+// Handler validates the Storage Classes of the StatefulSet volume claim templates, since the PVCs are generated by the
+// StatefulSet controller on behalf of the Tenant users, bypassing the PVC validation.
+func Handler() capsulewebhook.Handler {
+	return &handler{}
+}
+
+func (h *handler) OnCreate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return h.validate(ctx, c, decoder, req, nil)
+	}
+}
+
+func (h *handler) OnDelete(client client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		return admission.Allowed("")
+	}
+}
+
+func (h *handler) OnUpdate(c client.Client, decoder *admission.Decoder) capsulewebhook.Func {
+	return func(ctx context.Context, req admission.Request) admission.Response {
+		old := &appsv1.StatefulSet{}
+		if err := decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return capsulewebhook.Errored(http.StatusBadRequest, err)
+		}
+		return h.validate(ctx, c, decoder, req, old)
+	}
+}
+
+// validate checks the volume claim templates against the Tenant Storage Classes: upon update, they're checked only if
+// changed, so the StatefulSets predating a stricter policy can still be scaled or updated.
+func (h *handler) validate(ctx context.Context, c client.Client, decoder *admission.Decoder, req admission.Request, old *appsv1.StatefulSet) admission.Response {
+	sts := &appsv1.StatefulSet{}
+	if err := decoder.Decode(req, sts); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
+	}
+
+	if old != nil && equality.Semantic.DeepEqual(old.Spec.VolumeClaimTemplates, sts.Spec.VolumeClaimTemplates) {
+		return admission.Allowed("")
+	}
+
+	tl := &v1alpha1.TenantList{}
+	if err := c.List(ctx, tl, client.MatchingFieldsSelector{
+		Selector: fields.OneTermEqualSelector(".status.namespaces", req.Namespace),
+	}); err != nil {
+		return capsulewebhook.Errored(http.StatusBadRequest, err)
+	}
+
+	// the Namespace doesn't belong to any Tenant
+	if len(tl.Items) == 0 {
+		return admission.Allowed("")
+	}
+
+	for _, t := range sts.Spec.VolumeClaimTemplates {
+		if res := pvc.ValidateStorageClass(ctx, c, tl.Items[0].Spec.StorageClasses, t.Spec.StorageClassName); !res.Allowed {
+			return res
+		}
+	}
+	return admission.Allowed("")
+}